@@ -2,13 +2,22 @@ package main
 
 import (
 	"context"
-	"log"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
 	"os"
 	"os/signal"
+	"syscall"
 	"time"
 
+	"github.com/Xausdorf/mattermost-poll/internal/config"
 	"github.com/Xausdorf/mattermost-poll/internal/gateway/bot"
+	"github.com/Xausdorf/mattermost-poll/internal/gateway/monitoring"
+	"github.com/Xausdorf/mattermost-poll/internal/logging"
+	"github.com/Xausdorf/mattermost-poll/internal/metrics"
 	"github.com/Xausdorf/mattermost-poll/internal/repository/ttadapter"
+	"github.com/Xausdorf/mattermost-poll/internal/tracing"
 	"github.com/Xausdorf/mattermost-poll/internal/usecase"
 	"github.com/tarantool/go-tarantool/v2"
 	_ "github.com/tarantool/go-tarantool/v2/datetime"
@@ -16,80 +25,185 @@ import (
 	_ "github.com/tarantool/go-tarantool/v2/uuid"
 )
 
-type tarantoolConfig struct {
-	address  string
-	user     string
-	password string
-}
+const tracingShutdownTimeout = 5 * time.Second
 
+// Exit codes of the process.
 const (
-	ttReconnectSeconds = 3
-	ttMaxRecconects    = 5
+	exitOK = 0
+	// exitFailure - the bot could not start or stopped because of an error.
+	exitFailure = 1
+	// exitInvalidConfig - config could not be loaded, restarting won't help.
+	exitInvalidConfig = 2
+	// exitShutdownTimeout - the bot was stopped, but some commands were interrupted.
+	exitShutdownTimeout = 3
 )
 
 func main() {
-	ctx := context.Background()
+	os.Exit(runMain())
+}
+
+func runMain() int {
+	configPath := flag.String("config", os.Getenv("CONFIG_FILE"), "path to YAML or TOML config file")
+	flag.Parse()
 
-	ttCfg := loadTarantoolConfig()
-	conn, err := connectTarantool(ctx, ttCfg)
+	var logLevel slog.LevelVar
+	logger := logging.New(os.Stdout, &logLevel)
+
+	cfg, err := config.Load(*configPath)
 	if err != nil {
-		log.Fatalf("Connection to tarantool refused: %v", err)
+		logger.Error("Could not load config", "error", err)
+		return exitInvalidConfig
+	}
+	logLevel.Set(cfg.LogLevel())
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	err = run(ctx, stop, cfg, *configPath, &logLevel, logger)
+	switch {
+	case errors.Is(err, bot.ErrShutdownTimeout):
+		logger.Error("Polling bot stopped", "error", err)
+		return exitShutdownTimeout
+	case err != nil:
+		logger.Error("Polling bot stopped", "error", err)
+		return exitFailure
 	}
-	log.Println("Succesfully connected to tarantool")
+	logger.Info("Polling bot stopped")
+	return exitOK
+}
 
-	pollRepo := ttadapter.NewPollRepository(conn)
-	answerRepo := ttadapter.NewAnswerRepository(conn)
+// run starts the bot and blocks until ctx is done or the bot fails, then shuts everything down.
+// stopSignals restores default handling of signals, so the next signal kills the process without waiting.
+func run(
+	ctx context.Context,
+	stopSignals context.CancelFunc,
+	cfg *config.Config,
+	configPath string,
+	logLevel *slog.LevelVar,
+	logger *slog.Logger,
+) error {
+	appTracing, err := tracing.New(ctx, cfg.TracingConfig())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), tracingShutdownTimeout)
+		defer cancel()
+		if err := appTracing.Shutdown(shutdownCtx); err != nil {
+			logger.Warn("Could not flush traces", "error", err)
+		}
+	}()
 
-	pollService := usecase.NewPoll(pollRepo, answerRepo)
+	conn, err := connectTarantool(ctx, cfg.Tarantool)
+	if err != nil {
+		return fmt.Errorf("connection to tarantool refused: %w", err)
+	}
+	defer func() {
+		if err := conn.CloseGraceful(); err != nil {
+			logger.Warn("Could not close tarantool connection", "error", err)
+			return
+		}
+		logger.Info("Tarantool connection closed")
+	}()
+	logger.InfoContext(ctx, "Succesfully connected to tarantool", "address", cfg.Tarantool.Address)
+
+	appMetrics := metrics.New()
+
+	doer := appTracing.InstrumentDoer(appMetrics.InstrumentDoer(conn))
+	pollRepo := ttadapter.NewPollRepository(doer, logger)
+	answerRepo := ttadapter.NewAnswerRepository(doer, logger)
+	dndRepo := ttadapter.NewDoNotDisturbRepository(doer, logger)
+	eventRepo := ttadapter.NewEventRepository(doer, logger)
+	suggestionRepo := ttadapter.NewSuggestionRepository(doer, logger)
+	surveyRepo := ttadapter.NewSurveyRepository(doer, logger)
+	responseRepo := ttadapter.NewSurveyResponseRepository(doer, logger)
+	textAnswerRepo := ttadapter.NewTextAnswerRepository(doer, logger)
+
+	pollUsecase := usecase.NewPoll(
+		pollRepo, answerRepo, dndRepo, eventRepo, suggestionRepo, surveyRepo, responseRepo, textAnswerRepo,
+		cfg.PollConfig(), logger,
+	)
+	pollService := appTracing.InstrumentPollService(appMetrics.InstrumentPollService(pollUsecase))
+
+	pollingBot, err := bot.NewPollingBot(cfg.BotConfig(), pollService, appMetrics, appTracing.TracerProvider(), logger)
+	if err != nil {
+		return err
+	}
 
-	botConfig := bot.LoadConfig()
-	pollingBot := bot.NewPollingBot(botConfig, pollService)
-	setupGracefulShutdown(pollingBot)
+	monitoringServer := monitoring.NewServer(cfg.HTTP.Addr, logger)
+	monitoringServer.Handle("/metrics", appMetrics.Handler())
+	monitoringServer.Handle("/healthz", monitoringServer.HealthHandler(
+		monitoring.Check{Name: "websocket", Check: pollingBot.CheckAlive},
+	))
+	monitoringServer.Handle("/readyz", monitoringServer.HealthHandler(
+		monitoring.Check{Name: "websocket", Check: pollingBot.CheckWebSocket},
+		monitoring.Check{Name: "tarantool", Check: pingTarantool(conn)},
+		monitoring.Check{Name: "mattermost", Check: pollingBot.CheckMattermost},
+	))
+	monitoringServer.Start()
+	setupConfigReload(configPath, cfg, logger, func(cfg *config.Config) error {
+		logLevel.Set(cfg.LogLevel())
+		pollUsecase.SetConfig(cfg.PollConfig())
+		return pollingBot.UpdateSettings(cfg.BotSettings())
+	})
+
+	listenErr := pollingBot.Listen(ctx)
+	stopSignals()
+	logger.Info("Shutting down", "timeout", cfg.Shutdown.Timeout)
+
+	// the websocket is closed at this point, wait for commands which are already running
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Shutdown.Timeout)
+	defer cancel()
+	shutdownErr := pollingBot.Shutdown(shutdownCtx)
+	if err := monitoringServer.Shutdown(shutdownCtx); err != nil {
+		logger.Warn("Could not stop monitoring server", "error", err)
+	}
 
-	pollingBot.Listen(ctx)
+	return errors.Join(listenErr, shutdownErr)
 }
 
-func setupGracefulShutdown(bot *bot.PollingBot) {
+// setupConfigReload reloads the config on SIGHUP and applies settings which don't require reconnecting.
+// Invalid config is reported and the current one is kept.
+func setupConfigReload(path string, current *config.Config, logger *slog.Logger, apply func(*config.Config) error) {
 	c := make(chan os.Signal, 1)
-	signal.Notify(c, os.Interrupt)
+	signal.Notify(c, syscall.SIGHUP)
 	go func() {
 		for range c {
-			bot.Close()
-			log.Println("Shutting down")
-			os.Exit(0)
+			cfg, err := config.Load(path)
+			if err != nil {
+				logger.Error("Could not reload config, keeping the current one", "error", err)
+				continue
+			}
+			if !cfg.SameConnection(current) {
+				logger.Warn("Connection settings changed, they will be applied after restart")
+			}
+			if err = apply(cfg); err != nil {
+				logger.Error("Could not apply reloaded config", "error", err)
+				continue
+			}
+			current = cfg
+			logger.Info("Config reloaded")
 		}
 	}()
 }
 
-func loadTarantoolConfig() tarantoolConfig {
-	var cfg tarantoolConfig
-
-	cfg.address = os.Getenv("TT_ADDRESS")
-	if cfg.address == "" {
-		cfg.address = "127.0.0.1:3301"
-	}
-	cfg.user = os.Getenv("TT_USER")
-	if cfg.user == "" {
-		log.Fatal("Tarantool user is not set")
+func pingTarantool(conn *tarantool.Connection) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		_, err := conn.Do(tarantool.NewPingRequest().Context(ctx)).Get()
+		return err
 	}
-	cfg.password = os.Getenv("TT_PASSWORD")
-	if cfg.password == "" {
-		log.Fatal("Tarantool password is not set")
-	}
-
-	return cfg
 }
 
-func connectTarantool(ctx context.Context, cfg tarantoolConfig) (*tarantool.Connection, error) {
+func connectTarantool(ctx context.Context, cfg config.Tarantool) (*tarantool.Connection, error) {
 	dialer := tarantool.NetDialer{
-		Address:  cfg.address,
-		User:     cfg.user,
-		Password: cfg.password,
+		Address:  cfg.Address,
+		User:     cfg.User,
+		Password: cfg.Password,
 	}
 	opts := tarantool.Opts{
-		Timeout:       time.Second,
-		Reconnect:     ttReconnectSeconds * time.Second,
-		MaxReconnects: ttMaxRecconects,
+		Timeout:       cfg.Timeout,
+		Reconnect:     cfg.ReconnectInterval,
+		MaxReconnects: uint(cfg.MaxReconnects),
 	}
 
 	return tarantool.Connect(ctx, dialer, opts)
@@ -6,10 +6,39 @@ box.space.polls:format({
     { name = 'Question', type = 'string' },
     { name = 'Options', type = 'array' },
     { name = 'IsActive', type = 'boolean' },
-    { name = 'Author', type = 'string' }
+    { name = 'Author', type = 'string' },
+    -- polls created before multi-team support don't have these fields
+    { name = 'TeamID', type = 'string', is_nullable = true },
+    { name = 'ChannelID', type = 'string', is_nullable = true },
+    { name = 'NotifyAuthor', type = 'boolean', is_nullable = true },
+    { name = 'RemindEvery', type = 'unsigned', is_nullable = true },
+    { name = 'RemindedAt', type = 'unsigned', is_nullable = true },
+    { name = 'QuorumCount', type = 'unsigned', is_nullable = true },
+    { name = 'QuorumPercent', type = 'unsigned', is_nullable = true },
+    { name = 'ClosesAt', type = 'unsigned', is_nullable = true },
+    { name = 'QuorumNotReached', type = 'boolean', is_nullable = true },
+    { name = 'ResultsVisibility', type = 'string', is_nullable = true },
+    { name = 'NextOptionID', type = 'unsigned', is_nullable = true },
+    { name = 'Suggestions', type = 'string', is_nullable = true },
+    { name = 'Type', type = 'string', is_nullable = true },
+    { name = 'ScaleMin', type = 'unsigned', is_nullable = true },
+    { name = 'ScaleMax', type = 'unsigned', is_nullable = true },
+    { name = 'Weighted', type = 'boolean', is_nullable = true },
+    -- weights of voters keyed by user ID, nil if weights are resolved at vote time
+    { name = 'Weights', type = 'map', is_nullable = true }
 })
 
 box.space.polls:create_index('primary', { parts = { 'ID' }, if_not_exists = true })
+box.space.polls:create_index('author_active', {
+    parts = { 'Author', 'IsActive' },
+    unique = false,
+    if_not_exists = true
+})
+box.space.polls:create_index('active', {
+    parts = { 'IsActive' },
+    unique = false,
+    if_not_exists = true
+})
 
 -- Creating answers space --
 box.schema.space.create('answers', { if_not_exists = true })
@@ -18,7 +47,12 @@ box.space.answers:format({
     { name = 'ID', type = 'string' },
     { name = 'UserID', type = 'string' },
     { name = 'PollID', type = 'string' },
-    { name = 'Vote', type = 'unsigned' }
+    -- named Vote before options had IDs, see migration option_ids
+    { name = 'OptionID', type = 'unsigned' },
+    -- scores of rating polls keyed by option ID, nil for other polls
+    { name = 'Scores', type = 'map', is_nullable = true },
+    -- weight of the vote at the time it was given, nil in answers saved before weighted votes
+    { name = 'Weight', type = 'unsigned', is_nullable = true }
 })
 
 box.space.answers:create_index('primary', { parts = { 'ID' }, if_not_exists = true })
@@ -27,3 +61,152 @@ box.space.answers:create_index('user_poll', {
     unique = true, 
     if_not_exists = true 
 })
+box.space.answers:create_index('poll', {
+    parts = { 'PollID' },
+    unique = false,
+    if_not_exists = true
+})
+
+-- Creating do_not_disturb space, users who don't want to receive reminders --
+box.schema.space.create('do_not_disturb', { if_not_exists = true })
+
+box.space.do_not_disturb:format({
+    { name = 'UserID', type = 'string' }
+})
+
+box.space.do_not_disturb:create_index('primary', { parts = { 'UserID' }, if_not_exists = true })
+
+-- Creating poll_events space, lifecycle of polls --
+box.schema.space.create('poll_events', { if_not_exists = true })
+
+box.space.poll_events:format({
+    { name = 'ID', type = 'string' },
+    { name = 'PollID', type = 'string' },
+    { name = 'Type', type = 'string' },
+    { name = 'UserID', type = 'string' },
+    { name = 'At', type = 'unsigned' },
+    { name = 'Details', type = 'string' }
+})
+
+box.space.poll_events:create_index('primary', { parts = { 'ID' }, if_not_exists = true })
+box.space.poll_events:create_index('poll', {
+    parts = { 'PollID' },
+    unique = false,
+    if_not_exists = true
+})
+
+-- Creating suggestions space, options of polls suggested by voters --
+box.schema.space.create('suggestions', { if_not_exists = true })
+
+box.space.suggestions:format({
+    { name = 'ID', type = 'string' },
+    { name = 'PollID', type = 'string' },
+    { name = 'UserID', type = 'string' },
+    { name = 'Text', type = 'string' },
+    { name = 'Status', type = 'string' },
+    { name = 'OptionID', type = 'unsigned' }
+})
+
+box.space.suggestions:create_index('primary', { parts = { 'ID' }, if_not_exists = true })
+box.space.suggestions:create_index('poll', {
+    parts = { 'PollID' },
+    unique = false,
+    if_not_exists = true
+})
+
+-- Creating surveys space, surveys with several questions --
+box.schema.space.create('surveys', { if_not_exists = true })
+
+box.space.surveys:format({
+    { name = 'ID', type = 'string' },
+    { name = 'Title', type = 'string' },
+    { name = 'Questions', type = 'array' },
+    { name = 'IsActive', type = 'boolean' },
+    { name = 'Author', type = 'string' },
+    { name = 'TeamID', type = 'string' },
+    { name = 'ChannelID', type = 'string' }
+})
+
+box.space.surveys:create_index('primary', { parts = { 'ID' }, if_not_exists = true })
+
+-- Creating survey_responses space, answers to surveys saved after every question --
+box.schema.space.create('survey_responses', { if_not_exists = true })
+
+box.space.survey_responses:format({
+    { name = 'ID', type = 'string' },
+    { name = 'SurveyID', type = 'string' },
+    { name = 'UserID', type = 'string' },
+    -- answers keyed by question ID
+    { name = 'Answers', type = 'map' },
+    { name = 'Completed', type = 'boolean' },
+    { name = 'UpdatedAt', type = 'unsigned' }
+})
+
+box.space.survey_responses:create_index('primary', { parts = { 'ID' }, if_not_exists = true })
+box.space.survey_responses:create_index('user_survey', {
+    parts = { 'UserID', 'SurveyID' },
+    unique = true,
+    if_not_exists = true
+})
+box.space.survey_responses:create_index('survey', {
+    parts = { 'SurveyID' },
+    unique = false,
+    if_not_exists = true
+})
+box.space.survey_responses:create_index('user_completed', {
+    parts = { 'UserID', 'Completed' },
+    unique = false,
+    if_not_exists = true
+})
+
+-- Creating text_answers space, answers to text questions of surveys stored apart from responses --
+box.schema.space.create('text_answers', { if_not_exists = true })
+
+box.space.text_answers:format({
+    { name = 'ID', type = 'string' },
+    { name = 'SurveyID', type = 'string' },
+    { name = 'QuestionID', type = 'unsigned' },
+    -- empty for anonymous questions
+    { name = 'UserID', type = 'string' },
+    { name = 'Text', type = 'string' },
+    { name = 'CreatedAt', type = 'unsigned' },
+    { name = 'Published', type = 'boolean' }
+})
+
+box.space.text_answers:create_index('primary', { parts = { 'ID' }, if_not_exists = true })
+box.space.text_answers:create_index('survey', {
+    parts = { 'SurveyID' },
+    unique = false,
+    if_not_exists = true
+})
+
+-- Creating migrations space, names of applied migrations of data --
+box.schema.space.create('migrations', { if_not_exists = true })
+
+box.space.migrations:format({
+    { name = 'Name', type = 'string' }
+})
+
+box.space.migrations:create_index('primary', { parts = { 'Name' }, if_not_exists = true })
+
+local function migrate(name, fn)
+    if box.space.migrations:get(name) ~= nil then
+        return
+    end
+    box.atomic(function()
+        fn()
+        box.space.migrations:insert({ name })
+    end)
+end
+
+-- Options get stable IDs. Answers referenced options by index, so existing options get their index as ID
+-- and existing answers keep referencing the same options.
+migrate('option_ids', function()
+    for _, poll in box.space.polls:pairs() do
+        local options = {}
+        for i, option in ipairs(poll.Options) do
+            options[i] = { ID = i - 1, Text = option.Text, Votes = option.Votes }
+        end
+        box.space.polls:update(poll.ID, { { '=', 3, options } })
+    end
+end)
@@ -0,0 +1,390 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/BurntSushi/toml"
+	"github.com/Xausdorf/mattermost-poll/internal/domain"
+	"github.com/Xausdorf/mattermost-poll/internal/gateway/bot"
+	"github.com/Xausdorf/mattermost-poll/internal/logging"
+	"github.com/Xausdorf/mattermost-poll/internal/tracing"
+	"github.com/Xausdorf/mattermost-poll/internal/usecase"
+	"gopkg.in/yaml.v3"
+)
+
+// Config - settings of the whole application, read from a YAML or TOML file and overridden by environment.
+type Config struct {
+	Mattermost Mattermost `yaml:"mattermost" toml:"mattermost"`
+	Tarantool  Tarantool  `yaml:"tarantool" toml:"tarantool"`
+	HTTP       HTTP       `yaml:"http" toml:"http"`
+	Shutdown   Shutdown   `yaml:"shutdown" toml:"shutdown"`
+	Log        Log        `yaml:"log" toml:"log"`
+	Tracing    Tracing    `yaml:"tracing" toml:"tracing"`
+	Polls      Polls      `yaml:"polls" toml:"polls"`
+	RateLimits RateLimits `yaml:"rate_limits" toml:"rate_limits"`
+	Locales    Locales    `yaml:"locales" toml:"locales"`
+	Commands   Commands   `yaml:"commands" toml:"commands"`
+	// Responses - visibility of responses by kind: created, results, confirmation, error, help.
+	Responses bot.ResponseVisibility `yaml:"responses" toml:"responses"`
+	Reminders Reminders              `yaml:"reminders" toml:"reminders"`
+	Weights   Weights                `yaml:"weights" toml:"weights"`
+	// Teams - settings of teams keyed by team name or ID, there are no environment variables for them.
+	Teams map[string]Team `yaml:"teams" toml:"teams"`
+
+	// parsed values, filled by validate
+	server            *url.URL
+	logLevel          slog.Level
+	traceExporter     string
+	userRateLimits    bot.RateLimits
+	channelRateLimits bot.RateLimits
+	channelLocales    map[string]string
+	reminderMode      bot.ReminderMode
+}
+
+type Mattermost struct {
+	Server   string `yaml:"server" toml:"server"`
+	UserName string `yaml:"username" toml:"username"`
+	Token    string `yaml:"token" toml:"token"`
+	// MaxRetries - count of websocket connection failures in a row after which the bot stops.
+	MaxRetries int `yaml:"max_retries" toml:"max_retries"`
+	// WebSocketGracePeriod - how long the websocket may be disconnected before liveness check fails.
+	WebSocketGracePeriod time.Duration `yaml:"websocket_grace_period" toml:"websocket_grace_period"`
+}
+
+type Tarantool struct {
+	Address  string `yaml:"address" toml:"address"`
+	User     string `yaml:"user" toml:"user"`
+	Password string `yaml:"password" toml:"password"`
+	// Timeout - timeout of a single request.
+	Timeout           time.Duration `yaml:"timeout" toml:"timeout"`
+	ReconnectInterval time.Duration `yaml:"reconnect_interval" toml:"reconnect_interval"`
+	MaxReconnects     int           `yaml:"max_reconnects" toml:"max_reconnects"`
+}
+
+type HTTP struct {
+	Addr string `yaml:"addr" toml:"addr"`
+}
+
+type Shutdown struct {
+	// Timeout - how long in-flight commands may take after SIGINT or SIGTERM.
+	Timeout time.Duration `yaml:"timeout" toml:"timeout"`
+}
+
+type Log struct {
+	Level string `yaml:"level" toml:"level"`
+}
+
+type Tracing struct {
+	Exporter string `yaml:"exporter" toml:"exporter"`
+}
+
+// Polls - limits of polls, 0 means no limit.
+type Polls struct {
+	MaxActivePerAuthor int `yaml:"max_active_per_author" toml:"max_active_per_author"`
+	MaxOptions         int `yaml:"max_options" toml:"max_options"`
+	MaxQuestionLength  int `yaml:"max_question_length" toml:"max_question_length"`
+	MaxOptionLength    int `yaml:"max_option_length" toml:"max_option_length"`
+	// MaxSuggestionsPerUser - how many options a single voter can suggest in a poll.
+	MaxSuggestionsPerUser int `yaml:"max_suggestions_per_user" toml:"max_suggestions_per_user"`
+	// MaxSurveyQuestions - how many questions a survey can have.
+	MaxSurveyQuestions int `yaml:"max_survey_questions" toml:"max_survey_questions"`
+	// MaxTextAnswerLength - how many characters an answer to a text question of a survey can have.
+	MaxTextAnswerLength int `yaml:"max_text_answer_length" toml:"max_text_answer_length"`
+}
+
+// RateLimits - limits of commands in the form "default=20/1m,poll_start=3/1m".
+type RateLimits struct {
+	User    string `yaml:"user" toml:"user"`
+	Channel string `yaml:"channel" toml:"channel"`
+}
+
+type Locales struct {
+	Default string `yaml:"default" toml:"default"`
+	// Channels - locales of channels keyed by channel ID, they take precedence over user's locale.
+	Channels map[string]string `yaml:"channels" toml:"channels"`
+}
+
+type Commands struct {
+	Prefix string `yaml:"prefix" toml:"prefix"`
+	// MentionOnly - the bot handles only commands starting with its mention, like "@pollingbot poll_vote".
+	MentionOnly bool `yaml:"mention_only" toml:"mention_only"`
+	// Aliases - additional names of commands, alias -> command, e.g. vote: poll_vote.
+	Aliases map[string]string `yaml:"aliases" toml:"aliases"`
+}
+
+type Reminders struct {
+	// Mode - mention members in the poll's channel or send them direct messages.
+	Mode string `yaml:"mode" toml:"mode"`
+}
+
+// Weights - weights of votes in polls started with --weighted, a voter gets the largest matching weight.
+type Weights struct {
+	// Groups - weights of members of Mattermost groups, keyed by group name or ID.
+	Groups map[string]int `yaml:"groups" toml:"groups"`
+	// Users - weights of users, keyed by username or user ID.
+	Users map[string]int `yaml:"users" toml:"users"`
+}
+
+// Team - settings of a single team.
+type Team struct {
+	// AllowedChannels - names or IDs of channels where the bot handles commands, empty means every channel.
+	AllowedChannels []string `yaml:"allowed_channels" toml:"allowed_channels"`
+	CommandPrefix   string   `yaml:"command_prefix" toml:"command_prefix"`
+	// MentionOnly - overrides mention_only of commands for the team, if it is set.
+	MentionOnly *bool `yaml:"mention_only" toml:"mention_only"`
+	// DefaultOptions - options of polls started with a question only.
+	DefaultOptions []string `yaml:"default_options" toml:"default_options"`
+}
+
+// Default returns the config used when neither file nor environment set a value.
+func Default() Config {
+	return Config{
+		Mattermost: Mattermost{
+			UserName:             "PollingBot",
+			MaxRetries:           5,
+			WebSocketGracePeriod: time.Minute,
+		},
+		Tarantool: Tarantool{
+			Address:           "127.0.0.1:3301",
+			Timeout:           time.Second,
+			ReconnectInterval: 3 * time.Second,
+			MaxReconnects:     5,
+		},
+		HTTP: HTTP{
+			Addr: ":8080",
+		},
+		Shutdown: Shutdown{
+			Timeout: 10 * time.Second,
+		},
+		Log: Log{
+			Level: "info",
+		},
+		Tracing: Tracing{
+			Exporter: tracing.ExporterNone,
+		},
+		Polls: Polls{
+			MaxActivePerAuthor:    10,
+			MaxOptions:            10,
+			MaxQuestionLength:     300,
+			MaxOptionLength:       100,
+			MaxSuggestionsPerUser: 3,
+			MaxSurveyQuestions:    20,
+			MaxTextAnswerLength:   1000,
+		},
+		RateLimits: RateLimits{
+			User:    "default=20/1m,poll_start=3/1m",
+			Channel: "default=60/1m,poll_start=10/1m",
+		},
+		Locales: Locales{
+			Default: "en",
+		},
+		Commands: Commands{
+			Prefix: "!",
+		},
+		Reminders: Reminders{
+			Mode: string(bot.ReminderMention),
+		},
+	}
+}
+
+// Load reads the file (may be empty), applies environment overrides and validates the result.
+// All problems are reported at once.
+func Load(path string) (*Config, error) {
+	cfg := Default()
+	if path != "" {
+		if err := cfg.readFile(path); err != nil {
+			return nil, err
+		}
+	}
+	if err := errors.Join(cfg.applyEnv(os.Getenv), cfg.validate()); err != nil {
+		return nil, fmt.Errorf("config is not valid:\n%w", err)
+	}
+	return &cfg, nil
+}
+
+// readFile decodes the file by its extension, unknown keys are errors so that typos are not ignored.
+func (c *Config) readFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("could not read config: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err = decoder.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("could not parse config %s: %w", path, err)
+		}
+	case ".toml":
+		meta, err := toml.Decode(string(data), c)
+		if err != nil {
+			return fmt.Errorf("could not parse config %s: %w", path, err)
+		}
+		if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+			return fmt.Errorf("unknown keys in config %s: %v", path, undecoded)
+		}
+	default:
+		return fmt.Errorf("config %s must be .yaml, .yml or .toml", path)
+	}
+	return nil
+}
+
+func (c *Config) validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+	var err error
+
+	c.server, err = url.Parse(c.Mattermost.Server)
+	check(err == nil && c.server.Host != "", "mattermost.server must be an absolute URL: %q", c.Mattermost.Server)
+	check(c.Mattermost.Token != "", "mattermost.token is not set")
+	check(c.Mattermost.MaxRetries > 0, "mattermost.max_retries must be positive")
+	check(c.Mattermost.WebSocketGracePeriod > 0, "mattermost.websocket_grace_period must be positive")
+
+	check(c.Tarantool.Address != "", "tarantool.address is not set")
+	check(c.Tarantool.User != "", "tarantool.user is not set")
+	check(c.Tarantool.Password != "", "tarantool.password is not set")
+	check(c.Tarantool.Timeout > 0, "tarantool.timeout must be positive")
+	check(c.Tarantool.ReconnectInterval > 0, "tarantool.reconnect_interval must be positive")
+	check(c.Tarantool.MaxReconnects >= 0, "tarantool.max_reconnects must not be negative")
+
+	check(c.HTTP.Addr != "", "http.addr is not set")
+	check(c.Shutdown.Timeout > 0, "shutdown.timeout must be positive")
+
+	c.logLevel, err = logging.ParseLevel(c.Log.Level)
+	check(err == nil, "log.level: %v", err)
+	c.traceExporter, err = tracing.ParseExporter(c.Tracing.Exporter)
+	check(err == nil, "tracing.exporter: %v", err)
+
+	check(c.Polls.MaxActivePerAuthor >= 0, "polls.max_active_per_author must not be negative")
+	check(c.Polls.MaxOptions >= 0, "polls.max_options must not be negative")
+	check(c.Polls.MaxQuestionLength >= 0, "polls.max_question_length must not be negative")
+	check(c.Polls.MaxOptionLength >= 0, "polls.max_option_length must not be negative")
+	check(c.Polls.MaxSuggestionsPerUser >= 0, "polls.max_suggestions_per_user must not be negative")
+	check(c.Polls.MaxSurveyQuestions >= 0, "polls.max_survey_questions must not be negative")
+	check(c.Polls.MaxTextAnswerLength >= 0, "polls.max_text_answer_length must not be negative")
+
+	c.userRateLimits, err = bot.ParseRateLimits(c.RateLimits.User)
+	check(err == nil, "rate_limits.user: %v", err)
+	c.channelRateLimits, err = bot.ParseRateLimits(c.RateLimits.Channel)
+	check(err == nil, "rate_limits.channel: %v", err)
+
+	c.channelLocales = make(map[string]string, len(c.Locales.Channels))
+	for channelID, locale := range c.Locales.Channels {
+		check(channelID != "" && locale != "", "locales.channels: channel ID and locale must not be empty")
+		c.channelLocales[channelID] = locale
+	}
+
+	check(c.Commands.Prefix != "" && !strings.ContainsFunc(c.Commands.Prefix, unicode.IsSpace),
+		"commands.prefix must not be empty or contain spaces: %q", c.Commands.Prefix)
+	for alias, name := range c.Commands.Aliases {
+		check(alias != "" && !strings.ContainsFunc(alias, unicode.IsSpace),
+			"commands.aliases: alias must not be empty or contain spaces: %q", alias)
+		check(name != "", "commands.aliases.%s: command is not set", alias)
+	}
+	err = c.Responses.Validate()
+	check(err == nil, "responses: %v", err)
+	c.reminderMode, err = bot.ParseReminderMode(c.Reminders.Mode)
+	check(err == nil, "reminders.mode: %v", err)
+	err = c.voteWeights().Validate()
+	check(err == nil, "weights: %v", err)
+	for name, team := range c.Teams {
+		check(!strings.ContainsFunc(team.CommandPrefix, unicode.IsSpace),
+			"teams.%s.command_prefix must not contain spaces: %q", name, team.CommandPrefix)
+		for _, option := range team.DefaultOptions {
+			check(strings.TrimSpace(option) != "", "teams.%s.default_options must not contain empty options", name)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// SameConnection returns true if settings which are applied only at startup are equal,
+// the rest of settings can be changed while the application is running.
+func (c *Config) SameConnection(other *Config) bool {
+	return c.Mattermost == other.Mattermost &&
+		c.Tarantool == other.Tarantool &&
+		c.HTTP == other.HTTP &&
+		c.Shutdown == other.Shutdown &&
+		c.Tracing == other.Tracing
+}
+
+func (c *Config) LogLevel() slog.Level {
+	return c.logLevel
+}
+
+func (c *Config) TracingConfig() tracing.Config {
+	return tracing.Config{Exporter: c.traceExporter}
+}
+
+func (c *Config) PollConfig() usecase.Config {
+	return usecase.Config{
+		MaxActivePollsPerAuthor: c.Polls.MaxActivePerAuthor,
+		PollLimits: domain.PollLimits{
+			MaxOptions:        c.Polls.MaxOptions,
+			MaxQuestionLength: c.Polls.MaxQuestionLength,
+			MaxOptionLength:   c.Polls.MaxOptionLength,
+		},
+		MaxSuggestionsPerUser: c.Polls.MaxSuggestionsPerUser,
+		MaxSurveyQuestions:    c.Polls.MaxSurveyQuestions,
+		MaxTextAnswerLength:   c.Polls.MaxTextAnswerLength,
+	}
+}
+
+func (c *Config) BotConfig() bot.Config {
+	return bot.Config{
+		UserName:             c.Mattermost.UserName,
+		Token:                c.Mattermost.Token,
+		Server:               c.server,
+		MaxRetries:           c.Mattermost.MaxRetries,
+		WebSocketGracePeriod: c.Mattermost.WebSocketGracePeriod,
+		Settings:             c.BotSettings(),
+	}
+}
+
+func (c *Config) BotSettings() bot.Settings {
+	return bot.Settings{
+		UserRateLimits:     c.userRateLimits,
+		ChannelRateLimits:  c.channelRateLimits,
+		DefaultLocale:      c.Locales.Default,
+		ChannelLocales:     c.channelLocales,
+		CommandPrefix:      c.Commands.Prefix,
+		MentionOnly:        c.Commands.MentionOnly,
+		Aliases:            c.Commands.Aliases,
+		Teams:              c.teamSettings(),
+		ResponseVisibility: c.Responses,
+		ReminderMode:       c.reminderMode,
+		VoteWeights:        c.voteWeights(),
+	}
+}
+
+func (c *Config) voteWeights() bot.VoteWeights {
+	return bot.VoteWeights{Groups: c.Weights.Groups, Users: c.Weights.Users}
+}
+
+func (c *Config) teamSettings() map[string]bot.TeamSettings {
+	teams := make(map[string]bot.TeamSettings, len(c.Teams))
+	for name, team := range c.Teams {
+		teams[name] = bot.TeamSettings{
+			AllowedChannels: team.AllowedChannels,
+			CommandPrefix:   team.CommandPrefix,
+			MentionOnly:     team.MentionOnly,
+			DefaultOptions:  team.DefaultOptions,
+		}
+	}
+	return teams
+}
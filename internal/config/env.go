@@ -0,0 +1,120 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Xausdorf/mattermost-poll/internal/gateway/bot"
+)
+
+// applyEnv overrides values of the config by environment variables which are set and not empty.
+func (c *Config) applyEnv(getenv func(string) string) error {
+	var errs []error
+	str := func(env string, dst *string) {
+		if value := getenv(env); value != "" {
+			*dst = value
+		}
+	}
+	integer := func(env string, dst *int) {
+		value := getenv(env)
+		if value == "" {
+			return
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s is not an integer: %q", env, value))
+			return
+		}
+		*dst = n
+	}
+	duration := func(env string, dst *time.Duration) {
+		value := getenv(env)
+		if value == "" {
+			return
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s is not a duration: %q", env, value))
+			return
+		}
+		*dst = d
+	}
+
+	str("MM_SERVER", &c.Mattermost.Server)
+	str("MM_USERNAME", &c.Mattermost.UserName)
+	str("MM_TOKEN", &c.Mattermost.Token)
+	integer("MM_MAX_RETRIES", &c.Mattermost.MaxRetries)
+	duration("MM_WEBSOCKET_GRACE_PERIOD", &c.Mattermost.WebSocketGracePeriod)
+
+	str("TT_ADDRESS", &c.Tarantool.Address)
+	str("TT_USER", &c.Tarantool.User)
+	str("TT_PASSWORD", &c.Tarantool.Password)
+	duration("TT_TIMEOUT", &c.Tarantool.Timeout)
+	duration("TT_RECONNECT_INTERVAL", &c.Tarantool.ReconnectInterval)
+	integer("TT_MAX_RECONNECTS", &c.Tarantool.MaxReconnects)
+
+	str("HTTP_ADDR", &c.HTTP.Addr)
+	duration("SHUTDOWN_TIMEOUT", &c.Shutdown.Timeout)
+	str("LOG_LEVEL", &c.Log.Level)
+	str("OTEL_TRACES_EXPORTER", &c.Tracing.Exporter)
+
+	integer("MAX_ACTIVE_POLLS_PER_AUTHOR", &c.Polls.MaxActivePerAuthor)
+	integer("MAX_POLL_OPTIONS", &c.Polls.MaxOptions)
+	integer("MAX_QUESTION_LENGTH", &c.Polls.MaxQuestionLength)
+	integer("MAX_OPTION_LENGTH", &c.Polls.MaxOptionLength)
+	integer("MAX_SUGGESTIONS_PER_USER", &c.Polls.MaxSuggestionsPerUser)
+	integer("MAX_SURVEY_QUESTIONS", &c.Polls.MaxSurveyQuestions)
+	integer("MAX_TEXT_ANSWER_LENGTH", &c.Polls.MaxTextAnswerLength)
+
+	str("RATE_LIMIT_USER", &c.RateLimits.User)
+	str("RATE_LIMIT_CHANNEL", &c.RateLimits.Channel)
+
+	str("DEFAULT_LOCALE", &c.Locales.Default)
+	if value := getenv("CHANNEL_LOCALES"); value != "" {
+		channels, err := bot.ParseChannelLocales(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("CHANNEL_LOCALES is not valid: %w", err))
+		} else {
+			c.Locales.Channels = channels
+		}
+	}
+
+	str("COMMAND_PREFIX", &c.Commands.Prefix)
+	if value := getenv("MENTION_ONLY"); value != "" {
+		mentionOnly, err := strconv.ParseBool(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("MENTION_ONLY is not a boolean: %q", value))
+		} else {
+			c.Commands.MentionOnly = mentionOnly
+		}
+	}
+	if value := getenv("COMMAND_ALIASES"); value != "" {
+		aliases, err := bot.ParseAliases(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("COMMAND_ALIASES is not valid: %w", err))
+		} else {
+			c.Commands.Aliases = aliases
+		}
+	}
+	str("REMINDER_MODE", &c.Reminders.Mode)
+	if value := getenv("VOTE_WEIGHTS"); value != "" {
+		weights, err := bot.ParseVoteWeights(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("VOTE_WEIGHTS is not valid: %w", err))
+		} else {
+			c.Weights = Weights{Groups: weights.Groups, Users: weights.Users}
+		}
+	}
+	if value := getenv("RESPONSE_VISIBILITY"); value != "" {
+		visibility, err := bot.ParseResponseVisibility(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("RESPONSE_VISIBILITY is not valid: %w", err))
+		} else {
+			c.Responses = visibility
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,35 @@
+package domain
+
+import "time"
+
+// PollEventType - kind of change in the lifecycle of a poll.
+type PollEventType string
+
+const (
+	PollCreated  PollEventType = "created"
+	PollClosed   PollEventType = "closed"
+	PollExpired  PollEventType = "expired"
+	PollReopened PollEventType = "reopened"
+	PollExtended PollEventType = "extended"
+	PollDeleted  PollEventType = "deleted"
+)
+
+// PollEvent - record of a change in the lifecycle of a poll.
+type PollEvent struct {
+	PollID string
+	Type   PollEventType
+	// UserID - ID of the user who made the change, empty for changes made by the bot itself.
+	UserID string
+	At     time.Time
+	// Details - additional information, like the new deadline of an extended poll.
+	Details string
+}
+
+func NewPollEvent(pollID string, eventType PollEventType, userID string) *PollEvent {
+	return &PollEvent{
+		PollID: pollID,
+		Type:   eventType,
+		UserID: userID,
+		At:     time.Now(),
+	}
+}
@@ -0,0 +1,127 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	ErrOptionNotFound   = errors.New("option not found")
+	ErrCannotMoveScores = errors.New("scores can not be moved to another option")
+)
+
+// Option returns the option with the ID.
+func (p *Poll) Option(id int) (*PollOption, error) {
+	i := p.optionIndex(id)
+	if i < 0 {
+		return nil, ErrOptionNotFound
+	}
+	return &p.Options[i], nil
+}
+
+// AddOption checks the text against limits and other options and appends an option with a new ID.
+func (p *Poll) AddOption(text string, limits PollLimits) (*PollOption, error) {
+	id := p.nextOptionID()
+	if err := p.ValidateNewOption(text, limits); err != nil {
+		return nil, err
+	}
+	p.Options = append(p.Options, PollOption{ID: id, Text: SanitizeText(text)})
+	p.NextOptionID = id + 1
+	return &p.Options[len(p.Options)-1], nil
+}
+
+// EditOption replaces the text of the option, its votes are kept.
+func (p *Poll) EditOption(id int, text string, limits PollLimits) error {
+	option, err := p.Option(id)
+	if err != nil {
+		return err
+	}
+	if err = p.validateOption(id, text, limits); err != nil {
+		return err
+	}
+	option.Text = SanitizeText(text)
+	return nil
+}
+
+// RemoveOption removes the option and returns it. If moveTo is not nil, votes of the removed option
+// are added to the option with this ID.
+func (p *Poll) RemoveOption(id int, moveTo *int) (PollOption, error) {
+	i := p.optionIndex(id)
+	if i < 0 {
+		return PollOption{}, ErrOptionNotFound
+	}
+	if len(p.Options) <= minPollOptions {
+		return PollOption{}, &ValidationError{Err: ErrTooFewOptions, Option: -1, Limit: minPollOptions}
+	}
+	removed := p.Options[i]
+	if moveTo != nil && p.Type == PollRating {
+		return PollOption{}, ErrCannotMoveScores
+	}
+	if moveTo != nil {
+		if *moveTo == id {
+			return PollOption{}, ErrOptionNotFound
+		}
+		target, err := p.Option(*moveTo)
+		if err != nil {
+			return PollOption{}, err
+		}
+		target.Votes += removed.Votes
+		target.Weight += removed.Weight
+	}
+	p.NextOptionID = p.nextOptionID()
+	p.Options = slices.Delete(p.Options, i, i+1)
+	return removed, nil
+}
+
+func (p *Poll) optionIndex(id int) int {
+	return slices.IndexFunc(p.Options, func(option PollOption) bool {
+		return option.ID == id
+	})
+}
+
+// OptionWithText returns the option whose text is the same as the given one, compared like duplicate options,
+// or nil if there is no such option.
+func (p *Poll) OptionWithText(text string) *PollOption {
+	key := optionKey(text)
+	for i := range p.Options {
+		if strings.ToLower(p.Options[i].Text) == key {
+			return &p.Options[i]
+		}
+	}
+	return nil
+}
+
+// optionKey returns the text of an option as it is compared with texts of saved options.
+func optionKey(text string) string {
+	return strings.ToLower(SanitizeText(normalizeSpaces(text)))
+}
+
+// nextOptionID returns ID for a new option. Polls saved before NextOptionID was introduced have it zero.
+func (p *Poll) nextOptionID() int {
+	next := p.NextOptionID
+	for _, option := range p.Options {
+		next = max(next, option.ID+1)
+	}
+	return next
+}
+
+// validateOption checks the text of the option with the ID against limits and texts of other options.
+// Texts of saved options are sanitized already, so the new text is compared after sanitizing too.
+func (p *Poll) validateOption(id int, text string, limits PollLimits) error {
+	text = normalizeSpaces(text)
+	if text == "" {
+		return &ValidationError{Err: ErrEmptyOption, Option: id}
+	}
+	if limits.MaxOptionLength > 0 && utf8.RuneCountInString(text) > limits.MaxOptionLength {
+		return &ValidationError{Err: ErrOptionTooLong, Option: id, Limit: limits.MaxOptionLength}
+	}
+	key := optionKey(text)
+	for _, option := range p.Options {
+		if option.ID != id && strings.ToLower(option.Text) == key {
+			return &ValidationError{Err: ErrDuplicateOption, Option: id}
+		}
+	}
+	return nil
+}
@@ -1,6 +1,10 @@
 package domain
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 // Poll - structure for storing information about poll.
 type Poll struct {
@@ -10,33 +14,89 @@ type Poll struct {
 	IsActive bool
 	// Author - ID of poll's author.
 	Author string
+	// TeamID - ID of the team where the poll was started, empty for direct messages.
+	TeamID string
+	// ChannelID - ID of the channel where the poll was started.
+	ChannelID string
+	// NotifyAuthor - the author asked for a direct message with results when the poll is closed.
+	NotifyAuthor bool
+	// RemindEvery - how often members of the channel who have not voted are reminded, 0 means never.
+	RemindEvery time.Duration
+	// RemindedAt - time of the last reminder, or of creation if there were no reminders yet.
+	RemindedAt time.Time
+	// Quorum - required participation, zero value means no quorum.
+	Quorum Quorum
+	// ClosesAt - when the poll is closed automatically, zero value means never.
+	ClosesAt time.Time
+	// QuorumNotReached - the poll was closed automatically without quorum, so its results are not valid.
+	QuorumNotReached bool
+	// ResultsVisibility - who can see votes and when, empty means always.
+	ResultsVisibility ResultsVisibility
+	// NextOptionID - ID of the next added option, so IDs of removed options are not reused.
+	NextOptionID int
+	// Suggestions - whether voters can suggest new options.
+	Suggestions SuggestionMode
+	// Type - how voters answer, choice by default.
+	Type PollType
+	// Scale - range of scores of a rating poll.
+	Scale Scale
+	// Weighted - votes count with weights of voters, see VoteWeight.
+	Weighted bool
+	// Weights - weights of voters keyed by user ID, set for this poll only. If empty, weights are resolved
+	// by the caller, e.g. from membership in groups.
+	Weights map[string]int
 }
 
 // PollOption - structure for storing poll's option and voters count.
 type PollOption struct {
+	// ID - number of the option, stays the same when other options are added or removed.
+	ID   int
 	Text string
 	// Votes - count of users, who voted for this option.
 	Votes int
+	// Weight - sum of weights of votes for this option, equal to Votes if every vote has weight 1.
+	// Missing in polls saved before weighted votes.
+	Weight int
+	// Scores - histogram of scores of a rating poll, total weight of voters for every score starting from Scale.Min.
+	Scores []int
 }
 
 // Answer - structure for connecting the user and his vote in the poll.
 type Answer struct {
 	UserID string
 	PollID string
-	// Vote - option number in the list of poll's options.
-	Vote int
+	// OptionID - ID of the chosen option.
+	OptionID int
+	// Scores - scores of a rating poll keyed by option ID, OptionID is not used then.
+	Scores map[int]int
+	// Weight - weight of the vote at the time it was given, 0 in answers saved before weighted votes.
+	Weight int
 }
 
 func NewPoll(question string, options []PollOption, author string) *Poll {
+	for i := range options {
+		options[i].ID = i
+	}
 	return &Poll{
-		ID:       uuid.NewString(),
-		Question: question,
-		Options:  options,
-		IsActive: true,
-		Author:   author,
+		ID:           uuid.NewString(),
+		Question:     question,
+		Options:      options,
+		IsActive:     true,
+		Author:       author,
+		NextOptionID: len(options),
 	}
 }
 
+// ReminderDue returns true if it is time to remind about the active poll.
+func (p *Poll) ReminderDue(now time.Time) bool {
+	return p.IsActive && p.RemindEvery > 0 && !now.Before(p.RemindedAt.Add(p.RemindEvery))
+}
+
+// Expired returns true if the active poll has to be closed automatically.
+func (p *Poll) Expired(now time.Time) bool {
+	return p.IsActive && !p.ClosesAt.IsZero() && !now.Before(p.ClosesAt)
+}
+
 func NewPollOption(text string) *PollOption {
 	return &PollOption{
 		Text: text,
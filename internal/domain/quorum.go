@@ -0,0 +1,41 @@
+package domain
+
+// Quorum - how many members of the channel have to vote for results of the poll to be valid.
+// Zero value means the poll has no quorum.
+type Quorum struct {
+	// Count - absolute count of voters.
+	Count int
+	// Percent - percentage of channel members, used if Count is 0.
+	Percent int
+}
+
+func (q Quorum) IsSet() bool {
+	return q.Count > 0 || q.Percent > 0
+}
+
+// Required returns count of voters required among members of the channel, rounded up.
+func (q Quorum) Required(members int) int {
+	if q.Count > 0 {
+		return q.Count
+	}
+	return (members*q.Percent + 99) / 100
+}
+
+// Voters returns count of users who voted in the poll.
+func (p *Poll) Voters() int {
+	voters := 0
+	for _, option := range p.Options {
+		if p.Type == PollRating {
+			// every voter rates every option, except options added after the vote
+			voters = max(voters, option.Votes)
+		} else {
+			voters += option.Votes
+		}
+	}
+	return voters
+}
+
+// QuorumReached returns true if the poll has no quorum or enough members of the channel voted.
+func (p *Poll) QuorumReached(members int) bool {
+	return !p.Quorum.IsSet() || p.Voters() >= p.Quorum.Required(members)
+}
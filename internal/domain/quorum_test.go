@@ -0,0 +1,89 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/Xausdorf/mattermost-poll/internal/domain"
+)
+
+func TestQuorumRequired(t *testing.T) {
+	tests := []struct {
+		name    string
+		quorum  domain.Quorum
+		members int
+		want    int
+		isSet   bool
+	}{
+		{name: "not set", quorum: domain.Quorum{}, members: 10, want: 0},
+		{name: "count", quorum: domain.Quorum{Count: 3}, members: 10, want: 3, isSet: true},
+		{name: "count above members", quorum: domain.Quorum{Count: 30}, members: 10, want: 30, isSet: true},
+		{name: "count wins over percent", quorum: domain.Quorum{Count: 3, Percent: 90}, members: 10, want: 3, isSet: true},
+		{name: "percent", quorum: domain.Quorum{Percent: 50}, members: 10, want: 5, isSet: true},
+		{name: "percent rounded up", quorum: domain.Quorum{Percent: 50}, members: 7, want: 4, isSet: true},
+		{name: "small percent", quorum: domain.Quorum{Percent: 1}, members: 7, want: 1, isSet: true},
+		{name: "whole channel", quorum: domain.Quorum{Percent: 100}, members: 7, want: 7, isSet: true},
+		{name: "no members", quorum: domain.Quorum{Percent: 50}, members: 0, want: 0, isSet: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.quorum.Required(tt.members); got != tt.want {
+				t.Errorf("Required(%d) = %d, want %d", tt.members, got, tt.want)
+			}
+			if got := tt.quorum.IsSet(); got != tt.isSet {
+				t.Errorf("IsSet() = %v, want %v", got, tt.isSet)
+			}
+		})
+	}
+}
+
+func TestPollQuorumReached(t *testing.T) {
+	choice := []domain.PollOption{{ID: 0, Votes: 2}, {ID: 1, Votes: 1}}
+	// every voter rates every option, the option added later has fewer votes
+	rating := []domain.PollOption{{ID: 0, Votes: 3}, {ID: 1, Votes: 3}, {ID: 2, Votes: 1}}
+	tests := []struct {
+		name       string
+		poll       domain.Poll
+		members    int
+		wantVoters int
+		want       bool
+	}{
+		{name: "no quorum", poll: domain.Poll{Options: choice}, members: 100, wantVoters: 3, want: true},
+		{name: "no votes and no quorum", poll: domain.Poll{Options: []domain.PollOption{{}, {}}}, want: true},
+		{name: "count reached", poll: domain.Poll{Options: choice, Quorum: domain.Quorum{Count: 3}}, wantVoters: 3, want: true},
+		{name: "count not reached", poll: domain.Poll{Options: choice, Quorum: domain.Quorum{Count: 4}}, wantVoters: 3},
+		{
+			name:       "percent reached",
+			poll:       domain.Poll{Options: choice, Quorum: domain.Quorum{Percent: 50}},
+			members:    6,
+			wantVoters: 3,
+			want:       true,
+		},
+		{
+			name:       "percent not reached",
+			poll:       domain.Poll{Options: choice, Quorum: domain.Quorum{Percent: 50}},
+			members:    7,
+			wantVoters: 3,
+		},
+		{
+			name:       "rating voters are not summed",
+			poll:       domain.Poll{Type: domain.PollRating, Options: rating, Quorum: domain.Quorum{Count: 4}},
+			wantVoters: 3,
+		},
+		{
+			name:       "rating reached",
+			poll:       domain.Poll{Type: domain.PollRating, Options: rating, Quorum: domain.Quorum{Count: 3}},
+			wantVoters: 3,
+			want:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.poll.Voters(); got != tt.wantVoters {
+				t.Errorf("Voters() = %d, want %d", got, tt.wantVoters)
+			}
+			if got := tt.poll.QuorumReached(tt.members); got != tt.want {
+				t.Errorf("QuorumReached(%d) = %v, want %v", tt.members, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidScale = errors.New("invalid scale of scores")
+	ErrInvalidScore = errors.New("score is out of scale")
+)
+
+const (
+	// maxScaleSize - the largest count of different scores, so histograms stay readable.
+	maxScaleSize = 11
+	// promoterShare and detractorShare - positions on the scale where promoters start and detractors end,
+	// on the 0-10 scale of NPS promoters give 9-10 and detractors give 0-6.
+	promoterShare  = 0.9
+	detractorShare = 0.6
+)
+
+// PollType - how voters answer the poll.
+type PollType string
+
+const (
+	// PollChoice - every voter chooses a single option.
+	PollChoice PollType = ""
+	// PollRating - every voter gives every option a score.
+	PollRating PollType = "rating"
+)
+
+// Scale - range of scores of a rating poll, both ends included.
+type Scale struct {
+	Min int
+	Max int
+}
+
+var (
+	// DefaultScale - scale of ratings like 1-5 stars.
+	DefaultScale = Scale{Min: 1, Max: 5}
+	// NPSScale - scale of Net Promoter Score.
+	NPSScale = Scale{Min: 0, Max: 10}
+)
+
+// ParseScale parses a range like "1-5", "nps" means NPSScale and empty string means DefaultScale.
+func ParseScale(s string) (Scale, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	switch s {
+	case "":
+		return DefaultScale, nil
+	case "nps":
+		return NPSScale, nil
+	}
+	minStr, maxStr, ok := strings.Cut(s, "-")
+	if !ok {
+		return Scale{}, fmt.Errorf("scale %q must be a range like 1-5: %w", s, ErrInvalidScale)
+	}
+	lo, err := strconv.Atoi(minStr)
+	if err != nil {
+		return Scale{}, fmt.Errorf("scale %q must be a range like 1-5: %w", s, ErrInvalidScale)
+	}
+	hi, err := strconv.Atoi(maxStr)
+	if err != nil {
+		return Scale{}, fmt.Errorf("scale %q must be a range like 1-5: %w", s, ErrInvalidScale)
+	}
+	scale := Scale{Min: lo, Max: hi}
+	if !scale.Valid() {
+		return Scale{}, fmt.Errorf("scale %q: %w", s, ErrInvalidScale)
+	}
+	return scale, nil
+}
+
+// Valid returns true if the scale has from 2 to maxScaleSize non-negative scores.
+func (s Scale) Valid() bool {
+	return s.Min >= 0 && s.Max > s.Min && s.Size() <= maxScaleSize
+}
+
+// Size returns count of different scores.
+func (s Scale) Size() int {
+	return s.Max - s.Min + 1
+}
+
+func (s Scale) Contains(score int) bool {
+	return score >= s.Min && score <= s.Max
+}
+
+// Rating - statistics of scores given to an option.
+type Rating struct {
+	// Count - count of given scores, or their total weight in a weighted poll.
+	Count  int
+	Mean   float64
+	Median float64
+	// Histogram - count of voters, or their total weight, for every score of the scale, starting from Min.
+	Histogram  []int
+	Promoters  int
+	Passives   int
+	Detractors int
+}
+
+// NPS returns percentage of promoters minus percentage of detractors, from -100 to 100.
+func (r Rating) NPS() int {
+	if r.Count == 0 {
+		return 0
+	}
+	return (r.Promoters - r.Detractors) * 100 / r.Count
+}
+
+// Rating returns statistics of scores given to the option on the scale.
+func (o *PollOption) Rating(scale Scale) Rating {
+	return scale.Rating(o.Scores)
+}
+
+// Rating returns statistics of scores, histogram has count of voters for every score starting from Min.
+func (s Scale) Rating(histogram []int) Rating {
+	rating := Rating{Histogram: make([]int, s.Size())}
+	copy(rating.Histogram, histogram)
+
+	sum := 0
+	for i, count := range rating.Histogram {
+		score := s.Min + i
+		rating.Count += count
+		sum += score * count
+		switch share := float64(i) / float64(s.Size()-1); {
+		case share >= promoterShare:
+			rating.Promoters += count
+		case share <= detractorShare:
+			rating.Detractors += count
+		default:
+			rating.Passives += count
+		}
+	}
+	if rating.Count == 0 {
+		return rating
+	}
+	rating.Mean = float64(sum) / float64(rating.Count)
+	rating.Median = (rating.nth(s, (rating.Count-1)/2) + rating.nth(s, rating.Count/2)) / 2
+	return rating
+}
+
+// nth returns score at position n of sorted scores.
+func (r Rating) nth(scale Scale, n int) float64 {
+	for i, count := range r.Histogram {
+		if n < count {
+			return float64(scale.Min + i)
+		}
+		n -= count
+	}
+	return float64(scale.Max)
+}
+
+// Rate adds scores of a voter with the weight, keyed by option ID, to histograms of options.
+func (p *Poll) Rate(scores map[int]int, weight int) error {
+	if len(scores) != len(p.Options) {
+		return ErrInvalidScore
+	}
+	for id, score := range scores {
+		if p.optionIndex(id) < 0 {
+			return ErrOptionNotFound
+		}
+		if !p.Scale.Contains(score) {
+			return ErrInvalidScore
+		}
+	}
+	for i := range p.Options {
+		option := &p.Options[i]
+		if len(option.Scores) < p.Scale.Size() {
+			option.Scores = append(option.Scores, make([]int, p.Scale.Size()-len(option.Scores))...)
+		}
+		option.Scores[scores[option.ID]-p.Scale.Min] += weight
+		option.Votes++
+		option.Weight += weight
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package domain_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/Xausdorf/mattermost-poll/internal/domain"
+)
+
+func TestParseScale(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    domain.Scale
+		wantErr bool
+	}{
+		{in: "", want: domain.DefaultScale},
+		{in: "nps", want: domain.NPSScale},
+		{in: " NPS ", want: domain.NPSScale},
+		{in: "1-10", want: domain.Scale{Min: 1, Max: 10}},
+		{in: "0-1", want: domain.Scale{Min: 0, Max: 1}},
+		{in: "0-10", want: domain.NPSScale},
+		{in: "5", wantErr: true},
+		{in: "a-5", wantErr: true},
+		{in: "1-b", wantErr: true},
+		{in: "5-1", wantErr: true},
+		{in: "3-3", wantErr: true},
+		{in: "1-12", wantErr: true},
+		{in: "-1-5", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := domain.ParseScale(tt.in)
+			if tt.wantErr {
+				if !errors.Is(err, domain.ErrInvalidScale) {
+					t.Errorf("ParseScale(%q) error = %v, want %v", tt.in, err, domain.ErrInvalidScale)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("ParseScale(%q) = %v, %v, want %v", tt.in, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestScaleRating(t *testing.T) {
+	tests := []struct {
+		name      string
+		scale     domain.Scale
+		histogram []int
+		want      domain.Rating
+		wantNPS   int
+	}{
+		{
+			name:  "no scores",
+			scale: domain.DefaultScale,
+			want:  domain.Rating{Histogram: []int{0, 0, 0, 0, 0}},
+		},
+		{
+			name:      "even count",
+			scale:     domain.DefaultScale,
+			histogram: []int{1, 0, 0, 1, 2},
+			want: domain.Rating{
+				Count: 4, Mean: 3.75, Median: 4.5, Histogram: []int{1, 0, 0, 1, 2},
+				Promoters: 2, Passives: 1, Detractors: 1,
+			},
+			wantNPS: 25,
+		},
+		{
+			name:      "odd count",
+			scale:     domain.DefaultScale,
+			histogram: []int{0, 1, 1, 1, 0},
+			want: domain.Rating{
+				Count: 3, Mean: 3, Median: 3, Histogram: []int{0, 1, 1, 1, 0},
+				Passives: 1, Detractors: 2,
+			},
+			wantNPS: -66,
+		},
+		{
+			name:      "short histogram is padded",
+			scale:     domain.DefaultScale,
+			histogram: []int{2},
+			want:      domain.Rating{Count: 2, Mean: 1, Median: 1, Histogram: []int{2, 0, 0, 0, 0}, Detractors: 2},
+			wantNPS:   -100,
+		},
+		{
+			name:      "net promoter score",
+			scale:     domain.NPSScale,
+			histogram: []int{1, 0, 0, 0, 0, 0, 1, 1, 0, 1, 1},
+			want: domain.Rating{
+				Count: 5, Mean: 6.4, Median: 7, Histogram: []int{1, 0, 0, 0, 0, 0, 1, 1, 0, 1, 1},
+				Promoters: 2, Passives: 1, Detractors: 2,
+			},
+		},
+		{
+			name:      "only promoters",
+			scale:     domain.NPSScale,
+			histogram: []int{0, 0, 0, 0, 0, 0, 0, 0, 0, 3, 1},
+			want: domain.Rating{
+				Count: 4, Mean: 9.25, Median: 9, Histogram: []int{0, 0, 0, 0, 0, 0, 0, 0, 0, 3, 1},
+				Promoters: 4,
+			},
+			wantNPS: 100,
+		},
+		{
+			name:      "scale not starting from zero",
+			scale:     domain.Scale{Min: 3, Max: 4},
+			histogram: []int{1, 1},
+			want:      domain.Rating{Count: 2, Mean: 3.5, Median: 3.5, Histogram: []int{1, 1}, Promoters: 1, Detractors: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.scale.Rating(tt.histogram)
+			if !equalRatings(got, tt.want) {
+				t.Errorf("Rating(%v) = %+v, want %+v", tt.histogram, got, tt.want)
+			}
+			if nps := got.NPS(); nps != tt.wantNPS {
+				t.Errorf("NPS() = %d, want %d", nps, tt.wantNPS)
+			}
+		})
+	}
+}
+
+func TestPollRate(t *testing.T) {
+	newPoll := func() *domain.Poll {
+		return &domain.Poll{
+			Type:    domain.PollRating,
+			Scale:   domain.DefaultScale,
+			Options: []domain.PollOption{{ID: 1, Text: "a"}, {ID: 3, Text: "b"}},
+		}
+	}
+	tests := []struct {
+		name    string
+		scores  map[int]int
+		wantErr error
+	}{
+		{name: "missing option", scores: map[int]int{1: 5}, wantErr: domain.ErrInvalidScore},
+		{name: "unknown option", scores: map[int]int{1: 5, 2: 4}, wantErr: domain.ErrOptionNotFound},
+		{name: "score below scale", scores: map[int]int{1: 0, 3: 4}, wantErr: domain.ErrInvalidScore},
+		{name: "score above scale", scores: map[int]int{1: 5, 3: 6}, wantErr: domain.ErrInvalidScore},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			poll := newPoll()
+			if err := poll.Rate(tt.scores, 1); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Rate(%v) error = %v, want %v", tt.scores, err, tt.wantErr)
+			}
+			for _, option := range poll.Options {
+				if option.Votes != 0 || option.Scores != nil {
+					t.Errorf("option %d changed by rejected scores: %+v", option.ID, option)
+				}
+			}
+		})
+	}
+
+	t.Run("scores are added to histograms", func(t *testing.T) {
+		poll := newPoll()
+		for _, scores := range []map[int]int{{1: 5, 3: 1}, {1: 4, 3: 1}} {
+			if err := poll.Rate(scores, 1); err != nil {
+				t.Fatalf("Rate(%v) error: %v", scores, err)
+			}
+		}
+		want := [][]int{{0, 0, 0, 1, 1}, {2, 0, 0, 0, 0}}
+		for i, option := range poll.Options {
+			if !slices.Equal(option.Scores, want[i]) || option.Votes != 2 {
+				t.Errorf("option %d: scores %v, votes %d, want %v, 2", option.ID, option.Scores, option.Votes, want[i])
+			}
+		}
+		if voters := poll.Voters(); voters != 2 {
+			t.Errorf("Voters() = %d, want 2", voters)
+		}
+	})
+}
+
+func equalRatings(a, b domain.Rating) bool {
+	return a.Count == b.Count && a.Mean == b.Mean && a.Median == b.Median && slices.Equal(a.Histogram, b.Histogram) &&
+		a.Promoters == b.Promoters && a.Passives == b.Passives && a.Detractors == b.Detractors
+}
@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// SuggestionMode - whether voters can suggest new options of the poll.
+type SuggestionMode string
+
+const (
+	// SuggestionsOff - only the author can add options.
+	SuggestionsOff SuggestionMode = ""
+	// SuggestionsOpen - suggested options are added immediately.
+	SuggestionsOpen SuggestionMode = "open"
+	// SuggestionsApproval - suggested options are added after the author approves them.
+	SuggestionsApproval SuggestionMode = "approval"
+)
+
+// ParseSuggestionMode parses mode of suggestions, "off" or empty string means SuggestionsOff.
+func ParseSuggestionMode(s string) (SuggestionMode, error) {
+	switch mode := SuggestionMode(strings.ToLower(strings.TrimSpace(s))); mode {
+	case "off", SuggestionsOff:
+		return SuggestionsOff, nil
+	case SuggestionsOpen, SuggestionsApproval:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown suggestion mode %q, must be off, %s or %s", s, SuggestionsOpen, SuggestionsApproval)
+	}
+}
+
+// SuggestionStatus - state of a suggested option.
+type SuggestionStatus string
+
+const (
+	SuggestionPending  SuggestionStatus = "pending"
+	SuggestionApproved SuggestionStatus = "approved"
+	SuggestionRejected SuggestionStatus = "rejected"
+)
+
+// Suggestion - option of the poll suggested by a voter.
+type Suggestion struct {
+	ID     string
+	PollID string
+	// UserID - ID of the user who suggested the option.
+	UserID string
+	// Text - text of the option as it was written, it is sanitized when the option is added.
+	Text   string
+	Status SuggestionStatus
+	// OptionID - ID of the added option, if the suggestion is approved.
+	OptionID int
+}
+
+func NewSuggestion(pollID string, userID string, text string) *Suggestion {
+	return &Suggestion{
+		ID:     uuid.NewString(),
+		PollID: pollID,
+		UserID: userID,
+		Text:   normalizeSpaces(text),
+		Status: SuggestionPending,
+	}
+}
+
+// ValidateNewOption checks the text of an option which could be added to the poll.
+func (p *Poll) ValidateNewOption(text string, limits PollLimits) error {
+	if limits.MaxOptions > 0 && len(p.Options) >= limits.MaxOptions {
+		return &ValidationError{Err: ErrTooManyOptions, Option: -1, Limit: limits.MaxOptions}
+	}
+	return p.validateOption(p.nextOptionID(), text, limits)
+}
+
+// SameOptionText returns true if texts are equal after sanitizing, ignoring case.
+func SameOptionText(a string, b string) bool {
+	return strings.EqualFold(SanitizeText(a), SanitizeText(b))
+}
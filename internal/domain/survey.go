@@ -0,0 +1,357 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrEmptyTitle          = errors.New("title is empty")
+	ErrTitleTooLong        = errors.New("title is too long")
+	ErrTooFewQuestions     = errors.New("too few questions")
+	ErrTooManyQuestions    = errors.New("too many questions")
+	ErrOptionsNotAllowed   = errors.New("question of this type can not have options")
+	ErrUnknownQuestionType = errors.New("unknown question type")
+	ErrInvalidAnswer       = errors.New("answer does not match the question")
+	ErrAnswerTooLong       = errors.New("answer is too long")
+)
+
+// QuestionType - how respondents answer a question of a survey.
+type QuestionType string
+
+const (
+	// QuestionSingle - a single option is chosen.
+	QuestionSingle QuestionType = "single"
+	// QuestionMulti - one or several options are chosen.
+	QuestionMulti QuestionType = "multi"
+	// QuestionRating - the question itself is given a score on the scale.
+	QuestionRating QuestionType = "rating"
+	// QuestionText - the answer is a free text, stored separately from the response, see TextAnswer.
+	QuestionText QuestionType = "text"
+)
+
+// ParseQuestionType parses type of a question, case-insensitively.
+func ParseQuestionType(s string) (QuestionType, error) {
+	switch t := QuestionType(strings.ToLower(strings.TrimSpace(s))); t {
+	case QuestionSingle, QuestionMulti, QuestionRating, QuestionText:
+		return t, nil
+	default:
+		return "", fmt.Errorf("%w %q", ErrUnknownQuestionType, s)
+	}
+}
+
+// HasOptions returns true if respondents choose options to answer the question.
+func (t QuestionType) HasOptions() bool {
+	return t == QuestionSingle || t == QuestionMulti
+}
+
+// SurveyQuestion - a single question of a survey.
+type SurveyQuestion struct {
+	// ID - number of the question in the survey, starting from 0.
+	ID   int
+	Text string
+	Type QuestionType
+	// Options - options of a choice question, numbered from 0.
+	Options []string
+	// Scale - range of scores of a rating question.
+	Scale Scale
+	// Anonymous - answers to a text question are stored without the user who gave them.
+	Anonymous bool
+}
+
+// Survey - several questions of different types answered one by one.
+type Survey struct {
+	ID        string
+	Title     string
+	Questions []SurveyQuestion
+	IsActive  bool
+	// Author - ID of survey's author.
+	Author string
+	// TeamID - ID of the team where the survey was started, empty for direct messages.
+	TeamID string
+	// ChannelID - ID of the channel where the survey was started.
+	ChannelID string
+}
+
+func NewSurvey(title string, questions []SurveyQuestion, author string) *Survey {
+	for i := range questions {
+		questions[i].ID = i
+	}
+	return &Survey{
+		ID:        uuid.NewString(),
+		Title:     title,
+		Questions: questions,
+		IsActive:  true,
+		Author:    author,
+	}
+}
+
+// QuestionError - describes which question of the survey is invalid, Err is usually ValidationError.
+type QuestionError struct {
+	Question int
+	Err      error
+}
+
+func (e *QuestionError) Error() string {
+	return fmt.Sprintf("question %d: %v", e.Question, e.Err)
+}
+
+func (e *QuestionError) Unwrap() error {
+	return e.Err
+}
+
+// Validate checks the title against limits of questions and every question against limits of polls,
+// maxQuestions equal to 0 means no limit.
+func (s *Survey) Validate(limits PollLimits, maxQuestions int) error {
+	title := normalizeSpaces(s.Title)
+	if title == "" {
+		return &ValidationError{Err: ErrEmptyTitle, Option: -1}
+	}
+	if limits.MaxQuestionLength > 0 && utf8.RuneCountInString(title) > limits.MaxQuestionLength {
+		return &ValidationError{Err: ErrTitleTooLong, Option: -1, Limit: limits.MaxQuestionLength}
+	}
+	if len(s.Questions) == 0 {
+		return &ValidationError{Err: ErrTooFewQuestions, Option: -1, Limit: 1}
+	}
+	if maxQuestions > 0 && len(s.Questions) > maxQuestions {
+		return &ValidationError{Err: ErrTooManyQuestions, Option: -1, Limit: maxQuestions}
+	}
+	for _, question := range s.Questions {
+		if err := question.validate(limits); err != nil {
+			return &QuestionError{Question: question.ID, Err: err}
+		}
+	}
+	return nil
+}
+
+// validate checks the question like a poll. Questions without options are checked like a poll rating its question.
+func (q *SurveyQuestion) validate(limits PollLimits) error {
+	poll := &Poll{Question: q.Text}
+	switch {
+	case q.Type.HasOptions():
+		poll.Options = make([]PollOption, len(q.Options))
+		for i, text := range q.Options {
+			poll.Options[i] = PollOption{ID: i, Text: text}
+		}
+	case len(q.Options) > 0:
+		return &ValidationError{Err: ErrOptionsNotAllowed, Option: -1}
+	default:
+		poll.Options = []PollOption{{Text: q.Text}}
+	}
+	if q.Type == QuestionRating {
+		poll.Type = PollRating
+		poll.Scale = q.Scale
+	}
+	return poll.Validate(limits)
+}
+
+// Sanitize escapes Markdown and mentions in the title, questions and options, see SanitizeText.
+func (s *Survey) Sanitize() {
+	s.Title = SanitizeText(s.Title)
+	for i := range s.Questions {
+		question := &s.Questions[i]
+		question.Text = SanitizeText(question.Text)
+		for j := range question.Options {
+			question.Options[j] = SanitizeText(question.Options[j])
+		}
+	}
+}
+
+// NextQuestion returns the first question which is not answered in the response, nil if all are answered.
+func (s *Survey) NextQuestion(response *SurveyResponse) *SurveyQuestion {
+	for i := range s.Questions {
+		if _, ok := response.Answers[s.Questions[i].ID]; !ok {
+			return &s.Questions[i]
+		}
+	}
+	return nil
+}
+
+// SurveyAnswer - answer to a single question, only fields of the question's type are used.
+type SurveyAnswer struct {
+	// Options - numbers of chosen options of a choice question.
+	Options []int
+	// Score - score of a rating question.
+	Score int
+	// Text - answer to a text question, kept only until it is moved to TextAnswer.
+	Text string
+}
+
+// ParseAnswer parses a message of a respondent: numbers of options separated by spaces or commas,
+// a score, or any text, depending on the type of the question. maxTextLength equal to 0 means no limit.
+func (q *SurveyQuestion) ParseAnswer(input string, maxTextLength int) (SurveyAnswer, error) {
+	if q.Type == QuestionText {
+		text := strings.TrimSpace(input)
+		if text == "" {
+			return SurveyAnswer{}, ErrInvalidAnswer
+		}
+		if maxTextLength > 0 && utf8.RuneCountInString(text) > maxTextLength {
+			return SurveyAnswer{}, &ValidationError{Err: ErrAnswerTooLong, Option: -1, Limit: maxTextLength}
+		}
+		return SurveyAnswer{Text: text}, nil
+	}
+
+	fields := strings.FieldsFunc(input, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n'
+	})
+	numbers := make([]int, len(fields))
+	for i, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil {
+			return SurveyAnswer{}, ErrInvalidAnswer
+		}
+		numbers[i] = n
+	}
+
+	switch q.Type {
+	case QuestionRating:
+		if len(numbers) != 1 || !q.Scale.Contains(numbers[0]) {
+			return SurveyAnswer{}, ErrInvalidAnswer
+		}
+		return SurveyAnswer{Score: numbers[0]}, nil
+	case QuestionSingle:
+		if len(numbers) != 1 {
+			return SurveyAnswer{}, ErrInvalidAnswer
+		}
+	case QuestionMulti:
+		slices.Sort(numbers)
+		numbers = slices.Compact(numbers)
+		if len(numbers) == 0 {
+			return SurveyAnswer{}, ErrInvalidAnswer
+		}
+	}
+	for _, n := range numbers {
+		if n < 0 || n >= len(q.Options) {
+			return SurveyAnswer{}, ErrInvalidAnswer
+		}
+	}
+	return SurveyAnswer{Options: numbers}, nil
+}
+
+// SurveyResponse - answers of a single user to a survey, saved after every answered question.
+type SurveyResponse struct {
+	ID       string
+	SurveyID string
+	UserID   string
+	// Answers - answers keyed by question ID.
+	Answers map[int]SurveyAnswer
+	// Completed - every question is answered.
+	Completed bool
+	// UpdatedAt - time of the last answer, or of the start, so the latest survey in progress is answered.
+	UpdatedAt time.Time
+}
+
+func NewSurveyResponse(surveyID string, userID string) *SurveyResponse {
+	return &SurveyResponse{
+		ID:        uuid.NewString(),
+		SurveyID:  surveyID,
+		UserID:    userID,
+		Answers:   make(map[int]SurveyAnswer),
+		UpdatedAt: time.Now(),
+	}
+}
+
+// Answer saves the answer to the next question of the survey and returns the question,
+// the response is completed with the last answer.
+func (r *SurveyResponse) Answer(survey *Survey, input string, maxTextLength int) (*SurveyQuestion, error) {
+	question := survey.NextQuestion(r)
+	if question == nil {
+		return nil, ErrInvalidAnswer
+	}
+	answer, err := question.ParseAnswer(input, maxTextLength)
+	if err != nil {
+		return question, err
+	}
+	if r.Answers == nil {
+		r.Answers = make(map[int]SurveyAnswer)
+	}
+	r.Answers[question.ID] = answer
+	r.Completed = survey.NextQuestion(r) == nil
+	r.UpdatedAt = time.Now()
+	return question, nil
+}
+
+// QuestionResult - aggregated answers to a question.
+type QuestionResult struct {
+	Question SurveyQuestion
+	// Answers - count of respondents who answered the question.
+	Answers int
+	// Votes - count of respondents who chose every option of a choice question.
+	Votes []int
+	// Rating - statistics of scores of a rating question.
+	Rating Rating
+	// Published - answers to a text question which the author has published, in the order they were given.
+	Published []*TextAnswer
+}
+
+// SurveyResults - answers to every question of the survey, including answers of responses in progress.
+type SurveyResults struct {
+	Survey    *Survey
+	Questions []QuestionResult
+	// Respondents - count of users who answered at least one question.
+	Respondents int
+	// Completed - count of users who answered every question.
+	Completed int
+}
+
+// Results aggregates the responses by questions. Of text answers only published ones are shown.
+func (s *Survey) Results(responses []*SurveyResponse, textAnswers []*TextAnswer) *SurveyResults {
+	results := &SurveyResults{
+		Survey:    s,
+		Questions: make([]QuestionResult, len(s.Questions)),
+	}
+	histograms := make([][]int, len(s.Questions))
+	for i, question := range s.Questions {
+		results.Questions[i] = QuestionResult{Question: question, Votes: make([]int, len(question.Options))}
+		if question.Type == QuestionRating {
+			histograms[i] = make([]int, question.Scale.Size())
+		}
+	}
+
+	for _, response := range responses {
+		if len(response.Answers) == 0 {
+			continue
+		}
+		results.Respondents++
+		if response.Completed {
+			results.Completed++
+		}
+		for i, question := range s.Questions {
+			answer, ok := response.Answers[question.ID]
+			if !ok {
+				continue
+			}
+			result := &results.Questions[i]
+			result.Answers++
+			for _, option := range answer.Options {
+				if option >= 0 && option < len(result.Votes) {
+					result.Votes[option]++
+				}
+			}
+			if question.Type == QuestionRating && question.Scale.Contains(answer.Score) {
+				histograms[i][answer.Score-question.Scale.Min]++
+			}
+		}
+	}
+
+	for i, question := range s.Questions {
+		if question.Type == QuestionRating {
+			results.Questions[i].Rating = question.Scale.Rating(histograms[i])
+		}
+	}
+	SortTextAnswers(textAnswers)
+	for _, answer := range textAnswers {
+		if answer.Published && answer.QuestionID >= 0 && answer.QuestionID < len(results.Questions) {
+			result := &results.Questions[answer.QuestionID]
+			result.Published = append(result.Published, answer)
+		}
+	}
+	return results
+}
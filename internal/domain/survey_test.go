@@ -0,0 +1,127 @@
+package domain_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Xausdorf/mattermost-poll/internal/domain"
+)
+
+func TestParseAnswer(t *testing.T) {
+	single := domain.SurveyQuestion{Type: domain.QuestionSingle, Options: []string{"a", "b", "c"}}
+	multi := domain.SurveyQuestion{Type: domain.QuestionMulti, Options: []string{"a", "b", "c"}}
+	rating := domain.SurveyQuestion{Type: domain.QuestionRating, Scale: domain.DefaultScale}
+	text := domain.SurveyQuestion{Type: domain.QuestionText}
+	tests := []struct {
+		name     string
+		question domain.SurveyQuestion
+		in       string
+		maxText  int
+		want     domain.SurveyAnswer
+		wantErr  error
+	}{
+		{name: "single", question: single, in: "1", want: domain.SurveyAnswer{Options: []int{1}}},
+		{name: "single with spaces", question: single, in: " 2 ", want: domain.SurveyAnswer{Options: []int{2}}},
+		{name: "single with several options", question: single, in: "0 1", wantErr: domain.ErrInvalidAnswer},
+		{name: "single out of range", question: single, in: "3", wantErr: domain.ErrInvalidAnswer},
+		{name: "single negative", question: single, in: "-1", wantErr: domain.ErrInvalidAnswer},
+		{name: "single not a number", question: single, in: "b", wantErr: domain.ErrInvalidAnswer},
+		{name: "single empty", question: single, in: "", wantErr: domain.ErrInvalidAnswer},
+		{name: "multi", question: multi, in: "2, 0", want: domain.SurveyAnswer{Options: []int{0, 2}}},
+		{name: "multi with duplicates", question: multi, in: "1 1\n2", want: domain.SurveyAnswer{Options: []int{1, 2}}},
+		{name: "multi empty", question: multi, in: " , ", wantErr: domain.ErrInvalidAnswer},
+		{name: "multi out of range", question: multi, in: "0 5", wantErr: domain.ErrInvalidAnswer},
+		{name: "rating", question: rating, in: "5", want: domain.SurveyAnswer{Score: 5}},
+		{name: "rating out of scale", question: rating, in: "0", wantErr: domain.ErrInvalidAnswer},
+		{name: "rating with several scores", question: rating, in: "1 2", wantErr: domain.ErrInvalidAnswer},
+		{name: "text", question: text, in: "  Good job \n", want: domain.SurveyAnswer{Text: "Good job"}},
+		{name: "text empty", question: text, in: " \n ", wantErr: domain.ErrInvalidAnswer},
+		{name: "text at limit", question: text, in: "абв", maxText: 3, want: domain.SurveyAnswer{Text: "абв"}},
+		{name: "text too long", question: text, in: "абвг", maxText: 3, wantErr: domain.ErrAnswerTooLong},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.question.ParseAnswer(tt.in, tt.maxText)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseAnswer(%q) error = %v, want %v", tt.in, err, tt.wantErr)
+			}
+			if !slices.Equal(got.Options, tt.want.Options) || got.Score != tt.want.Score || got.Text != tt.want.Text {
+				t.Errorf("ParseAnswer(%q) = %+v, want %+v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSurveyResults(t *testing.T) {
+	survey := &domain.Survey{
+		ID: "s1",
+		Questions: []domain.SurveyQuestion{
+			{ID: 0, Type: domain.QuestionSingle, Options: []string{"a", "b"}},
+			{ID: 1, Type: domain.QuestionMulti, Options: []string{"a", "b", "c"}},
+			{ID: 2, Type: domain.QuestionRating, Scale: domain.DefaultScale},
+			{ID: 3, Type: domain.QuestionText},
+		},
+	}
+	responses := []*domain.SurveyResponse{
+		{
+			UserID: "u1",
+			Answers: map[int]domain.SurveyAnswer{
+				0: {Options: []int{1}}, 1: {Options: []int{0, 2}}, 2: {Score: 5}, 3: {},
+			},
+			Completed: true,
+		},
+		{
+			UserID:  "u2",
+			Answers: map[int]domain.SurveyAnswer{0: {Options: []int{1}}, 1: {Options: []int{2}}, 2: {Score: 3}},
+		},
+		{UserID: "u3", Answers: map[int]domain.SurveyAnswer{}},
+	}
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	textAnswers := []*domain.TextAnswer{
+		{ID: "t2", QuestionID: 3, Text: "second", CreatedAt: day.Add(time.Second), Published: true},
+		{ID: "t1", QuestionID: 3, Text: "first", CreatedAt: day, Published: true},
+		{ID: "t3", QuestionID: 3, Text: "hidden", CreatedAt: day.Add(2 * time.Second)},
+		{ID: "t4", QuestionID: 7, Text: "removed question", CreatedAt: day, Published: true},
+	}
+
+	results := survey.Results(responses, textAnswers)
+	if results.Respondents != 2 || results.Completed != 1 {
+		t.Errorf("respondents %d, completed %d, want 2, 1", results.Respondents, results.Completed)
+	}
+	tests := []struct {
+		name          string
+		question      int
+		wantAnswers   int
+		wantVotes     []int
+		wantHistogram []int
+		wantPublished []string
+	}{
+		{name: "single", question: 0, wantAnswers: 2, wantVotes: []int{0, 2}},
+		{name: "multi", question: 1, wantAnswers: 2, wantVotes: []int{1, 0, 2}},
+		{name: "rating", question: 2, wantAnswers: 2, wantVotes: []int{}, wantHistogram: []int{0, 0, 1, 0, 1}},
+		{name: "text", question: 3, wantAnswers: 1, wantVotes: []int{}, wantPublished: []string{"first", "second"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := results.Questions[tt.question]
+			if result.Answers != tt.wantAnswers {
+				t.Errorf("answers = %d, want %d", result.Answers, tt.wantAnswers)
+			}
+			if !slices.Equal(result.Votes, tt.wantVotes) {
+				t.Errorf("votes = %v, want %v", result.Votes, tt.wantVotes)
+			}
+			if !slices.Equal(result.Rating.Histogram, tt.wantHistogram) {
+				t.Errorf("histogram = %v, want %v", result.Rating.Histogram, tt.wantHistogram)
+			}
+			var published []string
+			for _, answer := range result.Published {
+				published = append(published, answer.Text)
+			}
+			if !slices.Equal(published, tt.wantPublished) {
+				t.Errorf("published = %q, want %q", published, tt.wantPublished)
+			}
+		})
+	}
+}
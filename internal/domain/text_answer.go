@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TextAnswer - answer to a text question of a survey. Text answers are stored apart from responses,
+// so the author can read and publish them without seeing the rest of the response.
+type TextAnswer struct {
+	ID         string
+	SurveyID   string
+	QuestionID int
+	// UserID - ID of the user who answered, empty if the question is anonymous.
+	UserID    string
+	Text      string
+	CreatedAt time.Time
+	// Published - the author has shown the answer in the channel of the survey and in its results.
+	Published bool
+}
+
+// anonymousPrecision - answers to anonymous questions keep only the day they were given,
+// so they can't be matched with responses by time.
+const anonymousPrecision = 24 * time.Hour
+
+// NewTextAnswer creates an answer to the question. Answers to anonymous questions get neither the user
+// nor the exact time, answers are previous answers of the survey which keep the new one last in order.
+func NewTextAnswer(
+	surveyID string,
+	question *SurveyQuestion,
+	userID string,
+	text string,
+	answers []*TextAnswer,
+) *TextAnswer {
+	createdAt := time.Now()
+	if question.Anonymous {
+		userID = ""
+		createdAt = anonymousTime(createdAt, question.ID, answers)
+	}
+	return &TextAnswer{
+		ID:         uuid.NewString(),
+		SurveyID:   surveyID,
+		QuestionID: question.ID,
+		UserID:     userID,
+		Text:       text,
+		CreatedAt:  createdAt,
+	}
+}
+
+// anonymousTime returns the start of the day of now, or a second after the last answer to the question
+// if it is later, so numbers of earlier answers don't change.
+func anonymousTime(now time.Time, questionID int, answers []*TextAnswer) time.Time {
+	createdAt := now.Truncate(anonymousPrecision)
+	for _, answer := range answers {
+		if answer.QuestionID == questionID && !answer.CreatedAt.Before(createdAt) {
+			createdAt = answer.CreatedAt.Add(time.Second)
+		}
+	}
+	return createdAt
+}
+
+// SortTextAnswers sorts answers in the order they were given, so their numbers don't change between pages.
+func SortTextAnswers(answers []*TextAnswer) {
+	slices.SortFunc(answers, func(a, b *TextAnswer) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+}
@@ -0,0 +1,61 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Xausdorf/mattermost-poll/internal/domain"
+)
+
+func TestNewTextAnswerAnonymousTime(t *testing.T) {
+	day := time.Now().Truncate(24 * time.Hour)
+	tests := []struct {
+		name    string
+		answers []*domain.TextAnswer
+		want    time.Time
+	}{
+		{name: "first answer", want: day},
+		{
+			name:    "answers of previous days",
+			answers: []*domain.TextAnswer{{QuestionID: 0, CreatedAt: day.Add(-time.Hour)}},
+			want:    day,
+		},
+		{
+			name: "after answers of the day",
+			answers: []*domain.TextAnswer{
+				{QuestionID: 0, CreatedAt: day.Add(2 * time.Second)},
+				{QuestionID: 0, CreatedAt: day},
+			},
+			want: day.Add(3 * time.Second),
+		},
+		{
+			name:    "answers to other questions",
+			answers: []*domain.TextAnswer{{QuestionID: 1, CreatedAt: day.Add(time.Second)}},
+			want:    day,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			question := &domain.SurveyQuestion{ID: 0, Type: domain.QuestionText, Anonymous: true}
+			answer := domain.NewTextAnswer("s1", question, "user", "text", tt.answers)
+			if answer.UserID != "" {
+				t.Errorf("UserID = %q, want empty", answer.UserID)
+			}
+			if !answer.CreatedAt.Equal(tt.want) {
+				t.Errorf("CreatedAt = %v, want %v", answer.CreatedAt, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewTextAnswerNotAnonymous(t *testing.T) {
+	before := time.Now()
+	question := &domain.SurveyQuestion{ID: 0, Type: domain.QuestionText}
+	answer := domain.NewTextAnswer("s1", question, "user", "text", nil)
+	if answer.UserID != "user" {
+		t.Errorf("UserID = %q, want user", answer.UserID)
+	}
+	if answer.CreatedAt.Before(before) {
+		t.Errorf("CreatedAt = %v, want the time of the answer", answer.CreatedAt)
+	}
+}
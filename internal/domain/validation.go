@@ -0,0 +1,131 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	ErrEmptyQuestion   = errors.New("question is empty")
+	ErrQuestionTooLong = errors.New("question is too long")
+	ErrTooFewOptions   = errors.New("too few options")
+	ErrTooManyOptions  = errors.New("too many options")
+	ErrEmptyOption     = errors.New("option is empty")
+	ErrOptionTooLong   = errors.New("option is too long")
+	ErrDuplicateOption = errors.New("duplicate option")
+	ErrInvalidQuorum   = errors.New("quorum must be a positive count or a percentage from 1 to 100")
+)
+
+const minPollOptions = 1
+
+// PollLimits - limits of poll's content. Zero value of a field means no limit.
+type PollLimits struct {
+	MaxOptions        int
+	MaxQuestionLength int
+	MaxOptionLength   int
+}
+
+// ValidationError - describes which part of the poll is invalid and why.
+type ValidationError struct {
+	// Err - one of validation sentinel errors.
+	Err error
+	// Option - ID of invalid option, -1 if the error is not about a single option.
+	Option int
+	// Limit - exceeded limit, 0 if the error is not about a limit.
+	Limit int
+}
+
+func (e *ValidationError) Error() string {
+	if e.Option >= 0 {
+		return fmt.Sprintf("option %d: %v", e.Option, e.Err)
+	}
+	return e.Err.Error()
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// Validate checks poll's question and options against limits.
+// Texts are compared after whitespace normalization, options are compared case-insensitively.
+func (p *Poll) Validate(limits PollLimits) error {
+	question := normalizeSpaces(p.Question)
+	if question == "" {
+		return &ValidationError{Err: ErrEmptyQuestion, Option: -1}
+	}
+	if limits.MaxQuestionLength > 0 && utf8.RuneCountInString(question) > limits.MaxQuestionLength {
+		return &ValidationError{Err: ErrQuestionTooLong, Option: -1, Limit: limits.MaxQuestionLength}
+	}
+
+	if len(p.Options) < minPollOptions {
+		return &ValidationError{Err: ErrTooFewOptions, Option: -1, Limit: minPollOptions}
+	}
+	if limits.MaxOptions > 0 && len(p.Options) > limits.MaxOptions {
+		return &ValidationError{Err: ErrTooManyOptions, Option: -1, Limit: limits.MaxOptions}
+	}
+
+	seen := make(map[string]int, len(p.Options))
+	for _, option := range p.Options {
+		text := normalizeSpaces(option.Text)
+		if text == "" {
+			return &ValidationError{Err: ErrEmptyOption, Option: option.ID}
+		}
+		if limits.MaxOptionLength > 0 && utf8.RuneCountInString(text) > limits.MaxOptionLength {
+			return &ValidationError{Err: ErrOptionTooLong, Option: option.ID, Limit: limits.MaxOptionLength}
+		}
+		key := strings.ToLower(text)
+		if _, ok := seen[key]; ok {
+			return &ValidationError{Err: ErrDuplicateOption, Option: option.ID}
+		}
+		seen[key] = option.ID
+	}
+
+	if p.Quorum.Count < 0 || p.Quorum.Percent < 0 || p.Quorum.Percent > 100 {
+		return &ValidationError{Err: ErrInvalidQuorum, Option: -1}
+	}
+	if p.Type == PollRating && !p.Scale.Valid() {
+		return &ValidationError{Err: ErrInvalidScale, Option: -1}
+	}
+	for _, weight := range p.Weights {
+		if !ValidWeight(weight) {
+			return &ValidationError{Err: ErrInvalidWeight, Option: -1, Limit: MaxVoteWeight}
+		}
+	}
+	return nil
+}
+
+// Sanitize normalizes whitespace of poll's texts and escapes Markdown and mentions,
+// so options are rendered verbatim and `@all` inside of them doesn't notify the channel.
+func (p *Poll) Sanitize() {
+	p.Question = SanitizeText(p.Question)
+	for i := range p.Options {
+		p.Options[i].Text = SanitizeText(p.Options[i].Text)
+	}
+}
+
+// SanitizeText collapses whitespace (including line breaks), escapes Markdown control characters
+// and breaks mentions with a zero-width space after '@'.
+func SanitizeText(s string) string {
+	s = normalizeSpaces(s)
+
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		switch r {
+		case '\\', '*', '_', '~', '`', '[', ']', '#', '>', '|':
+			b.WriteRune('\\')
+			b.WriteRune(r)
+		case '@':
+			b.WriteString("@\u200b")
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func normalizeSpaces(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,160 @@
+package domain_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Xausdorf/mattermost-poll/internal/domain"
+)
+
+func options(texts ...string) []domain.PollOption {
+	opts := make([]domain.PollOption, len(texts))
+	for i, text := range texts {
+		opts[i] = domain.PollOption{ID: i, Text: text}
+	}
+	return opts
+}
+
+func TestValidate(t *testing.T) {
+	limits := domain.PollLimits{MaxOptions: 3, MaxQuestionLength: 10, MaxOptionLength: 5}
+	tests := []struct {
+		name       string
+		poll       domain.Poll
+		limits     domain.PollLimits
+		wantErr    error
+		wantOption int
+		wantLimit  int
+	}{
+		{name: "valid", poll: domain.Poll{Question: "Lunch?", Options: options("Pizza", "Sushi")}, limits: limits},
+		{name: "no limits", poll: domain.Poll{Question: "Where to have lunch?", Options: options("Pizza place")}},
+		{
+			name:   "question within limit after space normalization",
+			poll:   domain.Poll{Question: "  Eat\n\t now?  ", Options: options("Pizza")},
+			limits: limits,
+		},
+		{
+			name:   "length is counted in characters",
+			poll:   domain.Poll{Question: "Куда пойти?", Options: options("Пицца")},
+			limits: domain.PollLimits{MaxQuestionLength: 11, MaxOptionLength: 5},
+		},
+		{
+			name:       "empty question",
+			poll:       domain.Poll{Question: " \n\t", Options: options("Pizza")},
+			limits:     limits,
+			wantErr:    domain.ErrEmptyQuestion,
+			wantOption: -1,
+		},
+		{
+			name:       "question too long",
+			poll:       domain.Poll{Question: "Where to eat?", Options: options("Pizza")},
+			limits:     limits,
+			wantErr:    domain.ErrQuestionTooLong,
+			wantOption: -1,
+			wantLimit:  10,
+		},
+		{
+			name:       "fewer options than minPollOptions",
+			poll:       domain.Poll{Question: "Lunch?"},
+			limits:     limits,
+			wantErr:    domain.ErrTooFewOptions,
+			wantOption: -1,
+			wantLimit:  1,
+		},
+		{name: "options at limit", poll: domain.Poll{Question: "Lunch?", Options: options("a", "b", "c")}, limits: limits},
+		{
+			name:       "too many options",
+			poll:       domain.Poll{Question: "Lunch?", Options: options("a", "b", "c", "d")},
+			limits:     limits,
+			wantErr:    domain.ErrTooManyOptions,
+			wantOption: -1,
+			wantLimit:  3,
+		},
+		{
+			name:       "empty option",
+			poll:       domain.Poll{Question: "Lunch?", Options: options("Pizza", "  ")},
+			limits:     limits,
+			wantErr:    domain.ErrEmptyOption,
+			wantOption: 1,
+		},
+		{
+			name:       "option too long",
+			poll:       domain.Poll{Question: "Lunch?", Options: options("Pizza", "Burgers")},
+			limits:     limits,
+			wantErr:    domain.ErrOptionTooLong,
+			wantOption: 1,
+			wantLimit:  5,
+		},
+		{
+			name:       "duplicate option",
+			poll:       domain.Poll{Question: "Lunch?", Options: options("Pizza", "Sushi", "Pizza")},
+			limits:     limits,
+			wantErr:    domain.ErrDuplicateOption,
+			wantOption: 2,
+		},
+		{
+			name:       "duplicate option in another case and spacing",
+			poll:       domain.Poll{Question: "Lunch?", Options: options("Ice cream", " ice  CREAM")},
+			wantErr:    domain.ErrDuplicateOption,
+			wantOption: 1,
+		},
+		{
+			name:       "negative quorum",
+			poll:       domain.Poll{Question: "Lunch?", Options: options("Pizza"), Quorum: domain.Quorum{Count: -1}},
+			wantErr:    domain.ErrInvalidQuorum,
+			wantOption: -1,
+		},
+		{
+			name:       "quorum over 100 percent",
+			poll:       domain.Poll{Question: "Lunch?", Options: options("Pizza"), Quorum: domain.Quorum{Percent: 101}},
+			wantErr:    domain.ErrInvalidQuorum,
+			wantOption: -1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.poll.Validate(tt.limits)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil {
+				return
+			}
+			var validationErr *domain.ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("Validate() error %v is not a ValidationError", err)
+			}
+			if validationErr.Option != tt.wantOption || validationErr.Limit != tt.wantLimit {
+				t.Errorf("option %d, limit %d, want %d, %d",
+					validationErr.Option, validationErr.Limit, tt.wantOption, tt.wantLimit)
+			}
+		})
+	}
+}
+
+func TestSanitizeText(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "plain text", in: "Pizza", want: "Pizza"},
+		{name: "whitespace and line breaks", in: "  Pizza\n\tand  pasta ", want: "Pizza and pasta"},
+		{name: "channel mention", in: "@all come", want: "@\u200ball come"},
+		{name: "user mention", in: "ask @alice", want: "ask @\u200balice"},
+		{name: "email", in: "bob@example.com", want: "bob@\u200bexample.com"},
+		{name: "bold", in: "**Pizza**", want: `\*\*Pizza\*\*`},
+		{name: "link", in: "[site](http://x)", want: `\[site\](http://x)`},
+		{name: "heading and quote", in: "# > text", want: `\# \> text`},
+		{name: "code and table", in: "`a` | b", want: "\\`a\\` \\| b"},
+		{name: "backslash", in: `a\b`, want: `a\\b`},
+		{name: "underscore and strikethrough", in: "_a_ ~b~", want: `\_a\_ \~b\~`},
+		{name: "empty", in: " \n ", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := domain.SanitizeText(tt.in); got != tt.want {
+				t.Errorf("SanitizeText(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ResultsVisibility - who can see votes of the poll and when.
+type ResultsVisibility string
+
+const (
+	// ResultsAlways - everyone sees live results.
+	ResultsAlways ResultsVisibility = "always"
+	// ResultsAfterVote - a user sees results after voting.
+	ResultsAfterVote ResultsVisibility = "voted"
+	// ResultsAfterClose - results are shown when the poll is closed.
+	ResultsAfterClose ResultsVisibility = "closed"
+	// ResultsAuthorOnly - only the author sees results.
+	ResultsAuthorOnly ResultsVisibility = "author"
+)
+
+// ParseResultsVisibility parses visibility of results, empty string means ResultsAlways.
+func ParseResultsVisibility(s string) (ResultsVisibility, error) {
+	switch visibility := ResultsVisibility(strings.ToLower(strings.TrimSpace(s))); visibility {
+	case "":
+		return ResultsAlways, nil
+	case ResultsAlways, ResultsAfterVote, ResultsAfterClose, ResultsAuthorOnly:
+		return visibility, nil
+	default:
+		return "", fmt.Errorf("unknown results visibility %q", s)
+	}
+}
+
+// ResultsVisibleTo returns true if the user can see votes of the poll. The author always sees them.
+func (p *Poll) ResultsVisibleTo(userID string, voted bool) bool {
+	if userID == p.Author {
+		return true
+	}
+	switch p.ResultsVisibility {
+	case ResultsAfterVote:
+		return voted
+	case ResultsAfterClose:
+		return !p.IsActive
+	case ResultsAuthorOnly:
+		return false
+	default:
+		return true
+	}
+}
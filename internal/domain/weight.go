@@ -0,0 +1,27 @@
+package domain
+
+import "errors"
+
+var ErrInvalidWeight = errors.New("weight of a vote must be a positive integer")
+
+// MaxVoteWeight - the largest weight of a single vote, so one voter can't outweigh the whole channel by a typo.
+const MaxVoteWeight = 100
+
+// VoteWeight returns weight of the vote of the user, given the weight resolved by the caller.
+// Votes in polls which are not weighted count as 1. If the poll has its own weights, they are used
+// instead of the resolved weight and voters who are not listed count as 1.
+func (p *Poll) VoteWeight(userID string, resolved int) int {
+	switch {
+	case !p.Weighted:
+		return 1
+	case len(p.Weights) > 0:
+		return max(p.Weights[userID], 1)
+	default:
+		return min(max(resolved, 1), MaxVoteWeight)
+	}
+}
+
+// ValidWeight returns true if the weight is from 1 to MaxVoteWeight.
+func ValidWeight(weight int) bool {
+	return weight >= 1 && weight <= MaxVoteWeight
+}
@@ -0,0 +1,124 @@
+package domain_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/Xausdorf/mattermost-poll/internal/domain"
+)
+
+func TestPollVoteWeight(t *testing.T) {
+	tests := []struct {
+		name     string
+		poll     domain.Poll
+		userID   string
+		resolved int
+		want     int
+	}{
+		{name: "not weighted", poll: domain.Poll{}, userID: "u1", resolved: 5, want: 1},
+		{
+			name:     "not weighted with own weights",
+			poll:     domain.Poll{Weights: map[string]int{"u1": 3}},
+			userID:   "u1",
+			resolved: 5,
+			want:     1,
+		},
+		{name: "resolved weight", poll: domain.Poll{Weighted: true}, userID: "u1", resolved: 5, want: 5},
+		{name: "resolved weight below one", poll: domain.Poll{Weighted: true}, userID: "u1", resolved: 0, want: 1},
+		{
+			name:     "resolved weight above limit",
+			poll:     domain.Poll{Weighted: true},
+			userID:   "u1",
+			resolved: domain.MaxVoteWeight + 1,
+			want:     domain.MaxVoteWeight,
+		},
+		{
+			name:     "own weights override resolved",
+			poll:     domain.Poll{Weighted: true, Weights: map[string]int{"u1": 3}},
+			userID:   "u1",
+			resolved: 5,
+			want:     3,
+		},
+		{
+			name:     "voter missing in own weights",
+			poll:     domain.Poll{Weighted: true, Weights: map[string]int{"u1": 3}},
+			userID:   "u2",
+			resolved: 5,
+			want:     1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.poll.VoteWeight(tt.userID, tt.resolved); got != tt.want {
+				t.Errorf("VoteWeight(%q, %d) = %d, want %d", tt.userID, tt.resolved, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidWeight(t *testing.T) {
+	tests := []struct {
+		weight int
+		want   bool
+	}{
+		{weight: -1, want: false},
+		{weight: 0, want: false},
+		{weight: 1, want: true},
+		{weight: domain.MaxVoteWeight, want: true},
+		{weight: domain.MaxVoteWeight + 1, want: false},
+	}
+	for _, tt := range tests {
+		if got := domain.ValidWeight(tt.weight); got != tt.want {
+			t.Errorf("ValidWeight(%d) = %v, want %v", tt.weight, got, tt.want)
+		}
+	}
+}
+
+func TestPollRateWeighted(t *testing.T) {
+	poll := &domain.Poll{
+		Type:     domain.PollRating,
+		Scale:    domain.DefaultScale,
+		Weighted: true,
+		Options:  []domain.PollOption{{ID: 1, Text: "a"}, {ID: 2, Text: "b"}},
+	}
+	votes := []struct {
+		scores map[int]int
+		weight int
+	}{
+		{scores: map[int]int{1: 5, 2: 1}, weight: 3},
+		{scores: map[int]int{1: 1, 2: 1}, weight: 1},
+	}
+	for _, vote := range votes {
+		if err := poll.Rate(vote.scores, vote.weight); err != nil {
+			t.Fatalf("Rate(%v, %d) error: %v", vote.scores, vote.weight, err)
+		}
+	}
+
+	tests := []struct {
+		scores     []int
+		weight     int
+		mean       float64
+		median     float64
+		promoters  int
+		detractors int
+	}{
+		{scores: []int{1, 0, 0, 0, 3}, weight: 4, mean: 4, median: 5, promoters: 3, detractors: 1},
+		{scores: []int{4, 0, 0, 0, 0}, weight: 4, mean: 1, median: 1, detractors: 4},
+	}
+	for i, tt := range tests {
+		option := poll.Options[i]
+		if !slices.Equal(option.Scores, tt.scores) || option.Votes != 2 || option.Weight != tt.weight {
+			t.Errorf("option %d: scores %v, votes %d, weight %d, want %v, 2, %d",
+				option.ID, option.Scores, option.Votes, option.Weight, tt.scores, tt.weight)
+		}
+		rating := option.Rating(poll.Scale)
+		if rating.Count != tt.weight || rating.Mean != tt.mean || rating.Median != tt.median ||
+			rating.Promoters != tt.promoters || rating.Detractors != tt.detractors {
+			t.Errorf("option %d: rating %+v, want count %d, mean %v, median %v, promoters %d, detractors %d",
+				option.ID, rating, tt.weight, tt.mean, tt.median, tt.promoters, tt.detractors)
+		}
+	}
+	if voters := poll.Voters(); voters != 2 {
+		t.Errorf("Voters() = %d, want 2", voters)
+	}
+}
@@ -2,386 +2,1159 @@ package bot
 
 import (
 	"context"
-	"encoding/csv"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/url"
-	"os"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/Xausdorf/mattermost-poll/internal/domain"
+	"github.com/Xausdorf/mattermost-poll/internal/logging"
 	"github.com/Xausdorf/mattermost-poll/internal/usecase"
 	"github.com/mattermost/mattermost-server/v6/model"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
-const (
-	maxRetries            = 5
-	pollStartMinArgsCount = 2
-	pollVoteArgsCount     = 2
-)
-
+// Config - settings of Mattermost connection, changing them requires restart.
 type Config struct {
-	mmUserName string
-	mmTeamName string
-	mmToken    string
-	mmServer   *url.URL
+	UserName string
+	Token    string
+	Server   *url.URL
+	// MaxRetries - count of websocket connection failures in a row after which Listen gives up.
+	MaxRetries int
+	// WebSocketGracePeriod - how long the websocket may be disconnected before the bot is considered dead.
+	WebSocketGracePeriod time.Duration
+
+	Settings Settings
 }
 
-func LoadConfig() Config {
-	var (
-		cfg Config
-		err error
-	)
+// Settings - settings which can be changed while the bot is running, see UpdateSettings.
+type Settings struct {
+	UserRateLimits    RateLimits
+	ChannelRateLimits RateLimits
 
-	cfg.mmUserName = os.Getenv("MM_USERNAME")
-	if cfg.mmUserName == "" {
-		cfg.mmUserName = "PollingBot"
-	}
-	cfg.mmTeamName = os.Getenv("MM_TEAM")
-	if cfg.mmTeamName == "" {
-		cfg.mmTeamName = "PollingBot"
-	}
-	cfg.mmToken = os.Getenv("MM_TOKEN")
-	if cfg.mmToken == "" {
-		log.Fatal("Mattermost token is not set")
-	}
-	cfg.mmServer, err = url.Parse(os.Getenv("MM_SERVER"))
-	if err != nil {
-		log.Fatalf("Mattermost URL is not valid: %v", err)
+	DefaultLocale  string
+	ChannelLocales map[string]string
+
+	// CommandPrefix - prefix of commands, "!" if empty. Teams can override it.
+	CommandPrefix string
+	// MentionOnly - handle only commands starting with mention of the bot, in every team.
+	MentionOnly bool
+	// Aliases - additional names of commands, alias -> command name, e.g. "vote" -> "poll_vote".
+	Aliases map[string]string
+	// Teams - settings of teams keyed by team name or ID, teams which are not listed use defaults.
+	Teams map[string]TeamSettings
+	// ResponseVisibility - public or ephemeral responses by kind, see DefaultResponseVisibility.
+	ResponseVisibility ResponseVisibility
+	// ReminderMode - how members who have not voted are reminded, mention if empty.
+	ReminderMode ReminderMode
+	// VoteWeights - weights of votes in polls started with --weighted.
+	VoteWeights VoteWeights
+}
+
+func (s Settings) teamDefaults() TeamSettings {
+	return TeamSettings{
+		CommandPrefix: s.CommandPrefix,
+		MentionOnly:   &s.MentionOnly,
 	}
+}
+
+var (
+	ErrShutdownTimeout       = errors.New("commands were not finished before shutdown deadline")
+	ErrWebSocketDisconnected = errors.New("mattermost websocket is disconnected")
+	ErrMattermostUnreachable = errors.New("mattermost api is unreachable")
+)
+
+// PollService - poll usecases used by the bot.
+type PollService interface {
+	CreatePoll(ctx context.Context, poll *domain.Poll) error
+	AddAnswer(ctx context.Context, answer *domain.Answer) error
+	GetPollByID(ctx context.Context, id string) (*domain.Poll, error)
+	GetResults(ctx context.Context, id string, userID string) (*usecase.PollResults, error)
+	ClosePollByID(
+		ctx context.Context, id string, senderID string, opts usecase.CloseOptions,
+	) (*usecase.PollResults, error)
+	ExpiredPolls(ctx context.Context, now time.Time) ([]*domain.Poll, error)
+	ExpirePoll(ctx context.Context, id string, now time.Time, members int) (*usecase.PollResults, error)
+	ReopenPoll(ctx context.Context, id string, sender usecase.Sender) error
+	ExtendPoll(ctx context.Context, id string, sender usecase.Sender, d time.Duration) (*domain.Poll, error)
+	AddOption(ctx context.Context, id string, sender usecase.Sender, text string) (*domain.PollOption, error)
+	EditOption(ctx context.Context, id string, sender usecase.Sender, optionID int, text string) error
+	RemoveOption(
+		ctx context.Context, id string, sender usecase.Sender, optionID int, opts usecase.RemoveOptionOptions,
+	) (int, error)
+	SuggestOption(ctx context.Context, pollID string, userID string, text string) (*domain.Suggestion, error)
+	PendingSuggestions(ctx context.Context, pollID string, sender usecase.Sender) ([]*domain.Suggestion, error)
+	ReviewSuggestion(ctx context.Context, id string, sender usecase.Sender, approve bool) (*domain.Suggestion, error)
+	CreateSurvey(ctx context.Context, survey *domain.Survey) error
+	TakeSurvey(ctx context.Context, id string, userID string) (*domain.Survey, *domain.SurveyResponse, error)
+	AnswerSurvey(ctx context.Context, userID string, input string) (*domain.Survey, *domain.SurveyResponse, error)
+	SurveyInProgress(ctx context.Context, userID string) (*domain.Survey, *domain.SurveyResponse, error)
+	SurveyResults(ctx context.Context, id string, sender usecase.Sender) (*domain.SurveyResults, error)
+	CloseSurvey(ctx context.Context, id string, sender usecase.Sender) error
+	TextAnswers(
+		ctx context.Context, surveyID string, questionID int, sender usecase.Sender,
+	) (*domain.Survey, []*domain.TextAnswer, error)
+	PublishTextAnswers(
+		ctx context.Context, surveyID string, sender usecase.Sender, ids []string,
+	) (*domain.Survey, []*domain.TextAnswer, error)
+	DeletePollByID(ctx context.Context, id string, senderID string) error
+	RemindPoll(
+		ctx context.Context, id string, senderID string, listMembers func(ctx context.Context) ([]string, error),
+	) ([]string, error)
+	PollsToRemind(ctx context.Context, now time.Time) ([]*domain.Poll, error)
+	SetDoNotDisturb(ctx context.Context, userID string, enabled bool) error
+}
 
-	return cfg
+// Observer - receives events of the bot for instrumentation.
+type Observer interface {
+	CommandHandled(command string, outcome string)
+	WebSocketReconnected()
+	EventQueueDepth(depth int)
 }
 
+// outcome - result of handling a command, reported to Observer.
+type outcome string
+
+const (
+	outcomeOK          outcome = "ok"
+	outcomeRejected    outcome = "rejected"
+	outcomeFailed      outcome = "failed"
+	outcomeInvalid     outcome = "invalid"
+	outcomeRateLimited outcome = "rate_limited"
+)
+
 type PollingBot struct {
 	cfg             Config
 	client          *model.Client4
 	webSocketClient *model.WebSocketClient
 	user            *model.User
-	team            *model.Team
-	pollService     *usecase.Poll
+	teams           *teamRegistry
+	commands        *commandRegistry
+	pollService     PollService
+	observer        Observer
+	rateLimiter     *RateLimiter
+	responses       *responsePolicy
+	translator      *Translator
+	locales         *localeResolver
+	weights         *weightResolver
+	tracer          trace.Tracer
+
+	settingsMu   sync.RWMutex
+	reminderMode ReminderMode
+
+	// handlers - in-flight event handlers, they use handlersCtx which is not cancelled
+	// together with the context of Listen, so that a command is not interrupted in the middle.
+	handlers       sync.WaitGroup
+	handlersCtx    context.Context
+	cancelHandlers context.CancelFunc
+
+	// wsMu guards state of websocket connection, which is reported by health checks.
+	wsMu           sync.Mutex
+	wsConnected    bool
+	wsDisconnected time.Time
+
+	logger *slog.Logger
 }
 
-func NewPollingBot(cfg Config, pollService *usecase.Poll) *PollingBot {
+func NewPollingBot(
+	cfg Config,
+	pollService PollService,
+	observer Observer,
+	tracerProvider trace.TracerProvider,
+	logger *slog.Logger,
+) (*PollingBot, error) {
 	var bot PollingBot
 
 	bot.cfg = cfg
-	bot.client = model.NewAPIv4Client(bot.cfg.mmServer.String())
-	bot.client.SetToken(bot.cfg.mmToken)
+	bot.logger = logger
+	bot.client = model.NewAPIv4Client(bot.cfg.Server.String())
+	bot.client.SetToken(bot.cfg.Token)
 
-	user, resp, err := bot.client.GetMe("")
+	user, _, err := bot.client.GetMe("")
 	if err != nil {
-		log.Fatalf("Could not log in to Mattermost: %v", err)
+		return nil, fmt.Errorf("could not log in to Mattermost: %w", err)
 	}
-	log.Printf("Logged in to mattermost: user=%v; resp=%v\n", user, resp)
+	logger.Info("Logged in to mattermost", "user_id", user.Id, "username", user.Username)
 	bot.user = user
 
-	team, resp, err := bot.client.GetTeamByName(cfg.mmTeamName, "")
-	if err != nil {
-		log.Fatalf("Could not find team: %v", err)
+	bot.teams = newTeamRegistry(bot.client, logger)
+	bot.teams.setSettings(cfg.Settings.Teams, cfg.Settings.teamDefaults())
+	if err = bot.teams.discover(context.Background(), user.Id); err != nil {
+		return nil, err
+	}
+
+	bot.commands = newCommandRegistry()
+	if err = bot.registerCommands(); err != nil {
+		return nil, err
+	}
+	if err = bot.commands.setAliases(cfg.Settings.Aliases); err != nil {
+		return nil, err
 	}
-	log.Printf("Team found: team=%v; resp=%v\n", team, resp)
-	bot.team = team
 
 	bot.pollService = pollService
+	bot.observer = observer
+	bot.tracer = tracerProvider.Tracer("github.com/Xausdorf/mattermost-poll/internal/gateway/bot")
+	bot.rateLimiter = NewRateLimiter(cfg.Settings.UserRateLimits, cfg.Settings.ChannelRateLimits)
+	bot.responses = newResponsePolicy(cfg.Settings.ResponseVisibility)
+	bot.setReminderMode(cfg.Settings.ReminderMode)
+	bot.weights = newWeightResolver(bot.client, cfg.Settings.VoteWeights)
+
+	translator, err := NewTranslator(cfg.Settings.DefaultLocale)
+	if err != nil {
+		return nil, fmt.Errorf("message catalogs are not valid: %w", err)
+	}
+	bot.translator = translator
+	if err = checkChannelLocales(translator, cfg.Settings.ChannelLocales); err != nil {
+		return nil, err
+	}
+	bot.locales = newLocaleResolver(bot.client, cfg.Settings.ChannelLocales, logger)
+	bot.wsDisconnected = time.Now()
 
-	return &bot
+	return &bot, nil
 }
 
-func (b *PollingBot) Listen(ctx context.Context) {
-	for range maxRetries {
+// UpdateSettings applies new settings to the running bot, buckets of rate limiter and cached locales are kept.
+func (b *PollingBot) UpdateSettings(settings Settings) error {
+	if err := checkChannelLocales(b.translator, settings.ChannelLocales); err != nil {
+		return err
+	}
+	if err := b.commands.setAliases(settings.Aliases); err != nil {
+		return err
+	}
+	if err := b.translator.SetFallback(settings.DefaultLocale); err != nil {
+		return err
+	}
+	b.locales.setChannelLocales(settings.ChannelLocales)
+	b.teams.setSettings(settings.Teams, settings.teamDefaults())
+	b.responses.set(settings.ResponseVisibility)
+	b.setReminderMode(settings.ReminderMode)
+	b.weights.set(settings.VoteWeights)
+	b.rateLimiter.SetLimits(settings.UserRateLimits, settings.ChannelRateLimits)
+	return nil
+}
+
+func (b *PollingBot) registerCommands() error {
+	handlers := map[string]commandHandler{
+		"help":               b.handleHelp,
+		"poll_start":         b.handleStart,
+		"poll_vote":          b.handleVote,
+		"poll_results":       b.handleResults,
+		"poll_close":         b.handleClose,
+		"poll_delete":        b.handleDelete,
+		"poll_remind":        b.handleRemind,
+		"poll_dnd":           b.handleDoNotDisturb,
+		"poll_reopen":        b.handleReopen,
+		"poll_extend":        b.handleExtend,
+		"poll_option_add":    b.handleOptionAdd,
+		"poll_option_edit":   b.handleOptionEdit,
+		"poll_option_remove": b.handleOptionRemove,
+		"poll_suggest":       b.handleSuggest,
+		"poll_suggestions":   b.handleSuggestions,
+		"poll_approve":       b.handleApprove,
+		"poll_reject":        b.handleReject,
+		"survey_start":       b.handleSurveyStart,
+		"survey_take":        b.handleSurveyTake,
+		"survey_results":     b.handleSurveyResults,
+		"survey_close":       b.handleSurveyClose,
+		"survey_answers":     b.handleSurveyAnswers,
+		"survey_export":      b.handleSurveyExport,
+		"survey_publish":     b.handleSurveyPublish,
+	}
+	for _, spec := range commandSpecs() {
+		handler, ok := handlers[spec.name]
+		if !ok {
+			return fmt.Errorf("command %s has no handler", spec.name)
+		}
+		b.commands.register(spec, handler)
+	}
+	return nil
+}
+
+func checkChannelLocales(translator *Translator, channelLocales map[string]string) error {
+	for channelID, locale := range channelLocales {
+		if !translator.Supports(locale) {
+			return fmt.Errorf("locale of channel %s is not supported: %q", channelID, locale)
+		}
+	}
+	return nil
+}
+
+// Listen handles websocket events until ctx is done, reconnecting when the connection is lost.
+// Returns error if the websocket could not be connected Config.MaxRetries times in a row.
+func (b *PollingBot) Listen(ctx context.Context) error {
+	b.handlersCtx, b.cancelHandlers = context.WithCancel(context.WithoutCancel(ctx))
+
+	// scheduled jobs are stopped before Listen returns, so that they don't race with shutdown of handlers
+	scheduleCtx, stopSchedule := context.WithCancel(ctx)
+	var schedule sync.WaitGroup
+	schedule.Add(1)
+	go func() {
+		defer schedule.Done()
+		b.scheduleLoop(scheduleCtx)
+	}()
+	defer schedule.Wait()
+	defer stopSchedule()
+
+	failures := 0
+	for connections := 0; failures < b.cfg.MaxRetries; connections++ {
+		if ctx.Err() != nil {
+			return nil
+		}
+		if connections > 0 {
+			b.observer.WebSocketReconnected()
+		}
+
 		var err error
 		b.webSocketClient, err = model.NewWebSocketClient4(
-			fmt.Sprintf("ws://%s", b.cfg.mmServer.Host+b.cfg.mmServer.Path),
+			fmt.Sprintf("ws://%s", b.cfg.Server.Host+b.cfg.Server.Path),
 			b.client.AuthToken,
 		)
 		if err != nil {
-			log.Printf("Could not connect mattermost websocket: %v\n", err)
+			failures++
+			b.logger.WarnContext(ctx, "Could not connect mattermost websocket", "error", err)
 			continue
 		}
-		log.Println("Mattermost websocket succesfully connected")
+		failures = 0
+		b.logger.InfoContext(ctx, "Mattermost websocket succesfully connected")
 
 		b.webSocketClient.Listen()
 
-		log.Println("Polling Bot listening now")
-		for {
-			select {
-			case event := <-b.webSocketClient.EventChannel:
-				go b.handleWebSocketEvent(ctx, event)
-			case <-ctx.Done():
-				return
+		b.logger.InfoContext(ctx, "Polling Bot listening now")
+		b.setWebSocketConnected(true)
+		connected := b.listenEvents(ctx, b.webSocketClient)
+		b.setWebSocketConnected(false)
+		if !connected {
+			b.Close()
+			return nil
+		}
+		b.logger.WarnContext(ctx, "Mattermost websocket connection lost", "error", b.webSocketClient.ListenError)
+	}
+	return errors.New("could not connect mattermost websocket, max retries exceeded")
+}
+
+// listenEvents handles events until the connection is lost (returns true) or ctx is done (returns false).
+func (b *PollingBot) listenEvents(ctx context.Context, ws *model.WebSocketClient) bool {
+	for {
+		select {
+		case event, ok := <-ws.EventChannel:
+			if !ok {
+				return true
 			}
+			b.observer.EventQueueDepth(len(ws.EventChannel))
+			b.handlers.Add(1)
+			go func() {
+				defer b.handlers.Done()
+				b.handleWebSocketEvent(b.handlersCtx, event)
+			}()
+		case <-ws.PingTimeoutChannel:
+			// server stopped pinging, the connection is silently dead, reconnect
+			b.logger.WarnContext(ctx, "Mattermost websocket ping timeout, closing connection")
+			ws.Close()
+		case <-ctx.Done():
+			return false
 		}
 	}
-	log.Fatal("Could not connect mattermost websocket, max retries exceeded")
+}
+
+func (b *PollingBot) setWebSocketConnected(connected bool) {
+	b.wsMu.Lock()
+	defer b.wsMu.Unlock()
+	if b.wsConnected && !connected {
+		b.wsDisconnected = time.Now()
+	}
+	b.wsConnected = connected
+}
+
+// CheckWebSocket returns error if the websocket is disconnected.
+func (b *PollingBot) CheckWebSocket(context.Context) error {
+	b.wsMu.Lock()
+	defer b.wsMu.Unlock()
+	if !b.wsConnected {
+		return ErrWebSocketDisconnected
+	}
+	return nil
+}
+
+// CheckAlive returns error if the websocket is disconnected longer than reconnection normally takes,
+// so the bot should be restarted.
+func (b *PollingBot) CheckAlive(context.Context) error {
+	b.wsMu.Lock()
+	defer b.wsMu.Unlock()
+	if !b.wsConnected && time.Since(b.wsDisconnected) > b.cfg.WebSocketGracePeriod {
+		return fmt.Errorf("%w for %s", ErrWebSocketDisconnected, time.Since(b.wsDisconnected).Round(time.Second))
+	}
+	return nil
+}
+
+// CheckMattermost pings Mattermost API.
+func (b *PollingBot) CheckMattermost(context.Context) error {
+	status, _, err := b.client.GetPing()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrMattermostUnreachable, err)
+	}
+	if status != model.StatusOk {
+		return fmt.Errorf("%w: status %s", ErrMattermostUnreachable, status)
+	}
+	return nil
+}
+
+// Shutdown waits for in-flight commands after Listen returned. If ctx is done earlier,
+// contexts of the commands are cancelled and ErrShutdownTimeout is returned.
+func (b *PollingBot) Shutdown(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		b.handlers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		b.logger.InfoContext(ctx, "All commands finished")
+		return nil
+	case <-ctx.Done():
+		if b.cancelHandlers != nil {
+			b.cancelHandlers()
+		}
+		return ErrShutdownTimeout
+	}
 }
 
 func (b *PollingBot) Close() {
 	if b.webSocketClient != nil {
-		log.Println("Closing mattermost websocket connection")
+		b.logger.Info("Closing mattermost websocket connection")
 		b.webSocketClient.Close()
 	}
 }
 
 func (b *PollingBot) handleWebSocketEvent(ctx context.Context, event *model.WebSocketEvent) {
-	if event.EventType() != model.WebsocketEventPosted {
+	switch event.EventType() {
+	case model.WebsocketEventPosted, model.WebsocketEventAddedToTeam, model.WebsocketEventLeaveTeam:
+	default:
+		return
+	}
+	ctx = logging.WithCorrelationID(ctx)
+	ctx, span := b.tracer.Start(ctx, "mattermost "+event.EventType(),
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(attribute.String("correlation_id", logging.CorrelationID(ctx))),
+	)
+	defer span.End()
+
+	switch event.EventType() {
+	case model.WebsocketEventPosted:
+		b.handlePostedEvent(ctx, event)
+	case model.WebsocketEventAddedToTeam:
+		b.handleTeamEvent(ctx, event, true)
+	case model.WebsocketEventLeaveTeam:
+		b.handleTeamEvent(ctx, event, false)
+	}
+}
+
+// handleTeamEvent updates the list of teams when the bot is added to a team or removed from it.
+func (b *PollingBot) handleTeamEvent(ctx context.Context, event *model.WebSocketEvent, added bool) {
+	data := event.GetData()
+	userID, _ := data["user_id"].(string)
+	teamID, _ := data["team_id"].(string)
+	if userID != b.user.Id || teamID == "" {
+		return
+	}
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("team_id", teamID))
+
+	if !added {
+		b.teams.remove(ctx, teamID)
 		return
 	}
+	if _, err := b.teams.add(ctx, teamID); err != nil {
+		b.logger.WarnContext(ctx, "Could not add team", "team_id", teamID, "error", err)
+		trace.SpanFromContext(ctx).SetStatus(codes.Error, "could not add team")
+	}
+}
+
+func (b *PollingBot) handlePostedEvent(ctx context.Context, event *model.WebSocketEvent) {
+	span := trace.SpanFromContext(ctx)
 
 	post := &model.Post{}
 	eventData, ok := event.GetData()["post"].(string)
 	if !ok {
-		log.Println("Could not cast event data to string")
+		b.logger.WarnContext(ctx, "Could not cast event data to string", "event", event.EventType())
+		span.SetStatus(codes.Error, "event data is not a string")
 		return
 	}
 	if err := json.Unmarshal([]byte(eventData), &post); err != nil {
-		log.Println("Could not unmarshal event to *model.Post")
+		b.logger.WarnContext(ctx, "Could not unmarshal event to *model.Post", "error", err)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "could not unmarshal post")
 		return
 	}
 
 	if post.UserId == b.user.Id {
 		return
 	}
+	// team_id is empty for direct and group messages
+	teamID, _ := event.GetData()["team_id"].(string)
+	channelName, _ := event.GetData()["channel_name"].(string)
+	channelType, _ := event.GetData()["channel_type"].(string)
+	span.SetAttributes(
+		attribute.String("post_id", post.Id),
+		attribute.String("user_id", post.UserId),
+		attribute.String("channel_id", post.ChannelId),
+		attribute.String("team_id", teamID),
+	)
 
-	b.handlePost(ctx, post)
+	origin := postOrigin{
+		teamID:   teamID,
+		direct:   model.ChannelType(channelType) == model.ChannelTypeDirect,
+		settings: b.teams.settingsFor(ctx, teamID),
+	}
+	if !origin.settings.allows(post.ChannelId, channelName) {
+		return
+	}
+	b.handlePost(withOrigin(ctx, origin), post)
 }
 
 func (b *PollingBot) handlePost(ctx context.Context, post *model.Post) {
-	log.Printf("Handling post: msg=%q; post=%v\n", post.Message, post)
-
-	// CSV reading for splitting a string at spaces, except spaces inside quotation marks.
-	r := csv.NewReader(strings.NewReader(post.Message))
-	r.Comma = ' '
-	tokens, err := r.Read()
-	if err != nil {
-		log.Printf("Could not split post's message: msg=%q; post=%v; %v\n", post.Message, post, err)
+	origin := originFromContext(ctx)
+	// a direct message is addressed to the bot anyway, so the mention is not required there
+	mentionOnly := origin.settings.mentionOnly() && !origin.direct
+	inv, ok := findInvocation(post.Message, origin.settings.prefix(), b.user.Username, mentionOnly)
+	if !ok {
+		if origin.direct {
+			b.handleDirectMessage(ctx, post)
+		}
 		return
 	}
-	log.Printf("Handling post tokens: %v", tokens)
-	if len(tokens) == 0 {
+	command, ok := b.commands.lookup(inv.name)
+	if !ok {
 		return
 	}
+	// aliases are reported under the name of the command, so they don't split metrics and rate limits
+	name := command.spec.name
+	b.logger.InfoContext(ctx, "Handling command", "command", name, "post_id", post.Id,
+		"user_id", post.UserId, "channel_id", post.ChannelId, "team_id", origin.teamID, "direct", origin.direct)
+
+	ctx = withLocale(ctx, b.locales.resolve(ctx, post))
+	result := b.runCommand(ctx, post, inv, command)
+	b.observer.CommandHandled(name, string(result))
+
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(attribute.String("command", name), attribute.String("outcome", string(result)))
+	if result == outcomeFailed {
+		span.SetStatus(codes.Error, "command failed")
+	}
+}
+
+func (b *PollingBot) runCommand(ctx context.Context, post *model.Post, inv invocation, command registeredCommand) outcome {
+	spec := command.spec
+	if !b.allowCommand(ctx, post, spec.name) {
+		return outcomeRateLimited
+	}
+
+	cmd, err := spec.parse(inv.message, inv.prefix)
+	if err != nil {
+		b.logger.InfoContext(ctx, "Could not parse command", "command", spec.name, "error", err)
+		b.Respond(ctx, post, ResponseError, b.tr(ctx, msgParseError, b.syntaxErrorMessage(ctx, err), spec.usage(inv.usagePrefix)))
+		return outcomeInvalid
+	}
+	cmd.Prefix = inv.usagePrefix
+	if err = spec.validate(cmd); err != nil {
+		var usageErr *UsageError
+		if errors.As(err, &usageErr) {
+			b.Respond(ctx, post, ResponseError, b.tr(ctx, msgUsage, b.tr(ctx, usageErr.Reason, usageErr.Args...), usageErr.Usage))
+		}
+		return outcomeInvalid
+	}
+
+	return command.handler(ctx, post, cmd)
+}
+
+// tr formats the message in the language of the handled post.
+func (b *PollingBot) tr(ctx context.Context, key messageKey, args ...any) string {
+	return b.translator.T(localeFromContext(ctx), key, args...)
+}
+
+// syntaxErrorMessage explains to the user why the command could not be parsed.
+func (b *PollingBot) syntaxErrorMessage(ctx context.Context, err error) string {
+	var syntaxErr *SyntaxError
+	switch {
+	case errors.As(err, &syntaxErr) && errors.Is(err, ErrUnterminatedQuote):
+		return b.tr(ctx, msgUnterminatedQuote, syntaxErr.Pos)
+	case errors.Is(err, ErrEmptyFlagName):
+		return b.tr(ctx, msgEmptyFlagName)
+	default:
+		return b.tr(ctx, msgParseErrorUnknown)
+	}
+}
+
+// allowCommand checks rate limits of the command and politely asks to slow down once per window.
+func (b *PollingBot) allowCommand(ctx context.Context, post *model.Post, command string) bool {
+	err := b.rateLimiter.Allow(command, post.UserId, post.ChannelId)
+	if err == nil {
+		return true
+	}
 
-	switch tokens[0] {
-	case "!poll_start":
-		b.handleStart(ctx, post, tokens[1:])
-	case "!poll_vote":
-		b.handleVote(ctx, post, tokens[1:])
-	case "!poll_results":
-		b.handleResults(ctx, post, tokens[1:])
-	case "!poll_close":
-		b.handleClose(ctx, post, tokens[1:])
-	case "!poll_delete":
-		b.handleDelete(ctx, post, tokens[1:])
-	case "!help":
-		b.handleHelp(ctx, post, tokens[1:])
+	var rateErr *RateLimitError
+	if !errors.As(err, &rateErr) {
+		b.logger.ErrorContext(ctx, "Failed to check rate limit", "error", err)
+		return false
+	}
+	b.logger.InfoContext(ctx, "Command is rate limited", "command", command, "user_id", post.UserId,
+		"channel_id", post.ChannelId)
+	if rateErr.Notify {
+		b.Respond(ctx, post, ResponseError, b.tr(ctx, msgRateLimited, rateErr.RetryAfter.Round(time.Second)))
 	}
+	return false
 }
 
-func (b *PollingBot) Respond(_ context.Context, post *model.Post, msg string) {
+// Respond replies to the post in its thread, publicly or only to its author depending on the kind of response.
+// Direct messages are answered without a thread, like in a chat.
+func (b *PollingBot) Respond(ctx context.Context, post *model.Post, kind ResponseKind, msg string) {
+	direct := originFromContext(ctx).direct
 	resp := &model.Post{}
 	resp.ChannelId = post.ChannelId
 	resp.Message = msg
-	resp.RootId = post.RootId
-	if post.RootId == "" {
-		resp.RootId = post.Id
+	if !direct {
+		resp.RootId = threadRootID(post)
+	}
+
+	// direct channel is private anyway, and ephemeral posts disappear after reload
+	if !direct && b.responses.of(kind) == VisibilityEphemeral {
+		_, _, err := b.client.CreatePostEphemeral(&model.PostEphemeral{UserID: post.UserId, Post: resp})
+		if err == nil {
+			return
+		}
+		b.logger.WarnContext(ctx, "Could not respond with ephemeral post, responding publicly",
+			"post_id", post.Id, "kind", kind, "error", err)
 	}
 
 	if _, _, err := b.client.CreatePost(resp); err != nil {
-		log.Printf("Could not respond to post: msg=%q; post=%v; %v\n", msg, post, err)
+		b.logger.ErrorContext(ctx, "Could not respond to post", "post_id", post.Id, "error", err)
 	}
 }
 
-func (b *PollingBot) handleStart(ctx context.Context, post *model.Post, args []string) {
-	// !poll_start "[question]" "[option1]" "[option2]" ...
-	if len(args) < pollStartMinArgsCount {
-		b.Respond(ctx, post, "Too few arguments. May be you didn't write the options?")
+// RespondPrivately responds to the post so that only its user sees the message, whatever visibility is configured.
+// The message is sent as an ephemeral post, or as a direct message if the ephemeral post fails.
+func (b *PollingBot) RespondPrivately(ctx context.Context, post *model.Post, msg string) {
+	if originFromContext(ctx).direct {
+		// direct channel is private anyway
+		if _, _, err := b.client.CreatePost(&model.Post{ChannelId: post.ChannelId, Message: msg}); err != nil {
+			b.logger.ErrorContext(ctx, "Could not respond to post", "post_id", post.Id, "error", err)
+		}
 		return
 	}
 
-	options := make([]domain.PollOption, len(args)-1)
-	for i := 1; i < len(args); i++ {
-		options[i-1] = *domain.NewPollOption(args[i])
+	resp := &model.Post{ChannelId: post.ChannelId, RootId: threadRootID(post), Message: msg}
+	_, _, err := b.client.CreatePostEphemeral(&model.PostEphemeral{UserID: post.UserId, Post: resp})
+	if err == nil {
+		return
+	}
+	b.logger.WarnContext(ctx, "Could not respond with ephemeral post, responding with direct message",
+		"post_id", post.Id, "error", err)
+	if err = b.SendDirect(ctx, post.UserId, msg); err != nil {
+		b.logger.ErrorContext(ctx, "Could not respond to post", "post_id", post.Id, "error", err)
+	}
+}
+
+// SendDirect sends the message to the user in the direct channel with the bot.
+func (b *PollingBot) SendDirect(ctx context.Context, userID string, msg string) error {
+	channel, _, err := b.client.CreateDirectChannel(b.user.Id, userID)
+	if err != nil {
+		return fmt.Errorf("could not open direct channel: %w", err)
+	}
+	if _, _, err = b.client.CreatePost(&model.Post{ChannelId: channel.Id, Message: msg}); err != nil {
+		return fmt.Errorf("could not send direct message: %w", err)
 	}
+	b.logger.DebugContext(ctx, "Direct message sent", "user_id", userID)
+	return nil
+}
 
-	poll := domain.NewPoll(args[0], options, post.UserId)
+func (b *PollingBot) handleStart(ctx context.Context, post *model.Post, cmd *Command) outcome {
+	// !poll_start "[question]" "[option1]" "[option2]" ...
+	// or multi-line:
+	// !poll_start [question]
+	// - [option1]
+	// - [option2]
+	question, texts := pollFromCommand(cmd)
+	origin := originFromContext(ctx)
+	scaleValue, rating := cmd.Flag("rating")
+	switch {
+	case len(texts) > 0:
+	case rating:
+		// the question itself is rated
+		texts = []string{question}
+	default:
+		texts = origin.settings.DefaultOptions
+	}
+	options := make([]domain.PollOption, len(texts))
+	for i, text := range texts {
+		options[i] = *domain.NewPollOption(text)
+	}
+
+	poll := domain.NewPoll(question, options, post.UserId)
+	poll.TeamID = origin.teamID
+	poll.ChannelID = post.ChannelId
+	poll.NotifyAuthor = cmd.BoolFlag("dm-summary")
+	if rating {
+		scale, err := domain.ParseScale(scaleValue)
+		if err != nil {
+			b.Respond(ctx, post, ResponseError, b.tr(ctx, msgInvalidScale))
+			return outcomeRejected
+		}
+		poll.Type = domain.PollRating
+		poll.Scale = scale
+	}
+	if value, ok := cmd.Flag("remind-every"); ok {
+		remindEvery, err := time.ParseDuration(value)
+		if err != nil || remindEvery < minRemindInterval {
+			b.Respond(ctx, post, ResponseError, b.tr(ctx, msgRemindIntervalInvalid, minRemindInterval))
+			return outcomeRejected
+		}
+		poll.RemindEvery = remindEvery
+	}
+	if value, ok := cmd.Flag("quorum"); ok {
+		quorum, err := ParseQuorum(value)
+		if err != nil {
+			b.Respond(ctx, post, ResponseError, b.tr(ctx, msgInvalidQuorum))
+			return outcomeRejected
+		}
+		poll.Quorum = quorum
+	}
+	if value, ok := cmd.Flag("close-in"); ok {
+		closeIn, err := time.ParseDuration(value)
+		if err != nil || closeIn < scheduleInterval {
+			b.Respond(ctx, post, ResponseError, b.tr(ctx, msgCloseInInvalid, scheduleInterval))
+			return outcomeRejected
+		}
+		poll.ClosesAt = time.Now().Add(closeIn)
+	}
+	if value, ok := cmd.Flag("results"); ok {
+		visibility, err := domain.ParseResultsVisibility(value)
+		if err != nil {
+			b.Respond(ctx, post, ResponseError, b.tr(ctx, msgInvalidResultsVisibility))
+			return outcomeRejected
+		}
+		poll.ResultsVisibility = visibility
+	}
+	if value, ok := cmd.Flag("suggestions"); ok {
+		mode, err := domain.ParseSuggestionMode(value)
+		if err != nil {
+			b.Respond(ctx, post, ResponseError, b.tr(ctx, msgInvalidSuggestionMode))
+			return outcomeRejected
+		}
+		poll.Suggestions = mode
+	}
+	poll.Weighted = cmd.BoolFlag("weighted")
+	if value, ok := cmd.Flag("weights"); ok {
+		weights, result := b.pollWeights(ctx, post, value)
+		if result != outcomeOK {
+			return result
+		}
+		poll.Weighted = true
+		poll.Weights = weights
+	}
 	if err := b.pollService.CreatePoll(ctx, poll); err != nil {
-		log.Printf("Failed to create poll: %v\n", err)
-		b.Respond(ctx, post, "Failed to start poll. Try again")
-		return
+		if errors.Is(err, usecase.ErrTooManyActivePolls) {
+			b.Respond(ctx, post, ResponseError, b.tr(ctx, msgTooManyActivePolls))
+			return outcomeRejected
+		}
+		var validationErr *domain.ValidationError
+		if errors.As(err, &validationErr) {
+			b.Respond(ctx, post, ResponseError, b.validationMessage(ctx, validationErr))
+			return outcomeRejected
+		}
+		b.logger.ErrorContext(ctx, "Failed to create poll", "error", err)
+		b.Respond(ctx, post, ResponseError, b.tr(ctx, msgStartFailed))
+		return outcomeFailed
 	}
-	log.Printf("Poll succesfully created: %v", poll)
+	b.logger.InfoContext(ctx, "Poll succesfully created", "poll_id", poll.ID)
 
 	var msgBuilder strings.Builder
 	if err := func() error {
-		if _, err := msgBuilder.WriteString("Poll succesfully created!\nID: "); err != nil {
+		if _, err := msgBuilder.WriteString(b.tr(ctx, msgPollCreated, poll.ID)); err != nil {
 			return err
 		}
-		if _, err := msgBuilder.WriteString(poll.ID); err != nil {
-			return err
+		for _, option := range poll.Options {
+			if _, err := msgBuilder.WriteString(fmt.Sprintf("\n%d. %s", option.ID, option.Text)); err != nil {
+				return err
+			}
 		}
-		for i, option := range options {
-			if _, err := msgBuilder.WriteString(fmt.Sprintf("\n%d. %s", i, option.Text)); err != nil {
+		if poll.Type == domain.PollRating {
+			if _, err := msgBuilder.WriteString(b.tr(ctx, msgRatingHint, poll.Scale.Min, poll.Scale.Max, cmd.Prefix, poll.ID)); err != nil {
+				return err
+			}
+		}
+		if poll.Weighted {
+			if _, err := msgBuilder.WriteString(b.tr(ctx, msgPollWeighted)); err != nil {
 				return err
 			}
 		}
 		return nil
 	}(); err != nil {
-		log.Printf("Failed to build response message: %v", err)
-		b.Respond(ctx, post, "Failed to start poll. Try again")
-		return
+		b.logger.ErrorContext(ctx, "Failed to build response message", "error", err)
+		b.Respond(ctx, post, ResponseError, b.tr(ctx, msgStartFailed))
+		return outcomeFailed
 	}
 
-	b.Respond(ctx, post, msgBuilder.String())
+	b.Respond(ctx, post, ResponseCreated, msgBuilder.String())
+	return outcomeOK
 }
 
-func (b *PollingBot) handleVote(ctx context.Context, post *model.Post, args []string) {
-	// !poll_vote [pollID] [vote]
-	if len(args) != pollVoteArgsCount {
-		b.Respond(ctx, post, "There must be 2 arguments: poll ID and option's number")
-		return
+// pollFromCommand extracts question and options of !poll_start.
+// In the multi-line form the first line is the question, unless it consists of several quoted arguments,
+// and every following line is an option.
+func pollFromCommand(cmd *Command) (string, []string) {
+	if len(cmd.Body) == 0 {
+		return cmd.Args[0], cmd.Args[1:]
+	}
+
+	var question string
+	var options []string
+	switch {
+	case len(cmd.Args) > 1 && cmd.ArgsQuoted():
+		question, options = cmd.Args[0], cmd.Args[1:]
+	case len(cmd.Args) > 0:
+		question = strings.Join(cmd.Args, " ")
+	}
+	for _, line := range cmd.Body {
+		items := ParseListItem(line)
+		if question == "" {
+			question, items = items[0], items[1:]
+		}
+		options = append(options, items...)
+	}
+	return question, options
+}
+
+// validationMessage explains to the user what is wrong with the poll.
+func (b *PollingBot) validationMessage(ctx context.Context, err *domain.ValidationError) string {
+	switch {
+	case errors.Is(err, domain.ErrEmptyQuestion):
+		return b.tr(ctx, msgEmptyQuestion)
+	case errors.Is(err, domain.ErrQuestionTooLong):
+		return b.tr(ctx, msgQuestionTooLong, err.Limit)
+	case errors.Is(err, domain.ErrTooFewOptions):
+		return b.tr(ctx, msgTooFewOptions, err.Limit)
+	case errors.Is(err, domain.ErrTooManyOptions):
+		return b.tr(ctx, msgTooManyOptions, err.Limit)
+	case errors.Is(err, domain.ErrEmptyOption):
+		return b.tr(ctx, msgEmptyOption, err.Option)
+	case errors.Is(err, domain.ErrOptionTooLong):
+		return b.tr(ctx, msgOptionTooLong, err.Option, err.Limit)
+	case errors.Is(err, domain.ErrDuplicateOption):
+		return b.tr(ctx, msgDuplicateOption, err.Option)
+	case errors.Is(err, domain.ErrInvalidQuorum):
+		return b.tr(ctx, msgInvalidQuorum)
+	case errors.Is(err, domain.ErrInvalidScale):
+		return b.tr(ctx, msgInvalidScale)
+	case errors.Is(err, domain.ErrEmptyTitle):
+		return b.tr(ctx, msgEmptyTitle)
+	case errors.Is(err, domain.ErrTitleTooLong):
+		return b.tr(ctx, msgTitleTooLong, err.Limit)
+	case errors.Is(err, domain.ErrTooFewQuestions):
+		return b.tr(ctx, msgTooFewQuestions, err.Limit)
+	case errors.Is(err, domain.ErrTooManyQuestions):
+		return b.tr(ctx, msgTooManyQuestions, err.Limit)
+	case errors.Is(err, domain.ErrOptionsNotAllowed):
+		return b.tr(ctx, msgQuestionOptionsNotAllowed)
+	case errors.Is(err, domain.ErrAnswerTooLong):
+		return b.tr(ctx, msgAnswerTooLong, err.Limit)
+	case errors.Is(err, domain.ErrInvalidWeight):
+		return b.tr(ctx, msgInvalidWeights, err.Limit)
+	default:
+		return b.tr(ctx, msgInvalidPoll)
+	}
+}
+
+func (b *PollingBot) handleVote(ctx context.Context, post *model.Post, cmd *Command) outcome {
+	// !poll_vote [pollID] [vote]...
+	answer := &domain.Answer{UserID: post.UserId, PollID: cmd.Args[0]}
+	votes := make([]int, len(cmd.Args)-1)
+	for i, arg := range cmd.Args[1:] {
+		vote, err := strconv.Atoi(arg)
+		if err != nil {
+			b.Respond(ctx, post, ResponseError, b.tr(ctx, msgVoteNotInteger))
+			return outcomeRejected
+		}
+		votes[i] = vote
 	}
 
-	var err error
-	answer := &domain.Answer{}
-	answer.UserID = post.UserId
-	answer.PollID = args[0]
-	answer.Vote, err = strconv.Atoi(args[1])
+	poll, err := b.pollService.GetPollByID(ctx, answer.PollID)
 	if err != nil {
-		b.Respond(ctx, post, "Vote must be an integer: option's number")
-		return
+		if errors.Is(err, usecase.ErrPollNotFound) {
+			b.Respond(ctx, post, ResponseError, b.tr(ctx, msgVotePollNotFound))
+			return outcomeRejected
+		}
+		b.logger.ErrorContext(ctx, "Failed to get poll", "poll_id", answer.PollID, "error", err)
+		b.Respond(ctx, post, ResponseError, b.tr(ctx, msgVoteFailed))
+		return outcomeFailed
+	}
+	if poll.Type == domain.PollRating {
+		if len(votes) != len(poll.Options) {
+			b.Respond(ctx, post, ResponseError, b.tr(ctx, msgInvalidScores, len(poll.Options), poll.Scale.Min, poll.Scale.Max))
+			return outcomeRejected
+		}
+		// scores are given in the order of options
+		answer.Scores = make(map[int]int, len(votes))
+		for i, option := range poll.Options {
+			answer.Scores[option.ID] = votes[i]
+		}
+	} else {
+		if len(votes) > 1 {
+			b.Respond(ctx, post, ResponseError, b.tr(ctx, msgTooManyArguments, 2, len(cmd.Args)))
+			return outcomeRejected
+		}
+		answer.OptionID = votes[0]
+	}
+	// polls with their own weights don't need weights from settings
+	if poll.Weighted && len(poll.Weights) == 0 {
+		if answer.Weight, err = b.weights.resolve(post.UserId); err != nil {
+			b.logger.ErrorContext(ctx, "Failed to resolve weight of vote", "poll_id", answer.PollID, "error", err)
+			b.Respond(ctx, post, ResponseError, b.tr(ctx, msgVoteFailed))
+			return outcomeFailed
+		}
 	}
 
 	if err = b.pollService.AddAnswer(ctx, answer); err != nil {
 		if errors.Is(err, usecase.ErrAnswerAlreadyExists) {
-			b.Respond(ctx, post, "You have already voted in this poll")
-			return
+			b.Respond(ctx, post, ResponseError, b.tr(ctx, msgAlreadyVoted))
+			return outcomeRejected
 		}
 		if errors.Is(err, usecase.ErrPollNotFound) {
-			b.Respond(ctx, post, "There is no poll with such ID. May be poll was deleted?")
-			return
+			b.Respond(ctx, post, ResponseError, b.tr(ctx, msgVotePollNotFound))
+			return outcomeRejected
 		}
 		if errors.Is(err, usecase.ErrPollIsNotActive) {
-			b.Respond(ctx, post, "Poll is closed, you can not vote")
-			return
+			b.Respond(ctx, post, ResponseError, b.tr(ctx, msgVotePollClosed))
+			return outcomeRejected
 		}
 		if errors.Is(err, usecase.ErrNoSuchOption) {
-			b.Respond(ctx, post, "There are not so many options. Try again")
-			return
+			b.Respond(ctx, post, ResponseError, b.tr(ctx, msgNoSuchOption))
+			return outcomeRejected
 		}
-		log.Printf("Failed to add answer: %v\n", err)
-		b.Respond(ctx, post, "Failed to vote in this poll. Try again")
-		return
+		if errors.Is(err, domain.ErrInvalidScore) {
+			b.Respond(ctx, post, ResponseError, b.tr(ctx, msgInvalidScores, len(poll.Options), poll.Scale.Min, poll.Scale.Max))
+			return outcomeRejected
+		}
+		b.logger.ErrorContext(ctx, "Failed to add answer", "poll_id", answer.PollID, "error", err)
+		b.Respond(ctx, post, ResponseError, b.tr(ctx, msgVoteFailed))
+		return outcomeFailed
 	}
 
-	b.Respond(ctx, post, "Vote successfully registered")
+	b.Respond(ctx, post, ResponseConfirmation, b.tr(ctx, msgVoteRegistered))
+	return outcomeOK
 }
 
-func (b *PollingBot) handleResults(ctx context.Context, post *model.Post, args []string) {
+func (b *PollingBot) handleResults(ctx context.Context, post *model.Post, cmd *Command) outcome {
 	// !poll_results [pollID]
-	if len(args) != 1 {
-		b.Respond(ctx, post, "There must be 1 argument: poll ID")
-		return
-	}
-
-	pollID := args[0]
-	poll, err := b.pollService.GetPollByID(ctx, pollID)
+	pollID := cmd.Args[0]
+	results, err := b.pollService.GetResults(ctx, pollID, post.UserId)
 	if err != nil {
 		if errors.Is(err, usecase.ErrPollNotFound) {
-			b.Respond(ctx, post, "There is no poll with such ID. Try again")
-			return
+			b.Respond(ctx, post, ResponseError, b.tr(ctx, msgPollNotFound))
+			return outcomeRejected
 		}
-		log.Printf("Failed to get poll results: %v\n", err)
-		b.Respond(ctx, post, "Failed to obtain poll results. Try again")
-		return
+		var hiddenErr *usecase.ResultsHiddenError
+		if errors.As(err, &hiddenErr) {
+			b.Respond(ctx, post, ResponseError, b.hiddenResultsMessage(ctx, hiddenErr))
+			return outcomeRejected
+		}
+		b.logger.ErrorContext(ctx, "Failed to get poll results", "poll_id", pollID, "error", err)
+		b.Respond(ctx, post, ResponseError, b.tr(ctx, msgResultsFailed))
+		return outcomeFailed
+	}
+
+	msg, err := b.resultsMessage(ctx, results.Poll)
+	if err != nil {
+		b.logger.ErrorContext(ctx, "Failed to build response message", "error", err)
+		b.Respond(ctx, post, ResponseError, b.tr(ctx, msgResultsFailed))
+		return outcomeFailed
+	}
+
+	// results which are hidden from other members are shown only to the author or a voter who asked
+	if !results.PublishResults {
+		b.RespondPrivately(ctx, post, msg)
+		return outcomeOK
 	}
+	b.Respond(ctx, post, ResponseResults, msg)
+	return outcomeOK
+}
+
+// hiddenResultsMessage explains when results will be visible and shows participation.
+func (b *PollingBot) hiddenResultsMessage(ctx context.Context, err *usecase.ResultsHiddenError) string {
+	switch err.Visibility {
+	case domain.ResultsAfterVote:
+		return b.tr(ctx, msgResultsAfterVote, err.Voters)
+	case domain.ResultsAfterClose:
+		return b.tr(ctx, msgResultsAfterClose, err.Voters)
+	default:
+		return b.tr(ctx, msgResultsAuthorOnly, err.Voters)
+	}
+}
 
+// resultsMessage formats the question, votes of every option and participation, if the poll has a quorum.
+func (b *PollingBot) resultsMessage(ctx context.Context, poll *domain.Poll) (string, error) {
 	var msgBuilder strings.Builder
-	if err = func() error {
-		if _, err = msgBuilder.WriteString(poll.Question); err != nil {
-			return err
+	if poll.QuorumNotReached {
+		if _, err := msgBuilder.WriteString(b.tr(ctx, msgResultsNoQuorum)); err != nil {
+			return "", err
 		}
-		for i, option := range poll.Options {
-			if _, err = msgBuilder.WriteString(fmt.Sprintf("\n%d. %s\nVotes: %d", i, option.Text, option.Votes)); err != nil {
-				return err
+	}
+	if _, err := msgBuilder.WriteString(poll.Question); err != nil {
+		return "", err
+	}
+	if poll.Type == domain.PollRating {
+		if _, err := msgBuilder.WriteString(b.ratingResults(ctx, poll)); err != nil {
+			return "", err
+		}
+	} else {
+		for _, option := range poll.Options {
+			msg := b.tr(ctx, msgResultsOption, option.ID, option.Text, option.Votes)
+			if poll.Weighted {
+				msg = b.tr(ctx, msgResultsWeightedOption, option.ID, option.Text, option.Votes, option.Weight)
+			}
+			if _, err := msgBuilder.WriteString(msg); err != nil {
+				return "", err
 			}
 		}
-		return nil
-	}(); err != nil {
-		log.Printf("Failed to build response message: %v", err)
-		b.Respond(ctx, post, "Failed to obtain poll results. Try again")
-		return
 	}
 
-	b.Respond(ctx, post, msgBuilder.String())
+	if poll.Quorum.IsSet() {
+		members, err := b.quorumMembers(poll)
+		if err != nil {
+			return "", err
+		}
+		key := msgResultsQuorum
+		if poll.QuorumReached(members) {
+			key = msgResultsQuorumReached
+		}
+		if _, err = msgBuilder.WriteString(b.tr(ctx, key, poll.Voters(), poll.Quorum.Required(members))); err != nil {
+			return "", err
+		}
+	}
+	return msgBuilder.String(), nil
 }
 
-func (b *PollingBot) handleClose(ctx context.Context, post *model.Post, args []string) {
-	// !poll_close [pollID]
-	if len(args) != 1 {
-		b.Respond(ctx, post, "There must be 1 argument: poll ID")
-		return
+func (b *PollingBot) handleClose(ctx context.Context, post *model.Post, cmd *Command) outcome {
+	// !poll_close [--force] [pollID]
+	pollID := cmd.Args[0]
+	opts := usecase.CloseOptions{
+		Force: cmd.BoolFlag("force"),
+		Members: func(_ context.Context, poll *domain.Poll) (int, error) {
+			return b.quorumMembers(poll)
+		},
 	}
-
-	pollID := args[0]
-	if err := b.pollService.ClosePollByID(ctx, pollID, post.UserId); err != nil {
+	closed, err := b.pollService.ClosePollByID(ctx, pollID, post.UserId, opts)
+	if err != nil {
 		if errors.Is(err, usecase.ErrPollNotFound) {
-			b.Respond(ctx, post, "Failed to close poll: there is no poll with such ID. Try again")
-			return
+			b.Respond(ctx, post, ResponseError, b.tr(ctx, msgClosePollNotFound))
+			return outcomeRejected
 		}
 		if errors.Is(err, usecase.ErrUserIsNotPollAuthor) {
-			b.Respond(ctx, post, "You can not close this poll, only author can")
-			return
+			b.Respond(ctx, post, ResponseError, b.tr(ctx, msgCloseNotAuthor))
+			return outcomeRejected
 		}
-		log.Printf("Failed to close poll: %v\n", err)
-		b.Respond(ctx, post, "Failed to close poll. Try again")
-		return
+		var quorumErr *usecase.QuorumNotReachedError
+		if errors.As(err, &quorumErr) {
+			b.Respond(ctx, post, ResponseError,
+				b.tr(ctx, msgCloseNoQuorum, quorumErr.Voters, quorumErr.Required, cmd.Prefix, pollID))
+			return outcomeRejected
+		}
+		b.logger.ErrorContext(ctx, "Failed to close poll", "poll_id", pollID, "error", err)
+		b.Respond(ctx, post, ResponseError, b.tr(ctx, msgCloseFailed))
+		return outcomeFailed
 	}
 
-	b.Respond(ctx, post, "Poll succesfully closed")
+	b.Respond(ctx, post, ResponseConfirmation, b.tr(ctx, msgPollClosed))
+	pollCtx := withLocale(ctx, b.locales.userLocale(ctx, closed.Poll.Author))
+	if err = b.announceClosed(pollCtx, closed, msgPollClosedResults, msgPollClosedHidden); err != nil {
+		b.logger.WarnContext(ctx, "Could not post results of closed poll", "poll_id", pollID, "error", err)
+	}
+	b.sendClosedSummary(pollCtx, pollID)
+	return outcomeOK
 }
 
-func (b *PollingBot) handleDelete(ctx context.Context, post *model.Post, args []string) {
-	// !poll_delete [pollID]
-	if len(args) != 1 {
-		b.Respond(ctx, post, "There must be 1 argument: poll ID")
+// announceClosed posts to the channel of the closed poll its results, or only count of voters
+// if the usecase doesn't allow to publish them. Results of hidden polls are sent to the author
+// in a direct message, if requested.
+func (b *PollingBot) announceClosed(
+	ctx context.Context, closed *usecase.PollResults, resultsKey messageKey, hiddenKey messageKey,
+) error {
+	poll := closed.Poll
+	msg := b.tr(ctx, hiddenKey, poll.ID, poll.Voters())
+	if closed.PublishResults {
+		results, err := b.resultsMessage(ctx, poll)
+		if err != nil {
+			return fmt.Errorf("could not build results: %w", err)
+		}
+		msg = b.tr(ctx, resultsKey, poll.ID, results)
+	}
+	if _, _, err := b.client.CreatePost(&model.Post{ChannelId: poll.ChannelID, Message: msg}); err != nil {
+		return fmt.Errorf("could not create post: %w", err)
+	}
+	return nil
+}
+
+// sendClosedSummary sends results of the closed poll to its author, if the author asked for it.
+func (b *PollingBot) sendClosedSummary(ctx context.Context, pollID string) {
+	poll, err := b.pollService.GetPollByID(ctx, pollID)
+	if err != nil {
+		b.logger.WarnContext(ctx, "Could not get closed poll for summary", "poll_id", pollID, "error", err)
 		return
 	}
+	if !poll.NotifyAuthor {
+		return
+	}
+
+	ctx = withLocale(ctx, b.locales.userLocale(ctx, poll.Author))
+	results, err := b.resultsMessage(ctx, poll)
+	if err != nil {
+		b.logger.WarnContext(ctx, "Could not build summary of closed poll", "poll_id", pollID, "error", err)
+		return
+	}
+	if err = b.SendDirect(ctx, poll.Author, b.tr(ctx, msgClosedSummary, poll.ID, results)); err != nil {
+		b.logger.WarnContext(ctx, "Could not send summary of closed poll", "poll_id", pollID, "error", err)
+	}
+}
 
-	pollID := args[0]
+func (b *PollingBot) handleDelete(ctx context.Context, post *model.Post, cmd *Command) outcome {
+	// !poll_delete [pollID]
+	pollID := cmd.Args[0]
 	if err := b.pollService.DeletePollByID(ctx, pollID, post.UserId); err != nil {
 		if errors.Is(err, usecase.ErrPollNotFound) {
-			b.Respond(ctx, post, "Failed to delete poll: there is no poll with such ID. Try again")
-			return
+			b.Respond(ctx, post, ResponseError, b.tr(ctx, msgDeletePollNotFound))
+			return outcomeRejected
 		}
 		if errors.Is(err, usecase.ErrUserIsNotPollAuthor) {
-			b.Respond(ctx, post, "You can not delete this poll, only author can")
-			return
+			b.Respond(ctx, post, ResponseError, b.tr(ctx, msgDeleteNotAuthor))
+			return outcomeRejected
 		}
-		log.Printf("Failed to delete poll: %v\n", err)
-		b.Respond(ctx, post, "Failed to delete poll. Try again")
-		return
+		b.logger.ErrorContext(ctx, "Failed to delete poll", "poll_id", pollID, "error", err)
+		b.Respond(ctx, post, ResponseError, b.tr(ctx, msgDeleteFailed))
+		return outcomeFailed
 	}
 
-	b.Respond(ctx, post, "Poll succesfully deleted")
+	b.Respond(ctx, post, ResponseConfirmation, b.tr(ctx, msgPollDeleted))
+	return outcomeOK
 }
 
-func (b *PollingBot) handleHelp(ctx context.Context, post *model.Post, _ []string) {
-	// !help
-	b.Respond(ctx, post, `Available commands:
-	* !help - info about commands
-
-	* !poll_start "[question]" "[option1]" "[option2]" ... - creates a poll and returns poll's ID. 
-	IMPORTANT: question and options must be quoted.
+func (b *PollingBot) handleHelp(ctx context.Context, post *model.Post, cmd *Command) outcome {
+	// !help [command]
+	if len(cmd.Args) == 0 {
+		b.Respond(ctx, post, ResponseHelp, b.commands.helpText(b.translator, localeFromContext(ctx), cmd.Prefix))
+		return outcomeOK
+	}
 
-	* !poll_vote [pollID] [vote] - register user's vote. Parameter [vote] is number of option in the list of options.
-	
-	* !poll_results [pollID] - shows poll's results.
-	
-	* !poll_close [pollID] - author of poll can close it.
-	
-	* !poll_delete [pollID] - author of poll can delete it.`)
+	name := strings.TrimPrefix(cmd.Args[0], originFromContext(ctx).settings.prefix())
+	command, ok := b.commands.lookup(name)
+	if !ok {
+		b.Respond(ctx, post, ResponseError, b.tr(ctx, msgUnknownCommand, name, cmd.Prefix))
+		return outcomeRejected
+	}
+	b.Respond(ctx, post, ResponseHelp, command.spec.help(b.translator, localeFromContext(ctx), cmd.Prefix))
+	return outcomeOK
 }
@@ -0,0 +1,174 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/Xausdorf/mattermost-poll/internal/domain"
+	"github.com/Xausdorf/mattermost-poll/internal/usecase"
+)
+
+// mattermost - fake Mattermost API which records posts created by the bot.
+type mattermost struct {
+	mu sync.Mutex
+	// failEphemeral - ephemeral posts are rejected.
+	failEphemeral bool
+	public        []*model.Post
+	ephemeral     []*model.Post
+	// direct - posts to direct channels, which are named after the user.
+	direct []*model.Post
+}
+
+func (m *mattermost) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	switch r.URL.Path {
+	case "/api/v4/posts":
+		var post model.Post
+		if err := json.NewDecoder(r.Body).Decode(&post); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if strings.HasPrefix(post.ChannelId, "direct-") {
+			m.direct = append(m.direct, &post)
+		} else {
+			m.public = append(m.public, &post)
+		}
+		writeJSON(w, &post)
+	case "/api/v4/posts/ephemeral":
+		if m.failEphemeral {
+			http.Error(w, "ephemeral posts are disabled", http.StatusForbidden)
+			return
+		}
+		var ephemeral model.PostEphemeral
+		if err := json.NewDecoder(r.Body).Decode(&ephemeral); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		m.ephemeral = append(m.ephemeral, ephemeral.Post)
+		writeJSON(w, ephemeral.Post)
+	case "/api/v4/channels/direct":
+		var users []string
+		if err := json.NewDecoder(r.Body).Decode(&users); err != nil || len(users) != 2 {
+			http.Error(w, "invalid users", http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, &model.Channel{Id: "direct-" + users[1], Type: model.ChannelTypeDirect})
+	case "/api/v4/users/ids":
+		var ids []string
+		if err := json.NewDecoder(r.Body).Decode(&ids); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		users := make([]*model.User, 0, len(ids))
+		for _, id := range ids {
+			users = append(users, &model.User{Id: id, Username: "name-" + id})
+		}
+		writeJSON(w, users)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// posts returns counts of public, ephemeral and direct posts.
+func (m *mattermost) posts() (public int, ephemeral int, direct int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.public), len(m.ephemeral), len(m.direct)
+}
+
+// pollService - usecases used by handlers under test, others panic because the interface is nil.
+type pollService struct {
+	PollService
+	results     *usecase.PollResults
+	survey      *domain.Survey
+	textAnswers []*domain.TextAnswer
+}
+
+func (s *pollService) GetResults(_ context.Context, _ string, _ string) (*usecase.PollResults, error) {
+	return s.results, nil
+}
+
+func (s *pollService) TextAnswers(
+	_ context.Context, _ string, _ int, _ usecase.Sender,
+) (*domain.Survey, []*domain.TextAnswer, error) {
+	return s.survey, s.textAnswers, nil
+}
+
+// newTestBot returns the bot connected to the fake Mattermost API.
+func newTestBot(t *testing.T, service PollService) (*PollingBot, *mattermost) {
+	t.Helper()
+	api := &mattermost{}
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+
+	translator, err := NewTranslator("en")
+	if err != nil {
+		t.Fatalf("NewTranslator() error: %v", err)
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	client := model.NewAPIv4Client(server.URL)
+	return &PollingBot{
+		client:      client,
+		user:        &model.User{Id: "bot"},
+		pollService: service,
+		responses:   newResponsePolicy(nil),
+		translator:  translator,
+		locales:     newLocaleResolver(client, nil, logger),
+		tracer:      noop.NewTracerProvider().Tracer(""),
+		logger:      logger,
+	}, api
+}
+
+func TestHandleResultsVisibility(t *testing.T) {
+	tests := []struct {
+		name          string
+		publish       bool
+		direct        bool
+		wantPublic    int
+		wantEphemeral int
+	}{
+		{name: "results visible to everyone", publish: true, wantPublic: 1},
+		{name: "results hidden from other members", publish: false, wantEphemeral: 1},
+		{name: "hidden results in direct channel", publish: false, direct: true, wantPublic: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			poll := &domain.Poll{
+				ID:       "p1",
+				Question: "Lunch?",
+				Author:   "author",
+				Options:  []domain.PollOption{{ID: 0, Text: "Pizza", Votes: 2}, {ID: 1, Text: "Sushi", Votes: 1}},
+			}
+			b, api := newTestBot(t, &pollService{
+				results: &usecase.PollResults{Poll: poll, PublishResults: tt.publish},
+			})
+			ctx := withOrigin(context.Background(), postOrigin{direct: tt.direct})
+			post := &model.Post{Id: "post", ChannelId: "channel", UserId: "author"}
+
+			if got := b.handleResults(ctx, post, &Command{Prefix: "!", Args: []string{"p1"}}); got != outcomeOK {
+				t.Fatalf("handleResults() = %v, want %v", got, outcomeOK)
+			}
+			public, ephemeral, _ := api.posts()
+			if public != tt.wantPublic || ephemeral != tt.wantEphemeral {
+				t.Errorf("public posts %d, ephemeral posts %d, want %d, %d",
+					public, ephemeral, tt.wantPublic, tt.wantEphemeral)
+			}
+		})
+	}
+}
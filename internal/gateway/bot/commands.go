@@ -0,0 +1,357 @@
+package bot
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// UsageError - command doesn't match its specification, Usage is generated from the specification.
+// Reason is a message key, formatted with Args.
+type UsageError struct {
+	Reason messageKey
+	Args   []any
+	Usage  string
+}
+
+func (e *UsageError) Error() string {
+	return fmt.Sprintf("%s %v", e.Reason, e.Args)
+}
+
+type argSpec struct {
+	name        string
+	description messageKey
+	optional    bool
+	// variadic - argument can be repeated, only the last argument can be variadic.
+	variadic bool
+}
+
+type flagSpec struct {
+	name string
+	// value - placeholder of flag's value, empty for boolean flags.
+	value       string
+	description messageKey
+}
+
+// commandSpec - specification of a command, used for validation of arguments and for help.
+type commandSpec struct {
+	name        string
+	description messageKey
+	args        []argSpec
+	flags       []flagSpec
+	// details - additional notes shown by `!help <command>`.
+	details messageKey
+	// multiline - arguments can be given on the following lines, see ParseMultilineCommand.
+	multiline bool
+}
+
+func commandSpecs() []commandSpec {
+	return []commandSpec{
+		{
+			name:        "help",
+			description: msgCmdHelp,
+			args: []argSpec{
+				{name: "command", description: msgArgHelpCommand, optional: true},
+			},
+		},
+		{
+			name:        "poll_start",
+			description: msgCmdPollStart,
+			args: []argSpec{
+				{name: "question", description: msgArgQuestion},
+				{name: "option", description: msgArgOption, optional: true, variadic: true},
+			},
+			flags: []flagSpec{
+				{name: "dm-summary", description: msgFlagDMSummary},
+				{name: "remind-every", value: "duration", description: msgFlagRemindEvery},
+				{name: "quorum", value: "count|percent%", description: msgFlagQuorum},
+				{name: "close-in", value: "duration", description: msgFlagCloseIn},
+				{name: "results", value: "always|voted|closed|author", description: msgFlagResults},
+				{name: "suggestions", value: "off|open|approval", description: msgFlagSuggestions},
+				{name: "rating", value: "min-max|nps", description: msgFlagRating},
+				{name: "weighted", description: msgFlagWeighted},
+				{name: "weights", value: "user=weight,...", description: msgFlagWeights},
+			},
+			details:   msgDetailsPollStart,
+			multiline: true,
+		},
+		{
+			name:        "poll_vote",
+			description: msgCmdPollVote,
+			args: []argSpec{
+				{name: "pollID", description: msgArgPollID},
+				{name: "vote", description: msgArgVote, variadic: true},
+			},
+		},
+		{
+			name:        "poll_results",
+			description: msgCmdPollResults,
+			args: []argSpec{
+				{name: "pollID", description: msgArgPollID},
+			},
+		},
+		{
+			name:        "poll_close",
+			description: msgCmdPollClose,
+			args: []argSpec{
+				{name: "pollID", description: msgArgPollID},
+			},
+			flags: []flagSpec{
+				{name: "force", description: msgFlagForce},
+			},
+		},
+		{
+			name:        "poll_delete",
+			description: msgCmdPollDelete,
+			args: []argSpec{
+				{name: "pollID", description: msgArgPollID},
+			},
+		},
+		{
+			name:        "poll_remind",
+			description: msgCmdPollRemind,
+			args: []argSpec{
+				{name: "pollID", description: msgArgPollID},
+			},
+		},
+		{
+			name:        "poll_dnd",
+			description: msgCmdPollDnd,
+			args: []argSpec{
+				{name: "on|off", description: msgArgDndMode, optional: true},
+			},
+		},
+		{
+			name:        "poll_reopen",
+			description: msgCmdPollReopen,
+			args: []argSpec{
+				{name: "pollID", description: msgArgPollID},
+			},
+		},
+		{
+			name:        "poll_extend",
+			description: msgCmdPollExtend,
+			args: []argSpec{
+				{name: "pollID", description: msgArgPollID},
+				{name: "duration", description: msgArgExtendDuration},
+			},
+		},
+		{
+			name:        "poll_option_add",
+			description: msgCmdPollOptionAdd,
+			args: []argSpec{
+				{name: "pollID", description: msgArgPollID},
+				{name: "text", description: msgArgOptionText},
+			},
+		},
+		{
+			name:        "poll_option_edit",
+			description: msgCmdPollOptionEdit,
+			args: []argSpec{
+				{name: "pollID", description: msgArgPollID},
+				{name: "option", description: msgArgOptionNumber},
+				{name: "text", description: msgArgOptionText},
+			},
+		},
+		{
+			name:        "poll_option_remove",
+			description: msgCmdPollOptionRemove,
+			args: []argSpec{
+				{name: "pollID", description: msgArgPollID},
+				{name: "option", description: msgArgOptionNumber},
+			},
+			flags: []flagSpec{
+				{name: "move-to", value: "option", description: msgFlagMoveTo},
+			},
+		},
+		{
+			name:        "poll_suggest",
+			description: msgCmdPollSuggest,
+			args: []argSpec{
+				{name: "pollID", description: msgArgPollID},
+				{name: "text", description: msgArgOptionText},
+			},
+		},
+		{
+			name:        "poll_suggestions",
+			description: msgCmdPollSuggestions,
+			args: []argSpec{
+				{name: "pollID", description: msgArgPollID},
+			},
+		},
+		{
+			name:        "poll_approve",
+			description: msgCmdPollApprove,
+			args: []argSpec{
+				{name: "suggestionID", description: msgArgSuggestionID},
+			},
+		},
+		{
+			name:        "poll_reject",
+			description: msgCmdPollReject,
+			args: []argSpec{
+				{name: "suggestionID", description: msgArgSuggestionID},
+			},
+		},
+		{
+			name:        "survey_start",
+			description: msgCmdSurveyStart,
+			args: []argSpec{
+				{name: "title", description: msgArgSurveyTitle},
+			},
+			details:   msgDetailsSurveyStart,
+			multiline: true,
+		},
+		{
+			name:        "survey_take",
+			description: msgCmdSurveyTake,
+			args: []argSpec{
+				{name: "surveyID", description: msgArgSurveyID},
+			},
+		},
+		{
+			name:        "survey_results",
+			description: msgCmdSurveyResults,
+			args: []argSpec{
+				{name: "surveyID", description: msgArgSurveyID},
+			},
+		},
+		{
+			name:        "survey_close",
+			description: msgCmdSurveyClose,
+			args: []argSpec{
+				{name: "surveyID", description: msgArgSurveyID},
+			},
+		},
+		{
+			name:        "survey_answers",
+			description: msgCmdSurveyAnswers,
+			args: []argSpec{
+				{name: "surveyID", description: msgArgSurveyID},
+				{name: "question", description: msgArgQuestionNumber},
+			},
+			flags: []flagSpec{
+				{name: "page", value: "number", description: msgFlagPage},
+			},
+		},
+		{
+			name:        "survey_export",
+			description: msgCmdSurveyExport,
+			args: []argSpec{
+				{name: "surveyID", description: msgArgSurveyID},
+				{name: "question", description: msgArgQuestionNumber},
+			},
+		},
+		{
+			name:        "survey_publish",
+			description: msgCmdSurveyPublish,
+			args: []argSpec{
+				{name: "surveyID", description: msgArgSurveyID},
+				{name: "question", description: msgArgQuestionNumber},
+				{name: "answer", description: msgArgAnswerNumber, variadic: true},
+			},
+		},
+	}
+}
+
+// usage returns a single line like "!poll_vote [--flag=value] <pollID> <vote>".
+func (s commandSpec) usage(prefix string) string {
+	parts := []string{prefix + s.name}
+	for _, flag := range s.flags {
+		if flag.value == "" {
+			parts = append(parts, "[--"+flag.name+"]")
+		} else {
+			parts = append(parts, "[--"+flag.name+"="+flag.value+"]")
+		}
+	}
+	for _, arg := range s.args {
+		name := "<" + arg.name + ">"
+		if arg.variadic {
+			name += "..."
+		}
+		if arg.optional {
+			name = "[" + name + "]"
+		}
+		parts = append(parts, name)
+	}
+	return strings.Join(parts, " ")
+}
+
+// help returns detailed description of the command with its arguments and flags.
+func (s commandSpec) help(t *Translator, locale string, prefix string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "`%s` - %s", s.usage(prefix), t.T(locale, s.description))
+	for _, arg := range s.args {
+		fmt.Fprintf(&b, "\n* `<%s>` - %s", arg.name, t.T(locale, arg.description))
+	}
+	for _, flag := range s.flags {
+		fmt.Fprintf(&b, "\n* `--%s` - %s", flag.name, t.T(locale, flag.description))
+	}
+	if s.details != "" {
+		b.WriteString("\n")
+		b.WriteString(t.T(locale, s.details))
+	}
+	return b.String()
+}
+
+// parse parses the message according to the specification.
+func (s commandSpec) parse(msg string, prefix string) (*Command, error) {
+	if s.multiline && strings.Contains(strings.TrimSpace(msg), "\n") {
+		return ParseMultilineCommand(msg, prefix)
+	}
+	return ParseCommand(msg, prefix)
+}
+
+// validate checks count of arguments and flags of the command.
+// Count of arguments of a multi-line command is not checked, if it has a body.
+func (s commandSpec) validate(cmd *Command) error {
+	var required, total int
+	variadic := false
+	for _, arg := range s.args {
+		total++
+		if !arg.optional {
+			required++
+		}
+		variadic = variadic || arg.variadic
+	}
+
+	switch {
+	case len(cmd.Body) > 0:
+	case len(cmd.Args) < required:
+		return s.usageError(cmd, msgTooFewArguments, required, len(cmd.Args))
+	case !variadic && len(cmd.Args) > total:
+		return s.usageError(cmd, msgTooManyArguments, total, len(cmd.Args))
+	}
+
+	for name, value := range cmd.Flags {
+		flag, ok := s.findFlag(name)
+		if !ok {
+			return s.usageError(cmd, msgUnknownFlag, name)
+		}
+		if flag.value == "" {
+			if _, err := strconv.ParseBool(value); value != "" && err != nil {
+				return s.usageError(cmd, msgInvalidFlagBoolean, name)
+			}
+		} else if value == "" {
+			return s.usageError(cmd, msgFlagRequiresValue, name, name, flag.value)
+		}
+	}
+	return nil
+}
+
+func (s commandSpec) findFlag(name string) (flagSpec, bool) {
+	for _, flag := range s.flags {
+		if flag.name == name {
+			return flag, true
+		}
+	}
+	return flagSpec{}, false
+}
+
+func (s commandSpec) usageError(cmd *Command, reason messageKey, args ...any) *UsageError {
+	return &UsageError{
+		Reason: reason,
+		Args:   args,
+		Usage:  s.usage(cmd.Prefix),
+	}
+}
@@ -0,0 +1,133 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Xausdorf/mattermost-poll/internal/usecase"
+	"github.com/mattermost/mattermost-server/v6/model"
+)
+
+// deadlineLayout - format of poll deadlines in responses.
+const deadlineLayout = "2006-01-02 15:04 MST"
+
+func (b *PollingBot) handleReopen(ctx context.Context, post *model.Post, cmd *Command) outcome {
+	// !poll_reopen [pollID]
+	pollID := cmd.Args[0]
+	sender := b.sender(post)
+
+	if err := b.pollService.ReopenPoll(ctx, pollID, sender); err != nil {
+		if errors.Is(err, usecase.ErrPollNotFound) {
+			b.Respond(ctx, post, ResponseError, b.tr(ctx, msgPollNotFound))
+			return outcomeRejected
+		}
+		if errors.Is(err, usecase.ErrPollDeleted) {
+			b.Respond(ctx, post, ResponseError, b.tr(ctx, msgPollWasDeleted))
+			return outcomeRejected
+		}
+		if errors.Is(err, usecase.ErrUserIsNotPollAuthor) {
+			b.Respond(ctx, post, ResponseError, b.tr(ctx, msgReopenNotAllowed))
+			return outcomeRejected
+		}
+		if errors.Is(err, usecase.ErrPollIsActive) {
+			b.Respond(ctx, post, ResponseError, b.tr(ctx, msgReopenPollActive))
+			return outcomeRejected
+		}
+		if errors.Is(err, usecase.ErrTooManyActivePolls) {
+			b.Respond(ctx, post, ResponseError, b.tr(ctx, msgReopenTooManyActive))
+			return outcomeRejected
+		}
+		b.logger.ErrorContext(ctx, "Failed to reopen poll", "poll_id", pollID, "error", err)
+		b.Respond(ctx, post, ResponseError, b.tr(ctx, msgReopenFailed))
+		return outcomeFailed
+	}
+
+	b.Respond(ctx, post, ResponseConfirmation, b.tr(ctx, msgPollReopened, pollID))
+	return outcomeOK
+}
+
+func (b *PollingBot) handleExtend(ctx context.Context, post *model.Post, cmd *Command) outcome {
+	// !poll_extend [pollID] [duration]
+	pollID := cmd.Args[0]
+	d, err := time.ParseDuration(cmd.Args[1])
+	if err != nil || d < scheduleInterval {
+		b.Respond(ctx, post, ResponseError, b.tr(ctx, msgExtendInvalid, scheduleInterval))
+		return outcomeInvalid
+	}
+	sender := b.sender(post)
+
+	poll, err := b.pollService.ExtendPoll(ctx, pollID, sender, d)
+	if err != nil {
+		if errors.Is(err, usecase.ErrPollNotFound) {
+			b.Respond(ctx, post, ResponseError, b.tr(ctx, msgPollNotFound))
+			return outcomeRejected
+		}
+		if errors.Is(err, usecase.ErrPollDeleted) {
+			b.Respond(ctx, post, ResponseError, b.tr(ctx, msgPollWasDeleted))
+			return outcomeRejected
+		}
+		if errors.Is(err, usecase.ErrUserIsNotPollAuthor) {
+			b.Respond(ctx, post, ResponseError, b.tr(ctx, msgExtendNotAllowed))
+			return outcomeRejected
+		}
+		if errors.Is(err, usecase.ErrPollIsNotActive) {
+			b.Respond(ctx, post, ResponseError, b.tr(ctx, msgExtendPollClosed, cmd.Prefix, pollID))
+			return outcomeRejected
+		}
+		if errors.Is(err, usecase.ErrPollHasNoDeadline) {
+			b.Respond(ctx, post, ResponseError, b.tr(ctx, msgExtendNoDeadline))
+			return outcomeRejected
+		}
+		b.logger.ErrorContext(ctx, "Failed to extend poll", "poll_id", pollID, "error", err)
+		b.Respond(ctx, post, ResponseError, b.tr(ctx, msgExtendFailed))
+		return outcomeFailed
+	}
+
+	b.Respond(ctx, post, ResponseConfirmation,
+		b.tr(ctx, msgPollExtended, pollID, poll.ClosesAt.UTC().Format(deadlineLayout)))
+	return outcomeOK
+}
+
+// sender returns the author of the post. Permissions to manage polls of other users are checked by the usecase
+// against the channel of the poll, not the channel where the command was sent.
+func (b *PollingBot) sender(post *model.Post) usecase.Sender {
+	return usecase.Sender{ID: post.UserId, Roles: senderRoles{bot: b, userID: post.UserId}}
+}
+
+// senderRoles - roles of the author of a command in Mattermost.
+type senderRoles struct {
+	bot    *PollingBot
+	userID string
+}
+
+func (r senderRoles) IsAdmin(ctx context.Context, channelID string) (bool, error) {
+	return r.bot.isAdmin(ctx, r.userID, channelID)
+}
+
+// isAdmin returns true if the user is a system admin, or a member and an admin of the channel.
+func (b *PollingBot) isAdmin(ctx context.Context, userID string, channelID string) (bool, error) {
+	user, _, err := b.client.GetUser(userID, "")
+	if err != nil {
+		return false, fmt.Errorf("could not get user %s: %w", userID, err)
+	}
+	if user.IsSystemAdmin() {
+		return true, nil
+	}
+	if channelID == "" {
+		// polls started before channels were saved can be managed only by system admins
+		return false, nil
+	}
+	member, resp, err := b.client.GetChannelMember(channelID, userID, "")
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("could not get member %s of channel %s: %w", userID, channelID, err)
+	}
+	b.logger.DebugContext(ctx, "Checked admin permissions", "user_id", userID, "channel_id", channelID,
+		"channel_admin", member.SchemeAdmin)
+	return member.SchemeAdmin, nil
+}
@@ -0,0 +1,104 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+)
+
+// userLocaleTTL - how long user's locale is cached, users rarely change it.
+const userLocaleTTL = time.Hour
+
+type localeContextKey struct{}
+
+func withLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeContextKey{}, locale)
+}
+
+func localeFromContext(ctx context.Context) string {
+	locale, _ := ctx.Value(localeContextKey{}).(string)
+	return locale
+}
+
+// ParseChannelLocales parses overrides in the form "channelID=ru,otherChannelID=en".
+func ParseChannelLocales(s string) (map[string]string, error) {
+	locales := make(map[string]string)
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		channelID, locale, ok := strings.Cut(item, "=")
+		if !ok || channelID == "" || locale == "" {
+			return nil, fmt.Errorf("channel locale %q must be in the form channelID=locale", item)
+		}
+		locales[strings.TrimSpace(channelID)] = strings.TrimSpace(locale)
+	}
+	return locales, nil
+}
+
+type cachedLocale struct {
+	locale    string
+	fetchedAt time.Time
+}
+
+// localeResolver - picks language of responses: channel override, then user's Mattermost locale.
+type localeResolver struct {
+	client *model.Client4
+	logger *slog.Logger
+
+	mu             sync.Mutex
+	channelLocales map[string]string
+	users          map[string]cachedLocale
+}
+
+func newLocaleResolver(client *model.Client4, channelLocales map[string]string, logger *slog.Logger) *localeResolver {
+	return &localeResolver{
+		client:         client,
+		channelLocales: channelLocales,
+		logger:         logger,
+		users:          make(map[string]cachedLocale),
+	}
+}
+
+func (r *localeResolver) setChannelLocales(channelLocales map[string]string) {
+	r.mu.Lock()
+	r.channelLocales = channelLocales
+	r.mu.Unlock()
+}
+
+func (r *localeResolver) resolve(ctx context.Context, post *model.Post) string {
+	r.mu.Lock()
+	locale, ok := r.channelLocales[post.ChannelId]
+	r.mu.Unlock()
+	if ok {
+		return locale
+	}
+	return r.userLocale(ctx, post.UserId)
+}
+
+func (r *localeResolver) userLocale(ctx context.Context, userID string) string {
+	r.mu.Lock()
+	cached, ok := r.users[userID]
+	r.mu.Unlock()
+	if ok && time.Since(cached.fetchedAt) < userLocaleTTL {
+		return cached.locale
+	}
+
+	user, _, err := r.client.GetUser(userID, "")
+	if err != nil {
+		r.logger.WarnContext(ctx, "Could not get user's locale", "user_id", userID, "error", err)
+		// default locale is used until the user is fetched successfully
+		return ""
+	}
+
+	r.mu.Lock()
+	r.users[userID] = cachedLocale{locale: user.Locale, fetchedAt: time.Now()}
+	r.mu.Unlock()
+	return user.Locale
+}
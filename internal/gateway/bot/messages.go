@@ -0,0 +1,327 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// messageKey - key of a bot response in message catalogs. Values of catalogs are fmt format strings.
+type messageKey string
+
+const (
+	msgParseError                  messageKey = "parse_error"
+	msgUnterminatedQuote           messageKey = "unterminated_quote"
+	msgEmptyFlagName               messageKey = "empty_flag_name"
+	msgUsage                       messageKey = "usage"
+	msgTooFewArguments             messageKey = "too_few_arguments"
+	msgTooManyArguments            messageKey = "too_many_arguments"
+	msgUnknownFlag                 messageKey = "unknown_flag"
+	msgInvalidFlagBoolean          messageKey = "invalid_flag_boolean"
+	msgFlagRequiresValue           messageKey = "flag_requires_value"
+	msgRateLimited                 messageKey = "rate_limited"
+	msgUnknownCommand              messageKey = "unknown_command"
+	msgAvailableCommands           messageKey = "available_commands"
+	msgHelpForDetails              messageKey = "help_for_details"
+	msgTooManyActivePolls          messageKey = "too_many_active_polls"
+	msgStartFailed                 messageKey = "start_failed"
+	msgPollCreated                 messageKey = "poll_created"
+	msgEmptyQuestion               messageKey = "empty_question"
+	msgQuestionTooLong             messageKey = "question_too_long"
+	msgTooFewOptions               messageKey = "too_few_options"
+	msgTooManyOptions              messageKey = "too_many_options"
+	msgEmptyOption                 messageKey = "empty_option"
+	msgOptionTooLong               messageKey = "option_too_long"
+	msgDuplicateOption             messageKey = "duplicate_option"
+	msgInvalidPoll                 messageKey = "invalid_poll"
+	msgVoteNotInteger              messageKey = "vote_not_integer"
+	msgAlreadyVoted                messageKey = "already_voted"
+	msgVotePollNotFound            messageKey = "vote_poll_not_found"
+	msgVotePollClosed              messageKey = "vote_poll_closed"
+	msgNoSuchOption                messageKey = "no_such_option"
+	msgVoteFailed                  messageKey = "vote_failed"
+	msgVoteRegistered              messageKey = "vote_registered"
+	msgPollNotFound                messageKey = "poll_not_found"
+	msgResultsFailed               messageKey = "results_failed"
+	msgResultsOption               messageKey = "results_option"
+	msgClosePollNotFound           messageKey = "close_poll_not_found"
+	msgCloseNotAuthor              messageKey = "close_not_author"
+	msgCloseFailed                 messageKey = "close_failed"
+	msgPollClosed                  messageKey = "poll_closed"
+	msgDeletePollNotFound          messageKey = "delete_poll_not_found"
+	msgDeleteNotAuthor             messageKey = "delete_not_author"
+	msgDeleteFailed                messageKey = "delete_failed"
+	msgPollDeleted                 messageKey = "poll_deleted"
+	msgCmdHelp                     messageKey = "cmd_help"
+	msgArgHelpCommand              messageKey = "arg_help_command"
+	msgCmdPollStart                messageKey = "cmd_poll_start"
+	msgArgQuestion                 messageKey = "arg_question"
+	msgArgOption                   messageKey = "arg_option"
+	msgDetailsPollStart            messageKey = "details_poll_start"
+	msgCmdPollVote                 messageKey = "cmd_poll_vote"
+	msgArgPollID                   messageKey = "arg_poll_id"
+	msgArgVote                     messageKey = "arg_vote"
+	msgCmdPollResults              messageKey = "cmd_poll_results"
+	msgCmdPollClose                messageKey = "cmd_poll_close"
+	msgCmdPollDelete               messageKey = "cmd_poll_delete"
+	msgParseErrorUnknown           messageKey = "parse_error_unknown"
+	msgFlagDMSummary               messageKey = "flag_dm_summary"
+	msgClosedSummary               messageKey = "closed_summary"
+	msgFlagRemindEvery             messageKey = "flag_remind_every"
+	msgRemindIntervalInvalid       messageKey = "remind_interval_invalid"
+	msgCmdPollRemind               messageKey = "cmd_poll_remind"
+	msgCmdPollDnd                  messageKey = "cmd_poll_dnd"
+	msgArgDndMode                  messageKey = "arg_dnd_mode"
+	msgRemindNotAuthor             messageKey = "remind_not_author"
+	msgRemindPollClosed            messageKey = "remind_poll_closed"
+	msgRemindFailed                messageKey = "remind_failed"
+	msgRemindSent                  messageKey = "remind_sent"
+	msgRemindNobody                messageKey = "remind_nobody"
+	msgReminderMention             messageKey = "reminder_mention"
+	msgReminderDirect              messageKey = "reminder_direct"
+	msgDndInvalid                  messageKey = "dnd_invalid"
+	msgDndFailed                   messageKey = "dnd_failed"
+	msgDndOn                       messageKey = "dnd_on"
+	msgDndOff                      messageKey = "dnd_off"
+	msgFlagQuorum                  messageKey = "flag_quorum"
+	msgFlagCloseIn                 messageKey = "flag_close_in"
+	msgFlagForce                   messageKey = "flag_force"
+	msgInvalidQuorum               messageKey = "invalid_quorum"
+	msgCloseInInvalid              messageKey = "close_in_invalid"
+	msgCloseNoQuorum               messageKey = "close_no_quorum"
+	msgResultsNoQuorum             messageKey = "results_no_quorum"
+	msgResultsQuorum               messageKey = "results_quorum"
+	msgResultsQuorumReached        messageKey = "results_quorum_reached"
+	msgPollExpired                 messageKey = "poll_expired"
+	msgFlagResults                 messageKey = "flag_results"
+	msgInvalidResultsVisibility    messageKey = "invalid_results_visibility"
+	msgResultsAfterVote            messageKey = "results_after_vote"
+	msgResultsAfterClose           messageKey = "results_after_close"
+	msgResultsAuthorOnly           messageKey = "results_author_only"
+	msgPollExpiredHidden           messageKey = "poll_expired_hidden"
+	msgPollClosedResults           messageKey = "poll_closed_results"
+	msgPollClosedHidden            messageKey = "poll_closed_hidden"
+	msgCmdPollReopen               messageKey = "cmd_poll_reopen"
+	msgCmdPollExtend               messageKey = "cmd_poll_extend"
+	msgArgExtendDuration           messageKey = "arg_extend_duration"
+	msgPollWasDeleted              messageKey = "poll_was_deleted"
+	msgReopenNotAllowed            messageKey = "reopen_not_allowed"
+	msgReopenPollActive            messageKey = "reopen_poll_active"
+	msgReopenTooManyActive         messageKey = "reopen_too_many_active"
+	msgReopenFailed                messageKey = "reopen_failed"
+	msgPollReopened                messageKey = "poll_reopened"
+	msgExtendInvalid               messageKey = "extend_invalid"
+	msgExtendNotAllowed            messageKey = "extend_not_allowed"
+	msgExtendPollClosed            messageKey = "extend_poll_closed"
+	msgExtendNoDeadline            messageKey = "extend_no_deadline"
+	msgExtendFailed                messageKey = "extend_failed"
+	msgPollExtended                messageKey = "poll_extended"
+	msgCmdPollOptionAdd            messageKey = "cmd_poll_option_add"
+	msgCmdPollOptionEdit           messageKey = "cmd_poll_option_edit"
+	msgCmdPollOptionRemove         messageKey = "cmd_poll_option_remove"
+	msgArgOptionNumber             messageKey = "arg_option_number"
+	msgArgOptionText               messageKey = "arg_option_text"
+	msgFlagMoveTo                  messageKey = "flag_move_to"
+	msgOptionNotInteger            messageKey = "option_not_integer"
+	msgOptionNotFound              messageKey = "option_not_found"
+	msgOptionsNotAllowed           messageKey = "options_not_allowed"
+	msgOptionsPollClosed           messageKey = "options_poll_closed"
+	msgOptionsFailed               messageKey = "options_failed"
+	msgOptionAdded                 messageKey = "option_added"
+	msgOptionEdited                messageKey = "option_edited"
+	msgOptionRemoved               messageKey = "option_removed"
+	msgOptionRemovedMoved          messageKey = "option_removed_moved"
+	msgFlagSuggestions             messageKey = "flag_suggestions"
+	msgInvalidSuggestionMode       messageKey = "invalid_suggestion_mode"
+	msgCmdPollSuggest              messageKey = "cmd_poll_suggest"
+	msgCmdPollSuggestions          messageKey = "cmd_poll_suggestions"
+	msgCmdPollApprove              messageKey = "cmd_poll_approve"
+	msgCmdPollReject               messageKey = "cmd_poll_reject"
+	msgArgSuggestionID             messageKey = "arg_suggestion_id"
+	msgSuggestionPending           messageKey = "suggestion_pending"
+	msgSuggestionReceived          messageKey = "suggestion_received"
+	msgSuggestionsHeader           messageKey = "suggestions_header"
+	msgSuggestionItem              messageKey = "suggestion_item"
+	msgNoSuggestions               messageKey = "no_suggestions"
+	msgSuggestionRejected          messageKey = "suggestion_rejected"
+	msgSuggestionsNotAllowed       messageKey = "suggestions_not_allowed"
+	msgSuggestionsDisabled         messageKey = "suggestions_disabled"
+	msgTooManySuggestions          messageKey = "too_many_suggestions"
+	msgSuggestionExists            messageKey = "suggestion_exists"
+	msgSuggestionNotFound          messageKey = "suggestion_not_found"
+	msgSuggestionReviewed          messageKey = "suggestion_reviewed"
+	msgSuggestionsFailed           messageKey = "suggestions_failed"
+	msgFlagRating                  messageKey = "flag_rating"
+	msgInvalidScale                messageKey = "invalid_scale"
+	msgRatingHint                  messageKey = "rating_hint"
+	msgInvalidScores               messageKey = "invalid_scores"
+	msgRatingSummary               messageKey = "rating_summary"
+	msgRatingNPS                   messageKey = "rating_nps"
+	msgCannotMoveScores            messageKey = "cannot_move_scores"
+	msgEmptyTitle                  messageKey = "empty_title"
+	msgTitleTooLong                messageKey = "title_too_long"
+	msgTooFewQuestions             messageKey = "too_few_questions"
+	msgTooManyQuestions            messageKey = "too_many_questions"
+	msgQuestionOptionsNotAllowed   messageKey = "question_options_not_allowed"
+	msgCmdSurveyStart              messageKey = "cmd_survey_start"
+	msgCmdSurveyTake               messageKey = "cmd_survey_take"
+	msgCmdSurveyResults            messageKey = "cmd_survey_results"
+	msgCmdSurveyClose              messageKey = "cmd_survey_close"
+	msgArgSurveyTitle              messageKey = "arg_survey_title"
+	msgArgSurveyID                 messageKey = "arg_survey_id"
+	msgDetailsSurveyStart          messageKey = "details_survey_start"
+	msgSurveyOptionWithoutQuestion messageKey = "survey_option_without_question"
+	msgSurveyCreated               messageKey = "survey_created"
+	msgSurveyQuestionsSent         messageKey = "survey_questions_sent"
+	msgSurveyQuestion              messageKey = "survey_question"
+	msgSurveyHintSingle            messageKey = "survey_hint_single"
+	msgSurveyHintMulti             messageKey = "survey_hint_multi"
+	msgSurveyHintRating            messageKey = "survey_hint_rating"
+	msgSurveyHintText              messageKey = "survey_hint_text"
+	msgInvalidSurveyAnswer         messageKey = "invalid_survey_answer"
+	msgSurveyCompleted             messageKey = "survey_completed"
+	msgSurveyResults               messageKey = "survey_results"
+	msgSurveyQuestionAnswers       messageKey = "survey_question_answers"
+	msgSurveyOptionVotes           messageKey = "survey_option_votes"
+	msgSurveyClosed                messageKey = "survey_closed"
+	msgSurveyNotFound              messageKey = "survey_not_found"
+	msgSurveyIsClosed              messageKey = "survey_is_closed"
+	msgSurveyAlreadyCompleted      messageKey = "survey_already_completed"
+	msgSurveyNotAllowed            messageKey = "survey_not_allowed"
+	msgSurveyInvalidQuestion       messageKey = "survey_invalid_question"
+	msgAnswerTooLong               messageKey = "answer_too_long"
+	msgSurveyHintAnonymous         messageKey = "survey_hint_anonymous"
+	msgCmdSurveyAnswers            messageKey = "cmd_survey_answers"
+	msgCmdSurveyExport             messageKey = "cmd_survey_export"
+	msgCmdSurveyPublish            messageKey = "cmd_survey_publish"
+	msgArgQuestionNumber           messageKey = "arg_question_number"
+	msgArgAnswerNumber             messageKey = "arg_answer_number"
+	msgFlagPage                    messageKey = "flag_page"
+	msgInvalidPage                 messageKey = "invalid_page"
+	msgPageOutOfRange              messageKey = "page_out_of_range"
+	msgNoTextAnswers               messageKey = "no_text_answers"
+	msgTextAnswersHeader           messageKey = "text_answers_header"
+	msgTextAnswerAuthor            messageKey = "text_answer_author"
+	msgTextAnswerPublished         messageKey = "text_answer_published"
+	msgTextAnswersNextPage         messageKey = "text_answers_next_page"
+	msgTextAnswersExport           messageKey = "text_answers_export"
+	msgTextAnswersExportSent       messageKey = "text_answers_export_sent"
+	msgTextAnswerNotFound          messageKey = "text_answer_not_found"
+	msgTextAnswersPublished        messageKey = "text_answers_published"
+	msgTextAnswersPublishedCount   messageKey = "text_answers_published_count"
+	msgTextAnswersChanged          messageKey = "text_answers_changed"
+	msgSurveyQuestionNotFound      messageKey = "survey_question_not_found"
+	msgNotTextQuestion             messageKey = "not_text_question"
+	msgSurveyFailed                messageKey = "survey_failed"
+	msgFlagWeighted                messageKey = "flag_weighted"
+	msgFlagWeights                 messageKey = "flag_weights"
+	msgInvalidWeights              messageKey = "invalid_weights"
+	msgWeightUserNotFound          messageKey = "weight_user_not_found"
+	msgPollWeighted                messageKey = "poll_weighted"
+	msgResultsWeightedOption       messageKey = "results_weighted_option"
+	msgRatingWeightedSummary       messageKey = "rating_weighted_summary"
+)
+
+const defaultLocale = "en"
+
+// catalogs returns message catalogs keyed by language.
+func catalogs() map[string]map[messageKey]string {
+	return map[string]map[messageKey]string{
+		"en": messagesEn(),
+		"ru": messagesRu(),
+	}
+}
+
+// validateCatalogs checks that locales have the same keys, so a message translated only partially is found
+// at startup rather than in a chat. Declared keys and format verbs are checked by tests of catalogs.
+func validateCatalogs(catalogs map[string]map[messageKey]string) error {
+	keys := make(map[messageKey]struct{})
+	for _, catalog := range catalogs {
+		for key := range catalog {
+			keys[key] = struct{}{}
+		}
+	}
+
+	var errs []error
+	for locale, catalog := range catalogs {
+		var missing []string
+		for key := range keys {
+			if _, ok := catalog[key]; !ok {
+				missing = append(missing, string(key))
+			}
+		}
+		if len(missing) > 0 {
+			slices.Sort(missing)
+			errs = append(errs, fmt.Errorf("locale %q misses messages: %s", locale, strings.Join(missing, ", ")))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Translator - formats bot responses in the requested language.
+type Translator struct {
+	catalogs map[string]map[messageKey]string
+
+	mu       sync.RWMutex
+	fallback string
+}
+
+func NewTranslator(fallback string) (*Translator, error) {
+	t := &Translator{
+		catalogs: catalogs(),
+	}
+	if err := validateCatalogs(t.catalogs); err != nil {
+		return nil, err
+	}
+	if err := t.SetFallback(fallback); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// SetFallback changes the locale used for unsupported locales, empty locale means the default one.
+func (t *Translator) SetFallback(fallback string) error {
+	if fallback == "" {
+		fallback = defaultLocale
+	}
+	if !t.Supports(fallback) {
+		return fmt.Errorf("locale %q is not supported", fallback)
+	}
+	t.mu.Lock()
+	t.fallback = normalizeLocale(fallback)
+	t.mu.Unlock()
+	return nil
+}
+
+// Supports returns true if there is a catalog for the locale, e.g. "ru" or "ru-RU".
+func (t *Translator) Supports(locale string) bool {
+	_, ok := t.catalogs[normalizeLocale(locale)]
+	return ok
+}
+
+// T formats the message in the locale, falling back to the default locale if it is not supported.
+func (t *Translator) T(locale string, key messageKey, args ...any) string {
+	catalog, ok := t.catalogs[normalizeLocale(locale)]
+	if !ok {
+		t.mu.RLock()
+		catalog = t.catalogs[t.fallback]
+		t.mu.RUnlock()
+	}
+	format, ok := catalog[key]
+	if !ok {
+		return string(key)
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// normalizeLocale returns language part of Mattermost locale: "pt-BR" -> "pt".
+func normalizeLocale(locale string) string {
+	lang, _, _ := strings.Cut(strings.ToLower(locale), "-")
+	lang, _, _ = strings.Cut(lang, "_")
+	return lang
+}
@@ -0,0 +1,224 @@
+package bot
+
+func messagesEn() map[messageKey]string {
+	return map[messageKey]string{
+		msgParseError:                  "Could not parse the command: %s\nUsage: `%s`",
+		msgUnterminatedQuote:           "quote at position %d is not closed",
+		msgEmptyFlagName:               "flag name is empty",
+		msgParseErrorUnknown:           "invalid syntax",
+		msgUsage:                       "%s\nUsage: `%s`",
+		msgTooFewArguments:             "Too few arguments: expected at least %d, got %d",
+		msgTooManyArguments:            "Too many arguments: expected at most %d, got %d",
+		msgUnknownFlag:                 "Unknown flag --%s",
+		msgInvalidFlagBoolean:          "Flag --%s must be true or false",
+		msgFlagRequiresValue:           "Flag --%s requires a value: --%s=%s",
+		msgRateLimited:                 "You are sending commands too fast. Please wait %s and try again",
+		msgUnknownCommand:              "Unknown command %q. Use `%shelp` to see available commands",
+		msgAvailableCommands:           "Available commands:",
+		msgHelpForDetails:              "Use `%shelp <command>` for details.",
+		msgTooManyActivePolls:          "You have too many active polls. Close some of them before starting a new one",
+		msgStartFailed:                 "Failed to start poll. Try again",
+		msgPollCreated:                 "Poll successfully created!\nID: %s",
+		msgEmptyQuestion:               "Question can not be empty",
+		msgQuestionTooLong:             "Question is too long, it must be at most %d characters",
+		msgTooFewOptions:               "Poll must have at least %d option(s)",
+		msgTooManyOptions:              "Too many options, poll can have at most %d",
+		msgEmptyOption:                 "Option %d is empty",
+		msgOptionTooLong:               "Option %d is too long, it must be at most %d characters",
+		msgDuplicateOption:             "Option %d duplicates another option",
+		msgInvalidPoll:                 "Poll is not valid. Check the question and options and try again",
+		msgVoteNotInteger:              "Vote must be an integer: option's number",
+		msgAlreadyVoted:                "You have already voted in this poll",
+		msgVotePollNotFound:            "There is no poll with such ID. May be poll was deleted?",
+		msgVotePollClosed:              "Poll is closed, you can not vote",
+		msgNoSuchOption:                "There are not so many options. Try again",
+		msgVoteFailed:                  "Failed to vote in this poll. Try again",
+		msgVoteRegistered:              "Vote successfully registered",
+		msgPollNotFound:                "There is no poll with such ID. Try again",
+		msgResultsFailed:               "Failed to obtain poll results. Try again",
+		msgResultsOption:               "\n%d. %s\nVotes: %d",
+		msgClosePollNotFound:           "Failed to close poll: there is no poll with such ID. Try again",
+		msgCloseNotAuthor:              "You can not close this poll, only author can",
+		msgCloseFailed:                 "Failed to close poll. Try again",
+		msgPollClosed:                  "Poll successfully closed",
+		msgClosedSummary:               "Your poll `%s` is closed. Results:\n%s",
+		msgFlagDMSummary:               "send me the results in a direct message when the poll is closed",
+		msgFlagRemindEvery:             "remind members of the channel who have not voted every `duration`, like `24h`",
+		msgRemindIntervalInvalid:       "Reminder interval must be a duration of at least %s, like `24h`",
+		msgCmdPollRemind:               "reminds members of the channel who have not voted yet, only for the author",
+		msgCmdPollDnd:                  "turns reminders about polls off for you, or back on",
+		msgArgDndMode:                  "`on` to stop receiving reminders (default), `off` to receive them again",
+		msgRemindNotAuthor:             "Only the author of the poll can send reminders",
+		msgRemindPollClosed:            "Poll is closed, there is nothing to remind about",
+		msgRemindFailed:                "Failed to send reminders",
+		msgRemindSent:                  "Reminded members who have not voted: %d",
+		msgRemindNobody:                "Everyone has already voted",
+		msgReminderMention:             "%s, please vote in the poll `%s`: %s\nTo stop receiving reminders use `%spoll_dnd`",
+		msgReminderDirect:              "Please vote in the poll `%s`: %s\nTo stop receiving reminders use `%spoll_dnd`",
+		msgDndInvalid:                  "Expected `on` or `off`, got `%s`",
+		msgDndFailed:                   "Failed to change reminders settings",
+		msgDndOn:                       "You will not receive reminders about polls",
+		msgDndOff:                      "You will receive reminders about polls again",
+		msgFlagQuorum:                  "required participation: count of voters, like `5`, or percentage of channel members, like `60%`",
+		msgFlagCloseIn:                 "close the poll automatically after `duration`, like `72h`",
+		msgFlagForce:                   "close the poll even if its quorum is not reached",
+		msgInvalidQuorum:               "Quorum must be a positive count of voters, like `5`, or a percentage from 1 to 100, like `60%`",
+		msgCloseInInvalid:              "Closing delay must be a duration of at least %s, like `72h`",
+		msgCloseNoQuorum:               "Quorum is not reached: %d of %d required members voted. To close the poll anyway use `%spoll_close --force %s`",
+		msgResultsNoQuorum:             "**Quorum not reached**, results are not valid\n",
+		msgResultsQuorum:               "\n\nParticipation: %d of %d required",
+		msgResultsQuorumReached:        "\n\nParticipation: %d of %d required, quorum reached",
+		msgPollExpired:                 "Poll `%s` is closed automatically. Results:\n%s",
+		msgFlagResults:                 "who sees results: everyone at any time (default), users who voted, everyone after the poll is closed or only the author",
+		msgInvalidResultsVisibility:    "Results visibility must be one of `always`, `voted`, `closed` or `author`",
+		msgResultsAfterVote:            "Results are shown after you vote. Voted so far: %d",
+		msgResultsAfterClose:           "Results are shown when the poll is closed. Voted so far: %d",
+		msgResultsAuthorOnly:           "Only the author can see results of this poll. Voted so far: %d",
+		msgPollExpiredHidden:           "Poll `%s` is closed automatically. Voted: %d",
+		msgPollClosedResults:           "Poll `%s` is closed by its author. Results:\n%s",
+		msgPollClosedHidden:            "Poll `%s` is closed by its author. Voted: %d",
+		msgCmdPollReopen:               "reopens a closed poll keeping its votes, for the author or an admin",
+		msgCmdPollExtend:               "moves the deadline of a poll, for the author or an admin",
+		msgArgExtendDuration:           "how much time to add, like `24h`",
+		msgPollWasDeleted:              "Poll was deleted, it can not be changed anymore",
+		msgReopenNotAllowed:            "Only the author of the poll or an admin can reopen it",
+		msgReopenPollActive:            "Poll is not closed",
+		msgReopenTooManyActive:         "The author of the poll has too many active polls. Close some of them before reopening this one",
+		msgReopenFailed:                "Failed to reopen poll. Try again",
+		msgPollReopened:                "Poll `%s` is reopened",
+		msgExtendInvalid:               "Extension must be a duration of at least %s, like `24h`",
+		msgExtendNotAllowed:            "Only the author of the poll or an admin can extend it",
+		msgExtendPollClosed:            "Poll is closed. To reopen it use `%spoll_reopen %s`",
+		msgExtendNoDeadline:            "Poll has no deadline. Use `--close-in` when starting a poll to close it automatically",
+		msgExtendFailed:                "Failed to extend poll. Try again",
+		msgPollExtended:                "Poll `%s` will be closed at %s",
+		msgCmdPollOptionAdd:            "adds an option to a poll, for the author or an admin",
+		msgCmdPollOptionEdit:           "changes the text of an option keeping its votes, for the author or an admin",
+		msgCmdPollOptionRemove:         "removes an option from a poll, for the author or an admin",
+		msgArgOptionNumber:             "number of the option",
+		msgArgOptionText:               "text of the option",
+		msgFlagMoveTo:                  "move votes for the removed option to the option with this number. Without it the votes are discarded and their users can vote again",
+		msgOptionNotInteger:            "Option must be an integer: option's number",
+		msgOptionNotFound:              "There is no option with such number in the poll",
+		msgOptionsNotAllowed:           "Only the author of the poll or an admin can change its options",
+		msgOptionsPollClosed:           "Poll is closed, options can not be added or removed",
+		msgOptionsFailed:               "Failed to change options of the poll. Try again",
+		msgOptionAdded:                 "Option added:\n%d. %s",
+		msgOptionEdited:                "Option %d is changed, its votes are kept",
+		msgOptionRemoved:               "Option %d is removed. Discarded votes: %d, these users can vote again",
+		msgOptionRemovedMoved:          "Option %d is removed, its votes are moved to option %d: %d",
+		msgFlagSuggestions:             "let voters suggest options: added immediately (`open`) or after your approval (`approval`)",
+		msgInvalidSuggestionMode:       "Suggestions must be one of `off`, `open` or `approval`",
+		msgCmdPollSuggest:              "suggests a new option of a poll, if its author allowed suggestions",
+		msgCmdPollSuggestions:          "lists suggestions waiting for approval, for the author or an admin",
+		msgCmdPollApprove:              "adds a suggested option to the poll, for the author or an admin",
+		msgCmdPollReject:               "rejects a suggested option, for the author or an admin",
+		msgArgSuggestionID:             "ID of the suggestion",
+		msgSuggestionPending:           "Your suggestion is sent to the author of the poll for approval",
+		msgSuggestionReceived:          "@%s suggests the option \"%s\" for your poll `%s`: %s\nTo add it use `%spoll_approve %s`, to reject `%spoll_reject %s`",
+		msgSuggestionsHeader:           "Suggestions waiting for approval. Use `%spoll_approve <suggestionID>` or `%spoll_reject <suggestionID>`:",
+		msgSuggestionItem:              "`%s` %s",
+		msgNoSuggestions:               "There are no suggestions waiting for approval",
+		msgSuggestionRejected:          "Suggestion is rejected",
+		msgSuggestionsNotAllowed:       "Only the author of the poll or an admin can review suggestions",
+		msgSuggestionsDisabled:         "The author of the poll has not allowed suggestions",
+		msgTooManySuggestions:          "You have suggested too many options in this poll",
+		msgSuggestionExists:            "This option is already in the poll, waits for approval or was rejected",
+		msgSuggestionNotFound:          "There is no suggestion with such ID. Try again",
+		msgSuggestionReviewed:          "This suggestion is already approved or rejected",
+		msgSuggestionsFailed:           "Failed to handle the suggestion. Try again",
+		msgFlagRating:                  "rating poll: voters give every option a score in the range, like `1-5`, or `nps` for 0-10. Without options the question itself is rated",
+		msgInvalidScale:                "Rating scale must be a range like `1-5` with at most 11 scores, or `nps`",
+		msgRatingHint:                  "\nGive every option a score from %d to %d, in the order of options: `%spoll_vote %s <score>...`",
+		msgInvalidScores:               "Give all %d option(s) a score from %d to %d, in the order of options",
+		msgRatingSummary:               "Votes: %d, mean: %.2f, median: %.1f",
+		msgRatingNPS:                   "Promoters: %d, passives: %d, detractors: %d, NPS: %+d",
+		msgCannotMoveScores:            "Scores of a rating poll can not be moved to another option, remove the option without `--move-to`",
+		msgEmptyTitle:                  "Title of the survey can not be empty",
+		msgTitleTooLong:                "Title is too long, it must be at most %d characters",
+		msgTooFewQuestions:             "Survey must have at least %d question(s)",
+		msgTooManyQuestions:            "Too many questions, survey can have at most %d",
+		msgQuestionOptionsNotAllowed:   "Only `single` and `multi` questions can have options",
+		msgCmdSurveyStart:              "creates a survey of several questions and returns its ID",
+		msgCmdSurveyTake:               "starts the survey, or resumes it, in a direct message",
+		msgCmdSurveyResults:            "shows answers to every question of the survey",
+		msgCmdSurveyClose:              "stops accepting answers to the survey, for the author or an admin",
+		msgArgSurveyTitle:              "title of the survey",
+		msgArgSurveyID:                 "ID of the survey",
+		msgSurveyOptionWithoutQuestion: "Every line of a survey must be a question like `single: text` or an option of a `single` or `multi` question. See `%shelp survey_start`",
+		msgSurveyCreated:               "Survey successfully created!\nID: %s\n**%s**, questions: %d\nTo take it use `%ssurvey_take %s`, questions are asked in a direct message",
+		msgSurveyQuestionsSent:         "Questions are sent to you in a direct message",
+		msgSurveyQuestion:              "**%s** - question %d of %d:\n%s",
+		msgSurveyHintSingle:            "_Answer with the number of an option_",
+		msgSurveyHintMulti:             "_Answer with numbers of options separated by spaces or commas_",
+		msgSurveyHintRating:            "_Answer with a score from %d to %d_",
+		msgSurveyHintText:              "_Answer with a message_",
+		msgInvalidSurveyAnswer:         "The answer does not match the question. %s",
+		msgSurveyCompleted:             "Thank you! All questions of the survey **%s** are answered",
+		msgSurveyResults:               "**%s**\nRespondents: %d, completed: %d",
+		msgSurveyQuestionAnswers:       "Answers: %d",
+		msgSurveyOptionVotes:           "%d. %s - %d",
+		msgSurveyClosed:                "Survey successfully closed",
+		msgSurveyNotFound:              "There is no survey with such ID. Try again",
+		msgSurveyIsClosed:              "Survey is closed",
+		msgSurveyAlreadyCompleted:      "You have already answered all questions of this survey",
+		msgSurveyNotAllowed:            "Only the author of the survey or an admin can do this",
+		msgSurveyInvalidQuestion:       "Question %d: %s",
+		msgAnswerTooLong:               "Answer is too long, it must be at most %d characters",
+		msgSurveyHintAnonymous:         "_Answer with a message, it is anonymous: the author will not see who answered_",
+		msgCmdSurveyAnswers:            "lists answers to a text question page by page, for the author or an admin",
+		msgCmdSurveyExport:             "sends answers to a text question as a CSV file in a direct message, for the author or an admin",
+		msgCmdSurveyPublish:            "posts chosen answers to a text question to the channel of the survey and shows them in its results",
+		msgArgQuestionNumber:           "number of the question, starting from 1",
+		msgArgAnswerNumber:             "number of the answer in `survey_answers`",
+		msgFlagPage:                    "number of the page, starting from 1",
+		msgInvalidPage:                 "Page must be a positive number",
+		msgPageOutOfRange:              "There are only %d page(s) of answers",
+		msgNoTextAnswers:               "There are no answers to this question yet",
+		msgTextAnswersHeader:           "Answers to question %s of the survey **%s**, page %d of %d, total %d. To publish some of them use `%ssurvey_publish %s %s <answer>...`:",
+		msgTextAnswerAuthor:            " - @%s",
+		msgTextAnswerPublished:         " _(published)_",
+		msgTextAnswersNextPage:         "Next page: `%ssurvey_answers --page=%d %s %s`",
+		msgTextAnswersExport:           "Answers to question %s of the survey **%s**: %d",
+		msgTextAnswersExportSent:       "Answers are sent to you in a direct message",
+		msgTextAnswerNotFound:          "There is no answer with number %s. Use `survey_answers` to see numbers of answers",
+		msgTextAnswersPublished:        "Answers to the question \"%s\" of the survey **%s**:",
+		msgTextAnswersPublishedCount:   "Published answers: %d",
+		msgTextAnswersChanged:          "Answers have changed, list them again and try again",
+		msgSurveyQuestionNotFound:      "There is no question with such number in the survey",
+		msgNotTextQuestion:             "This is not a text question, its answers are shown by `survey_results`",
+		msgSurveyFailed:                "Failed to handle the survey. Try again",
+		msgFlagWeighted:                "votes count with weights from the settings of the bot, e.g. members of some groups have 2 votes",
+		msgFlagWeights:                 "weights of voters of this poll only, like `alice=2,bob=3`, other voters count as 1. Implies `--weighted`",
+		msgInvalidWeights:              "Weights must be in the form `username=weight`, separated by commas, with weights from 1 to %d",
+		msgWeightUserNotFound:          "There is no user with username %s",
+		msgPollWeighted:                "\n_Votes in this poll are weighted_",
+		msgResultsWeightedOption:       "\n%d. %s\nVotes: %d, weight: %d",
+		msgRatingWeightedSummary:       "Votes: %d, weight: %d, weighted mean: %.2f, weighted median: %.1f",
+		msgDeletePollNotFound:          "Failed to delete poll: there is no poll with such ID. Try again",
+		msgDeleteNotAuthor:             "You can not delete this poll, only author can",
+		msgDeleteFailed:                "Failed to delete poll. Try again",
+		msgPollDeleted:                 "Poll successfully deleted",
+		msgCmdHelp:                     "info about commands",
+		msgArgHelpCommand:              "command to show detailed help for",
+		msgCmdPollStart:                "creates a poll and returns poll's ID",
+		msgArgQuestion:                 "question of the poll",
+		msgArgOption:                   "option to vote for",
+		msgDetailsPollStart: "Question and options containing spaces must be quoted.\n" +
+			"Options can also be written one per line, optionally as a Markdown list:\n" +
+			"```\n!poll_start Where do we go for lunch?\n- Pizza\n- Sushi\n```\n" +
+			"If options are omitted, default options of the team are used, if they are configured.",
+		msgDetailsSurveyStart: "The first line is the title, every following line is a question `type: text` " +
+			"or an option of the previous question. Types: `single` and `multi` choice, `rating` with a scale " +
+			"like `rating 1-5` or `rating nps`, and `text`, or `text anonymous` to hide who answered:\n" +
+			"```\n!survey_start Sprint retro\nsingle: How was the sprint?\n- Good\n- Bad\n" +
+			"rating 1-5: Team spirit\ntext: What should we change?\n```\n" +
+			"Questions are asked one by one in a direct message, answers are saved after every question.",
+		msgCmdPollVote:    "registers your vote",
+		msgArgPollID:      "ID of the poll",
+		msgArgVote:        "number of the option, or scores of all options in their order for rating polls",
+		msgCmdPollResults: "shows poll's results",
+		msgCmdPollClose:   "author of the poll can close it",
+		msgCmdPollDelete:  "author of the poll can delete it",
+	}
+}
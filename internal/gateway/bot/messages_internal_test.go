@@ -0,0 +1,149 @@
+package bot
+
+import (
+	"go/ast"
+	"go/parser"
+	gotoken "go/token"
+	"maps"
+	"strconv"
+	"testing"
+)
+
+// declaredKeys returns values of all constants of type messageKey declared in messages.go.
+func declaredKeys(t *testing.T) []messageKey {
+	t.Helper()
+	file, err := parser.ParseFile(gotoken.NewFileSet(), "messages.go", nil, 0)
+	if err != nil {
+		t.Fatalf("could not parse messages.go: %v", err)
+	}
+	var keys []messageKey
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != gotoken.CONST {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			value, ok := spec.(*ast.ValueSpec)
+			if !ok {
+				continue
+			}
+			if ident, ok := value.Type.(*ast.Ident); !ok || ident.Name != "messageKey" {
+				continue
+			}
+			for _, v := range value.Values {
+				lit, ok := v.(*ast.BasicLit)
+				if !ok {
+					t.Fatalf("message key %v is not a string literal", value.Names)
+				}
+				key, err := strconv.Unquote(lit.Value)
+				if err != nil {
+					t.Fatalf("could not unquote message key %s: %v", lit.Value, err)
+				}
+				keys = append(keys, messageKey(key))
+			}
+		}
+	}
+	if len(keys) == 0 {
+		t.Fatal("no message keys found in messages.go")
+	}
+	return keys
+}
+
+// formatVerbs returns verbs of the format string keyed by index of their argument starting from 1,
+// so translations with explicit argument indexes like %[2]s are compared by arguments, not by position.
+func formatVerbs(format string) map[int]byte {
+	verbs := make(map[int]byte)
+	arg := 0
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		i++
+		for i < len(format) && isFormatFlag(format[i]) {
+			i++
+		}
+		if i < len(format) && format[i] == '[' {
+			end := i + 1
+			for end < len(format) && format[end] != ']' {
+				end++
+			}
+			n, err := strconv.Atoi(format[i+1 : end])
+			if err == nil {
+				arg = n - 1
+			}
+			i = end + 1
+		}
+		for i < len(format) && (format[i] >= '0' && format[i] <= '9' || format[i] == '.') {
+			i++
+		}
+		if i >= len(format) || format[i] == '%' {
+			continue
+		}
+		arg++
+		verbs[arg] = format[i]
+	}
+	return verbs
+}
+
+func isFormatFlag(c byte) bool {
+	return c == '-' || c == '+' || c == '#' || c == ' ' || c == '0'
+}
+
+func TestCatalogsHaveEveryDeclaredKey(t *testing.T) {
+	keys := declaredKeys(t)
+	all := catalogs()
+	reference := all[defaultLocale]
+	for _, key := range keys {
+		format, ok := reference[key]
+		if !ok {
+			t.Errorf("locale %q misses message %q", defaultLocale, key)
+			continue
+		}
+		want := formatVerbs(format)
+		for locale, catalog := range all {
+			translation, ok := catalog[key]
+			if !ok {
+				t.Errorf("locale %q misses message %q", locale, key)
+				continue
+			}
+			if got := formatVerbs(translation); !maps.Equal(got, want) {
+				t.Errorf("locale %q, message %q: verbs %v, want %v as in %q", locale, key, got, want, defaultLocale)
+			}
+		}
+	}
+}
+
+func TestCatalogsHaveNoUndeclaredKeys(t *testing.T) {
+	declared := make(map[messageKey]bool)
+	for _, key := range declaredKeys(t) {
+		declared[key] = true
+	}
+	for locale, catalog := range catalogs() {
+		for key := range catalog {
+			if !declared[key] {
+				t.Errorf("locale %q has message %q which is not declared", locale, key)
+			}
+		}
+	}
+}
+
+func TestFormatVerbs(t *testing.T) {
+	tests := []struct {
+		format string
+		want   map[int]byte
+	}{
+		{format: "no verbs", want: map[int]byte{}},
+		{format: "100%% done", want: map[int]byte{}},
+		{format: "%d. %s", want: map[int]byte{1: 'd', 2: 's'}},
+		{format: "%[2]s %[1]d", want: map[int]byte{1: 'd', 2: 's'}},
+		{format: "%.2f and %5d", want: map[int]byte{1: 'f', 2: 'd'}},
+		{format: "%-10s|%+d", want: map[int]byte{1: 's', 2: 'd'}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			if got := formatVerbs(tt.format); !maps.Equal(got, tt.want) {
+				t.Errorf("formatVerbs(%q) = %v, want %v", tt.format, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,224 @@
+package bot
+
+func messagesRu() map[messageKey]string {
+	return map[messageKey]string{
+		msgParseError:                  "Не удалось разобрать команду: %s\nСинтаксис: `%s`",
+		msgUnterminatedQuote:           "кавычка в позиции %d не закрыта",
+		msgEmptyFlagName:               "пустое имя опции",
+		msgParseErrorUnknown:           "неверный синтаксис",
+		msgUsage:                       "%s\nСинтаксис: `%s`",
+		msgTooFewArguments:             "Слишком мало аргументов: нужно хотя бы %d, передано %d",
+		msgTooManyArguments:            "Слишком много аргументов: можно не больше %d, передано %d",
+		msgUnknownFlag:                 "Неизвестная опция --%s",
+		msgInvalidFlagBoolean:          "Опция --%s может быть только true или false",
+		msgFlagRequiresValue:           "Опции --%s нужно значение: --%s=%s",
+		msgRateLimited:                 "Вы отправляете команды слишком часто. Подождите %s и попробуйте снова",
+		msgUnknownCommand:              "Неизвестная команда %q. Список команд: `%shelp`",
+		msgAvailableCommands:           "Доступные команды:",
+		msgHelpForDetails:              "Подробнее о команде: `%shelp <команда>`.",
+		msgTooManyActivePolls:          "У вас слишком много активных голосований. Закройте какие-нибудь из них, чтобы начать новое",
+		msgStartFailed:                 "Не удалось создать голосование. Попробуйте снова",
+		msgPollCreated:                 "Голосование создано!\nID: %s",
+		msgEmptyQuestion:               "Вопрос не может быть пустым",
+		msgQuestionTooLong:             "Вопрос слишком длинный, максимум %d символов",
+		msgTooFewOptions:               "В голосовании должно быть хотя бы %d вариант(ов) ответа",
+		msgTooManyOptions:              "Слишком много вариантов ответа, максимум %d",
+		msgEmptyOption:                 "Вариант %d пустой",
+		msgOptionTooLong:               "Вариант %d слишком длинный, максимум %d символов",
+		msgDuplicateOption:             "Вариант %d повторяет другой вариант",
+		msgInvalidPoll:                 "Голосование некорректно. Проверьте вопрос и варианты ответа и попробуйте снова",
+		msgVoteNotInteger:              "Голос должен быть целым числом: номером варианта ответа",
+		msgAlreadyVoted:                "Вы уже проголосовали в этом голосовании",
+		msgVotePollNotFound:            "Голосования с таким ID нет. Возможно, его удалили?",
+		msgVotePollClosed:              "Голосование закрыто, голосовать нельзя",
+		msgNoSuchOption:                "Такого варианта ответа нет. Попробуйте снова",
+		msgVoteFailed:                  "Не удалось проголосовать. Попробуйте снова",
+		msgVoteRegistered:              "Голос учтён",
+		msgPollNotFound:                "Голосования с таким ID нет. Попробуйте снова",
+		msgResultsFailed:               "Не удалось получить результаты голосования. Попробуйте снова",
+		msgResultsOption:               "\n%d. %s\nГолосов: %d",
+		msgClosePollNotFound:           "Не удалось закрыть голосование: голосования с таким ID нет. Попробуйте снова",
+		msgCloseNotAuthor:              "Вы не можете закрыть это голосование, это может сделать только автор",
+		msgCloseFailed:                 "Не удалось закрыть голосование. Попробуйте снова",
+		msgPollClosed:                  "Голосование закрыто",
+		msgClosedSummary:               "Ваше голосование `%s` закрыто. Результаты:\n%s",
+		msgFlagDMSummary:               "прислать мне результаты в личные сообщения, когда голосование будет закрыто",
+		msgFlagRemindEvery:             "напоминать участникам канала, которые не проголосовали, каждые `duration`, например `24h`",
+		msgRemindIntervalInvalid:       "Интервал напоминаний должен быть длительностью не меньше %s, например `24h`",
+		msgCmdPollRemind:               "напоминает участникам канала, которые еще не проголосовали, только для автора",
+		msgCmdPollDnd:                  "отключает для вас напоминания о голосованиях или включает их обратно",
+		msgArgDndMode:                  "`on` - не получать напоминания (по умолчанию), `off` - снова получать их",
+		msgRemindNotAuthor:             "Отправлять напоминания может только создатель голосования",
+		msgRemindPollClosed:            "Голосование закрыто, напоминать не о чем",
+		msgRemindFailed:                "Не удалось отправить напоминания",
+		msgRemindSent:                  "Напоминание отправлено не проголосовавшим участникам: %d",
+		msgRemindNobody:                "Все уже проголосовали",
+		msgReminderMention:             "%s, проголосуйте, пожалуйста, в голосовании `%s`: %s\nЧтобы не получать напоминания, используйте `%spoll_dnd`",
+		msgReminderDirect:              "Проголосуйте, пожалуйста, в голосовании `%s`: %s\nЧтобы не получать напоминания, используйте `%spoll_dnd`",
+		msgDndInvalid:                  "Ожидалось `on` или `off`, получено `%s`",
+		msgDndFailed:                   "Не удалось изменить настройки напоминаний",
+		msgDndOn:                       "Вы не будете получать напоминания о голосованиях",
+		msgDndOff:                      "Вы снова будете получать напоминания о голосованиях",
+		msgFlagQuorum:                  "необходимое участие: количество проголосовавших, например `5`, или процент участников канала, например `60%`",
+		msgFlagCloseIn:                 "автоматически закрыть голосование через `duration`, например `72h`",
+		msgFlagForce:                   "закрыть голосование, даже если кворум не набран",
+		msgInvalidQuorum:               "Кворум должен быть положительным количеством проголосовавших, например `5`, или процентом от 1 до 100, например `60%`",
+		msgCloseInInvalid:              "Время до закрытия должно быть длительностью не меньше %s, например `72h`",
+		msgCloseNoQuorum:               "Кворум не набран: проголосовали %d из %d необходимых. Чтобы все равно закрыть голосование, используйте `%spoll_close --force %s`",
+		msgResultsNoQuorum:             "**Кворум не набран**, результаты недействительны\n",
+		msgResultsQuorum:               "\n\nУчастие: %d из %d необходимых",
+		msgResultsQuorumReached:        "\n\nУчастие: %d из %d необходимых, кворум набран",
+		msgPollExpired:                 "Голосование `%s` закрыто автоматически. Результаты:\n%s",
+		msgFlagResults:                 "кто видит результаты: все в любое время (по умолчанию), проголосовавшие, все после закрытия или только автор",
+		msgInvalidResultsVisibility:    "Видимость результатов должна быть одной из `always`, `voted`, `closed` или `author`",
+		msgResultsAfterVote:            "Результаты будут видны после того, как вы проголосуете. Уже проголосовали: %d",
+		msgResultsAfterClose:           "Результаты будут видны после закрытия голосования. Уже проголосовали: %d",
+		msgResultsAuthorOnly:           "Результаты этого голосования видит только его автор. Уже проголосовали: %d",
+		msgPollExpiredHidden:           "Голосование `%s` закрыто автоматически. Проголосовали: %d",
+		msgPollClosedResults:           "Голосование `%s` закрыто автором. Результаты:\n%s",
+		msgPollClosedHidden:            "Голосование `%s` закрыто автором. Проголосовали: %d",
+		msgCmdPollReopen:               "снова открывает закрытое голосование с сохранением голосов, для автора или администратора",
+		msgCmdPollExtend:               "переносит срок закрытия голосования, для автора или администратора",
+		msgArgExtendDuration:           "на сколько продлить, например `24h`",
+		msgPollWasDeleted:              "Голосование удалено, его больше нельзя изменить",
+		msgReopenNotAllowed:            "Открыть голосование снова может только автор или администратор",
+		msgReopenPollActive:            "Голосование не закрыто",
+		msgReopenTooManyActive:         "У автора голосования слишком много активных голосований. Закройте какие-нибудь из них, чтобы открыть это снова",
+		msgReopenFailed:                "Не удалось открыть голосование. Попробуйте еще раз",
+		msgPollReopened:                "Голосование `%s` снова открыто",
+		msgExtendInvalid:               "Продление должно быть длительностью не меньше %s, например `24h`",
+		msgExtendNotAllowed:            "Продлить голосование может только автор или администратор",
+		msgExtendPollClosed:            "Голосование закрыто. Чтобы открыть его снова, используйте `%spoll_reopen %s`",
+		msgExtendNoDeadline:            "У голосования нет срока закрытия. Используйте `--close-in` при создании голосования, чтобы закрыть его автоматически",
+		msgExtendFailed:                "Не удалось продлить голосование. Попробуйте еще раз",
+		msgPollExtended:                "Голосование `%s` будет закрыто %s",
+		msgCmdPollOptionAdd:            "добавляет вариант ответа в голосование, для автора или администратора",
+		msgCmdPollOptionEdit:           "меняет текст варианта ответа с сохранением голосов, для автора или администратора",
+		msgCmdPollOptionRemove:         "удаляет вариант ответа из голосования, для автора или администратора",
+		msgArgOptionNumber:             "номер варианта ответа",
+		msgArgOptionText:               "текст варианта ответа",
+		msgFlagMoveTo:                  "перенести голоса за удаляемый вариант на вариант с этим номером. Без флага голоса отменяются, и проголосовавшие могут проголосовать снова",
+		msgOptionNotInteger:            "Вариант ответа должен быть целым числом: номером варианта",
+		msgOptionNotFound:              "В голосовании нет варианта ответа с таким номером",
+		msgOptionsNotAllowed:           "Менять варианты ответа может только автор голосования или администратор",
+		msgOptionsPollClosed:           "Голосование закрыто, варианты ответа нельзя добавлять и удалять",
+		msgOptionsFailed:               "Не удалось изменить варианты ответа. Попробуйте еще раз",
+		msgOptionAdded:                 "Вариант ответа добавлен:\n%d. %s",
+		msgOptionEdited:                "Вариант ответа %d изменен, голоса за него сохранены",
+		msgOptionRemoved:               "Вариант ответа %d удален. Отменено голосов: %d, эти пользователи могут проголосовать снова",
+		msgOptionRemovedMoved:          "Вариант ответа %d удален, голоса за него перенесены на вариант %d: %d",
+		msgFlagSuggestions:             "разрешить участникам предлагать варианты ответа: добавлять сразу (`open`) или после вашего одобрения (`approval`)",
+		msgInvalidSuggestionMode:       "Режим предложений должен быть `off`, `open` или `approval`",
+		msgCmdPollSuggest:              "предлагает новый вариант ответа, если автор голосования разрешил предложения",
+		msgCmdPollSuggestions:          "выводит предложения, ожидающие одобрения, для автора или администратора",
+		msgCmdPollApprove:              "добавляет предложенный вариант ответа в голосование, для автора или администратора",
+		msgCmdPollReject:               "отклоняет предложенный вариант ответа, для автора или администратора",
+		msgArgSuggestionID:             "ID предложения",
+		msgSuggestionPending:           "Ваше предложение отправлено автору голосования на одобрение",
+		msgSuggestionReceived:          "@%s предлагает вариант ответа «%s» для вашего голосования `%s`: %s\nЧтобы добавить его, используйте `%spoll_approve %s`, чтобы отклонить - `%spoll_reject %s`",
+		msgSuggestionsHeader:           "Предложения, ожидающие одобрения. Используйте `%spoll_approve <suggestionID>` или `%spoll_reject <suggestionID>`:",
+		msgSuggestionItem:              "`%s` %s",
+		msgNoSuggestions:               "Предложений, ожидающих одобрения, нет",
+		msgSuggestionRejected:          "Предложение отклонено",
+		msgSuggestionsNotAllowed:       "Рассматривать предложения может только автор голосования или администратор",
+		msgSuggestionsDisabled:         "Автор голосования не разрешил предлагать варианты ответа",
+		msgTooManySuggestions:          "Вы предложили слишком много вариантов ответа в этом голосовании",
+		msgSuggestionExists:            "Такой вариант ответа уже есть в голосовании, ожидает одобрения или был отклонен",
+		msgSuggestionNotFound:          "Предложения с таким ID нет. Попробуйте еще раз",
+		msgSuggestionReviewed:          "Это предложение уже одобрено или отклонено",
+		msgSuggestionsFailed:           "Не удалось обработать предложение. Попробуйте еще раз",
+		msgFlagRating:                  "голосование с оценками: участники оценивают каждый вариант в диапазоне, например `1-5`, или `nps` для 0-10. Без вариантов ответа оценивается сам вопрос",
+		msgInvalidScale:                "Шкала оценок должна быть диапазоном, например `1-5`, не больше 11 оценок, или `nps`",
+		msgRatingHint:                  "\nОцените каждый вариант от %d до %d, по порядку: `%spoll_vote %s <оценка>...`",
+		msgInvalidScores:               "Поставьте оценку от %[2]d до %[3]d каждому из вариантов (%[1]d), по порядку",
+		msgRatingSummary:               "Голосов: %d, среднее: %.2f, медиана: %.1f",
+		msgRatingNPS:                   "Промоутеры: %d, нейтральные: %d, критики: %d, NPS: %+d",
+		msgCannotMoveScores:            "Оценки голосования с оценками нельзя перенести на другой вариант, удалите вариант без `--move-to`",
+		msgEmptyTitle:                  "Название опроса не может быть пустым",
+		msgTitleTooLong:                "Название слишком длинное, максимум %d символов",
+		msgTooFewQuestions:             "В опросе должен быть хотя бы %d вопрос",
+		msgTooManyQuestions:            "Слишком много вопросов, в опросе может быть не больше %d",
+		msgQuestionOptionsNotAllowed:   "Варианты ответа могут быть только у вопросов `single` и `multi`",
+		msgCmdSurveyStart:              "создает опрос из нескольких вопросов и выводит его ID",
+		msgCmdSurveyTake:               "начинает опрос или продолжает его в личных сообщениях",
+		msgCmdSurveyResults:            "выводит ответы на каждый вопрос опроса",
+		msgCmdSurveyClose:              "прекращает прием ответов на опрос, для автора или администратора",
+		msgArgSurveyTitle:              "название опроса",
+		msgArgSurveyID:                 "ID опроса",
+		msgSurveyOptionWithoutQuestion: "Каждая строка опроса должна быть вопросом вида `single: текст` или вариантом ответа вопроса `single` или `multi`. См. `%shelp survey_start`",
+		msgSurveyCreated:               "Опрос создан!\nID: %s\n**%s**, вопросов: %d\nЧтобы пройти его, используйте `%ssurvey_take %s`, вопросы придут в личные сообщения",
+		msgSurveyQuestionsSent:         "Вопросы отправлены вам в личные сообщения",
+		msgSurveyQuestion:              "**%s** - вопрос %d из %d:\n%s",
+		msgSurveyHintSingle:            "_Ответьте номером варианта_",
+		msgSurveyHintMulti:             "_Ответьте номерами вариантов через пробел или запятую_",
+		msgSurveyHintRating:            "_Ответьте оценкой от %d до %d_",
+		msgSurveyHintText:              "_Ответьте сообщением_",
+		msgInvalidSurveyAnswer:         "Ответ не подходит к вопросу. %s",
+		msgSurveyCompleted:             "Спасибо! Вы ответили на все вопросы опроса **%s**",
+		msgSurveyResults:               "**%s**\nУчастников: %d, прошли полностью: %d",
+		msgSurveyQuestionAnswers:       "Ответов: %d",
+		msgSurveyOptionVotes:           "%d. %s - %d",
+		msgSurveyClosed:                "Опрос закрыт",
+		msgSurveyNotFound:              "Опроса с таким ID нет. Попробуйте еще раз",
+		msgSurveyIsClosed:              "Опрос закрыт",
+		msgSurveyAlreadyCompleted:      "Вы уже ответили на все вопросы этого опроса",
+		msgSurveyNotAllowed:            "Это может сделать только автор опроса или администратор",
+		msgSurveyInvalidQuestion:       "Вопрос %d: %s",
+		msgAnswerTooLong:               "Ответ слишком длинный, максимум %d символов",
+		msgSurveyHintAnonymous:         "_Ответьте сообщением, ответ анонимный: автор не увидит, кто ответил_",
+		msgCmdSurveyAnswers:            "выводит ответы на текстовый вопрос постранично, для автора или администратора",
+		msgCmdSurveyExport:             "присылает ответы на текстовый вопрос файлом CSV в личные сообщения, для автора или администратора",
+		msgCmdSurveyPublish:            "публикует выбранные ответы на текстовый вопрос в канале опроса и показывает их в результатах",
+		msgArgQuestionNumber:           "номер вопроса, начиная с 1",
+		msgArgAnswerNumber:             "номер ответа в `survey_answers`",
+		msgFlagPage:                    "номер страницы, начиная с 1",
+		msgInvalidPage:                 "Номер страницы должен быть положительным числом",
+		msgPageOutOfRange:              "Всего страниц с ответами: %d",
+		msgNoTextAnswers:               "На этот вопрос еще никто не ответил",
+		msgTextAnswersHeader:           "Ответы на вопрос %s опроса **%s**, страница %d из %d, всего %d. Чтобы опубликовать некоторые из них, используйте `%ssurvey_publish %s %s <ответ>...`:",
+		msgTextAnswerAuthor:            " - @%s",
+		msgTextAnswerPublished:         " _(опубликован)_",
+		msgTextAnswersNextPage:         "Следующая страница: `%ssurvey_answers --page=%d %s %s`",
+		msgTextAnswersExport:           "Ответы на вопрос %s опроса **%s**: %d",
+		msgTextAnswersExportSent:       "Ответы отправлены вам в личные сообщения",
+		msgTextAnswerNotFound:          "Ответа с номером %s нет. Номера ответов выводит `survey_answers`",
+		msgTextAnswersPublished:        "Ответы на вопрос «%s» опроса **%s**:",
+		msgTextAnswersPublishedCount:   "Опубликовано ответов: %d",
+		msgTextAnswersChanged:          "Ответы изменились, выведите их заново и попробуйте еще раз",
+		msgSurveyQuestionNotFound:      "В опросе нет вопроса с таким номером",
+		msgNotTextQuestion:             "Это не текстовый вопрос, ответы на него выводит `survey_results`",
+		msgSurveyFailed:                "Не удалось обработать опрос. Попробуйте еще раз",
+		msgFlagWeighted:                "голоса учитываются с весами из настроек бота, например у участников некоторых групп по 2 голоса",
+		msgFlagWeights:                 "веса участников только этого голосования, например `alice=2,bob=3`, голоса остальных весят 1. Включает `--weighted`",
+		msgInvalidWeights:              "Веса должны быть в виде `username=вес` через запятую, вес от 1 до %d",
+		msgWeightUserNotFound:          "Пользователя с именем %s нет",
+		msgPollWeighted:                "\n_Голоса в этом голосовании учитываются с весами_",
+		msgResultsWeightedOption:       "\n%d. %s\nГолосов: %d, вес: %d",
+		msgRatingWeightedSummary:       "Голосов: %d, вес: %d, взвешенное среднее: %.2f, взвешенная медиана: %.1f",
+		msgDeletePollNotFound:          "Не удалось удалить голосование: голосования с таким ID нет. Попробуйте снова",
+		msgDeleteNotAuthor:             "Вы не можете удалить это голосование, это может сделать только автор",
+		msgDeleteFailed:                "Не удалось удалить голосование. Попробуйте снова",
+		msgPollDeleted:                 "Голосование удалено",
+		msgCmdHelp:                     "информация о командах",
+		msgArgHelpCommand:              "команда, по которой нужна подробная справка",
+		msgCmdPollStart:                "создает голосование и выводит его ID",
+		msgArgQuestion:                 "вопрос голосования",
+		msgArgOption:                   "вариант ответа",
+		msgDetailsPollStart: "Вопрос и варианты ответа, содержащие пробелы, должны быть в кавычках.\n" +
+			"Варианты ответа можно написать по одному на строке, в том числе списком Markdown:\n" +
+			"```\n!poll_start Куда идем обедать?\n- Пицца\n- Суши\n```\n" +
+			"Если варианты ответа не указаны, используются варианты команды по умолчанию, если они настроены.",
+		msgDetailsSurveyStart: "Первая строка - название, каждая следующая - вопрос `тип: текст` " +
+			"или вариант ответа предыдущего вопроса. Типы: `single` и `multi` - выбор вариантов, `rating` со шкалой, " +
+			"например `rating 1-5` или `rating nps`, и `text`, или `text anonymous`, чтобы скрыть, кто ответил:\n" +
+			"```\n!survey_start Ретро спринта\nsingle: Как прошел спринт?\n- Хорошо\n- Плохо\n" +
+			"rating 1-5: Командный дух\ntext: Что стоит изменить?\n```\n" +
+			"Вопросы задаются по одному в личных сообщениях, ответы сохраняются после каждого вопроса.",
+		msgCmdPollVote:    "регистрирует ваш голос",
+		msgArgPollID:      "ID голосования",
+		msgArgVote:        "номер варианта ответа, или оценки всех вариантов по порядку для голосований с оценками",
+		msgCmdPollResults: "выводит результаты голосования",
+		msgCmdPollClose:   "автор может закрыть голосование",
+		msgCmdPollDelete:  "автор может удалить голосование",
+	}
+}
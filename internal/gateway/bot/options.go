@@ -0,0 +1,104 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/Xausdorf/mattermost-poll/internal/domain"
+	"github.com/Xausdorf/mattermost-poll/internal/usecase"
+	"github.com/mattermost/mattermost-server/v6/model"
+)
+
+func (b *PollingBot) handleOptionAdd(ctx context.Context, post *model.Post, cmd *Command) outcome {
+	// !poll_option_add [pollID] [text]
+	pollID := cmd.Args[0]
+	sender := b.sender(post)
+
+	option, err := b.pollService.AddOption(ctx, pollID, sender, cmd.Args[1])
+	if err != nil {
+		return b.optionsError(ctx, post, pollID, err)
+	}
+	b.Respond(ctx, post, ResponseConfirmation, b.tr(ctx, msgOptionAdded, option.ID, option.Text))
+	return outcomeOK
+}
+
+func (b *PollingBot) handleOptionEdit(ctx context.Context, post *model.Post, cmd *Command) outcome {
+	// !poll_option_edit [pollID] [option] [text]
+	pollID := cmd.Args[0]
+	optionID, err := strconv.Atoi(cmd.Args[1])
+	if err != nil {
+		b.Respond(ctx, post, ResponseError, b.tr(ctx, msgOptionNotInteger))
+		return outcomeInvalid
+	}
+	sender := b.sender(post)
+
+	if err = b.pollService.EditOption(ctx, pollID, sender, optionID, cmd.Args[2]); err != nil {
+		return b.optionsError(ctx, post, pollID, err)
+	}
+	b.Respond(ctx, post, ResponseConfirmation, b.tr(ctx, msgOptionEdited, optionID))
+	return outcomeOK
+}
+
+func (b *PollingBot) handleOptionRemove(ctx context.Context, post *model.Post, cmd *Command) outcome {
+	// !poll_option_remove [--move-to=option] [pollID] [option]
+	pollID := cmd.Args[0]
+	optionID, err := strconv.Atoi(cmd.Args[1])
+	if err != nil {
+		b.Respond(ctx, post, ResponseError, b.tr(ctx, msgOptionNotInteger))
+		return outcomeInvalid
+	}
+	var opts usecase.RemoveOptionOptions
+	if value, ok := cmd.Flag("move-to"); ok {
+		moveTo, err := strconv.Atoi(value)
+		if err != nil {
+			b.Respond(ctx, post, ResponseError, b.tr(ctx, msgOptionNotInteger))
+			return outcomeInvalid
+		}
+		opts.MoveTo = &moveTo
+	}
+	sender := b.sender(post)
+
+	votes, err := b.pollService.RemoveOption(ctx, pollID, sender, optionID, opts)
+	if err != nil {
+		return b.optionsError(ctx, post, pollID, err)
+	}
+	if opts.MoveTo != nil {
+		b.Respond(ctx, post, ResponseConfirmation, b.tr(ctx, msgOptionRemovedMoved, optionID, *opts.MoveTo, votes))
+	} else {
+		b.Respond(ctx, post, ResponseConfirmation, b.tr(ctx, msgOptionRemoved, optionID, votes))
+	}
+	return outcomeOK
+}
+
+// optionsError responds to a failed change of poll's options.
+func (b *PollingBot) optionsError(ctx context.Context, post *model.Post, pollID string, err error) outcome {
+	if errors.Is(err, usecase.ErrPollNotFound) {
+		b.Respond(ctx, post, ResponseError, b.tr(ctx, msgPollNotFound))
+		return outcomeRejected
+	}
+	if errors.Is(err, usecase.ErrUserIsNotPollAuthor) {
+		b.Respond(ctx, post, ResponseError, b.tr(ctx, msgOptionsNotAllowed))
+		return outcomeRejected
+	}
+	if errors.Is(err, usecase.ErrPollIsNotActive) {
+		b.Respond(ctx, post, ResponseError, b.tr(ctx, msgOptionsPollClosed))
+		return outcomeRejected
+	}
+	if errors.Is(err, usecase.ErrNoSuchOption) {
+		b.Respond(ctx, post, ResponseError, b.tr(ctx, msgOptionNotFound))
+		return outcomeRejected
+	}
+	if errors.Is(err, domain.ErrCannotMoveScores) {
+		b.Respond(ctx, post, ResponseError, b.tr(ctx, msgCannotMoveScores))
+		return outcomeRejected
+	}
+	var validationErr *domain.ValidationError
+	if errors.As(err, &validationErr) {
+		b.Respond(ctx, post, ResponseError, b.validationMessage(ctx, validationErr))
+		return outcomeRejected
+	}
+	b.logger.ErrorContext(ctx, "Failed to change options of poll", "poll_id", pollID, "error", err)
+	b.Respond(ctx, post, ResponseError, b.tr(ctx, msgOptionsFailed))
+	return outcomeFailed
+}
@@ -0,0 +1,318 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	defaultCommandPrefix = "!"
+	flagPrefix           = "--"
+	// flagsTerminator - all tokens after it are positional arguments, even if they look like flags.
+	flagsTerminator = "--"
+)
+
+var (
+	ErrNotCommand        = errors.New("message is not a command")
+	ErrUnterminatedQuote = errors.New("unterminated quote")
+	ErrEmptyFlagName     = errors.New("empty flag name")
+)
+
+// SyntaxError - error of command tokenizing, Pos is a position in runes where the problem starts.
+type SyntaxError struct {
+	Err error
+	Pos int
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%v at position %d", e.Err, e.Pos)
+}
+
+func (e *SyntaxError) Unwrap() error {
+	return e.Err
+}
+
+// Command - parsed bot command: name without prefix, positional arguments and --flags.
+// Flag given without value (--name) has empty value.
+type Command struct {
+	// Prefix - prefix the command was given with, e.g. "!".
+	Prefix string
+	Name   string
+	Args   []string
+	Flags  map[string]string
+	// Body - raw lines following the first line, filled only for multi-line commands.
+	Body []string
+
+	// quoted - whether the argument with the same index was quoted.
+	quoted []bool
+}
+
+// ArgsQuoted returns true if every positional argument was quoted.
+func (c *Command) ArgsQuoted() bool {
+	for _, q := range c.quoted {
+		if !q {
+			return false
+		}
+	}
+	return true
+}
+
+// Flag returns value of --name=value flag.
+func (c *Command) Flag(name string) (string, bool) {
+	value, ok := c.Flags[name]
+	return value, ok
+}
+
+// BoolFlag returns value of boolean flag, given as --name or --name=true|false.
+func (c *Command) BoolFlag(name string) bool {
+	value, ok := c.Flags[name]
+	if !ok {
+		return false
+	}
+	if value == "" {
+		return true
+	}
+	b, err := strconv.ParseBool(value)
+	return err == nil && b
+}
+
+type token struct {
+	value string
+	// quoted - token starts with a quote, so it can't be a flag.
+	quoted bool
+}
+
+// quoteClosers returns quotes that can close the quote r, or empty string if r is not a quote.
+// Mobile clients replace straight quotes with typographic ones, often not in pairs, so closers are lenient.
+func quoteClosers(r rune) string {
+	switch r {
+	case '"', '“', '”', '„':
+		return "\"“”"
+	case '«':
+		return "»"
+	case '\'':
+		return "'"
+	case '‘', '‚':
+		return "’‘"
+	default:
+		return ""
+	}
+}
+
+// tokenize splits the message at whitespace (including line breaks), except whitespace inside quotes.
+// Quotes are recognized at the start of a token or right after '=' of a flag, so apostrophes
+// inside words stay as they are. Backslash escapes the next character.
+func tokenize(s string) ([]token, error) {
+	var (
+		tokens   []token
+		current  strings.Builder
+		inToken  bool
+		quoted   bool
+		closers  string
+		quotePos int
+	)
+	flush := func() {
+		if inToken {
+			tokens = append(tokens, token{value: current.String(), quoted: quoted})
+		}
+		current.Reset()
+		inToken = false
+		quoted = false
+	}
+
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\\' && i+1 < len(runes):
+			i++
+			current.WriteRune(runes[i])
+			inToken = true
+		case closers != "":
+			if strings.ContainsRune(closers, r) {
+				closers = ""
+				continue
+			}
+			current.WriteRune(r)
+		case unicode.IsSpace(r):
+			flush()
+		case quoteClosers(r) != "" && (!inToken || strings.HasSuffix(current.String(), "=")):
+			closers = quoteClosers(r)
+			quotePos = i
+			quoted = quoted || !inToken
+			inToken = true
+		default:
+			current.WriteRune(r)
+			inToken = true
+		}
+	}
+	if closers != "" {
+		return nil, &SyntaxError{Err: ErrUnterminatedQuote, Pos: quotePos}
+	}
+	flush()
+
+	return tokens, nil
+}
+
+// Tokenize splits the message into arguments, see tokenize for details.
+func Tokenize(s string) ([]string, error) {
+	tokens, err := tokenize(s)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]string, len(tokens))
+	for i, t := range tokens {
+		values[i] = t.value
+	}
+	return values, nil
+}
+
+// commandName returns name of command in the message without tokenizing the whole message,
+// so that syntax errors can be reported with the usage of the command.
+func commandName(msg string, prefix string) (string, bool) {
+	fields := strings.Fields(msg)
+	if len(fields) == 0 {
+		return "", false
+	}
+	return strings.CutPrefix(fields[0], prefix)
+}
+
+// invocation - command found in a message.
+type invocation struct {
+	name string
+	// message - the message without mention of the bot, starting with the prefix.
+	message string
+	prefix  string
+	// usagePrefix - how commands are called in usage and help: the prefix, or the mention in mention-only mode.
+	usagePrefix string
+}
+
+// findInvocation finds a command in the message in the form "!name ...", "@bot name ..." or "@bot !name ...".
+// In mention-only mode the mention is required.
+func findInvocation(msg string, prefix string, botName string, mentionOnly bool) (invocation, bool) {
+	inv := invocation{prefix: prefix, usagePrefix: prefix}
+	if mentionOnly {
+		inv.usagePrefix = "@" + botName + " "
+	}
+
+	rest, mentioned := cutMention(msg, botName)
+	switch {
+	case mentioned:
+		inv.message = prefix + strings.TrimPrefix(strings.TrimLeftFunc(rest, unicode.IsSpace), prefix)
+	case mentionOnly:
+		return inv, false
+	default:
+		inv.message = msg
+	}
+
+	name, ok := commandName(inv.message, prefix)
+	if !ok || name == "" {
+		return inv, false
+	}
+	inv.name = name
+	return inv, true
+}
+
+// cutMention removes "@botName", optionally followed by ':' or ',', from the start of the message.
+func cutMention(msg string, botName string) (string, bool) {
+	msg = strings.TrimLeftFunc(msg, unicode.IsSpace)
+	mention := "@" + botName
+	if len(msg) < len(mention) || !strings.EqualFold(msg[:len(mention)], mention) {
+		return msg, false
+	}
+	rest := msg[len(mention):]
+	rest = strings.TrimPrefix(strings.TrimPrefix(rest, ":"), ",")
+	if rest != "" && !unicode.IsSpace([]rune(rest)[0]) {
+		// mention of another user with a longer name, e.g. "@bot2"
+		return msg, false
+	}
+	return rest, true
+}
+
+// ParseCommand parses the message in the form `!name arg "quoted arg" --flag=value --bool-flag`,
+// where "!" is the prefix.
+func ParseCommand(msg string, prefix string) (*Command, error) {
+	tokens, err := tokenize(msg)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 || tokens[0].quoted || !strings.HasPrefix(tokens[0].value, prefix) {
+		return nil, ErrNotCommand
+	}
+
+	cmd := &Command{
+		Prefix: prefix,
+		Name:   strings.TrimPrefix(tokens[0].value, prefix),
+		Flags:  make(map[string]string),
+	}
+	flagsDone := false
+	for _, t := range tokens[1:] {
+		if flagsDone || t.quoted || !strings.HasPrefix(t.value, flagPrefix) {
+			cmd.Args = append(cmd.Args, t.value)
+			cmd.quoted = append(cmd.quoted, t.quoted)
+			continue
+		}
+		if t.value == flagsTerminator {
+			flagsDone = true
+			continue
+		}
+		name, value, _ := strings.Cut(strings.TrimPrefix(t.value, flagPrefix), "=")
+		if name == "" {
+			return nil, ErrEmptyFlagName
+		}
+		cmd.Flags[name] = value
+	}
+	return cmd, nil
+}
+
+// ParseMultilineCommand parses only the first line of the message as a command,
+// the following lines are stored in Command.Body as they are.
+func ParseMultilineCommand(msg string, prefix string) (*Command, error) {
+	firstLine, rest, _ := strings.Cut(strings.TrimLeftFunc(msg, unicode.IsSpace), "\n")
+	cmd, err := ParseCommand(firstLine, prefix)
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range strings.Split(rest, "\n") {
+		if strings.TrimSpace(line) != "" {
+			cmd.Body = append(cmd.Body, line)
+		}
+	}
+	return cmd, nil
+}
+
+// ParseListItem parses a line of Markdown list: bullet ("-", "*", "+", "1.", "1)") is removed.
+// If the rest of the line consists of quoted strings only, each of them is an item,
+// otherwise the whole line is a single item.
+func ParseListItem(line string) []string {
+	line = trimListBullet(strings.TrimSpace(line))
+
+	tokens, err := tokenize(line)
+	if err != nil || len(tokens) == 0 {
+		return []string{line}
+	}
+	items := make([]string, len(tokens))
+	for i, t := range tokens {
+		if !t.quoted {
+			return []string{line}
+		}
+		items[i] = t.value
+	}
+	return items
+}
+
+func trimListBullet(line string) string {
+	rest := strings.TrimLeft(line, "0123456789")
+	switch {
+	case rest != line && (strings.HasPrefix(rest, ". ") || strings.HasPrefix(rest, ") ")):
+		rest = rest[1:]
+	case strings.HasPrefix(line, "- "), strings.HasPrefix(line, "* "), strings.HasPrefix(line, "+ "):
+		rest = line[1:]
+	default:
+		return line
+	}
+	return strings.TrimSpace(rest)
+}
@@ -0,0 +1,105 @@
+package bot
+
+import (
+	"testing"
+)
+
+func TestFindInvocation(t *testing.T) {
+	tests := []struct {
+		name        string
+		msg         string
+		mentionOnly bool
+		wantOK      bool
+		wantName    string
+		wantMessage string
+	}{
+		{name: "prefix", msg: "!poll_list", wantOK: true, wantName: "poll_list", wantMessage: "!poll_list"},
+		{
+			name:        "mention with prefix",
+			msg:         "@pollbot !poll_vote p1 1",
+			wantOK:      true,
+			wantName:    "poll_vote",
+			wantMessage: "!poll_vote p1 1",
+		},
+		{
+			name:        "mention without prefix",
+			msg:         "@pollbot poll_vote p1 1",
+			wantOK:      true,
+			wantName:    "poll_vote",
+			wantMessage: "!poll_vote p1 1",
+		},
+		{
+			name:        "mention with colon and another case",
+			msg:         "  @PollBot: poll_list",
+			wantOK:      true,
+			wantName:    "poll_list",
+			wantMessage: "!poll_list",
+		},
+		{
+			name:        "mention with comma",
+			msg:         "@pollbot, !help",
+			wantOK:      true,
+			wantName:    "help",
+			wantMessage: "!help",
+		},
+		{
+			name:        "mention in mention-only mode",
+			msg:         "@pollbot poll_list",
+			mentionOnly: true,
+			wantOK:      true,
+			wantName:    "poll_list",
+			wantMessage: "!poll_list",
+		},
+		{name: "prefix in mention-only mode", msg: "!poll_list", mentionOnly: true},
+		{name: "mention of another user", msg: "@pollbot2 poll_list"},
+		{name: "mention only", msg: "@pollbot"},
+		{name: "mention in the middle", msg: "hi @pollbot poll_list"},
+		{name: "no prefix", msg: "poll_list"},
+		{name: "prefix only", msg: "! poll_list"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inv, ok := findInvocation(tt.msg, "!", "pollbot", tt.mentionOnly)
+			if ok != tt.wantOK {
+				t.Fatalf("findInvocation(%q) found = %v, want %v", tt.msg, ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if inv.name != tt.wantName || inv.message != tt.wantMessage {
+				t.Errorf("findInvocation(%q) = %q, %q, want %q, %q",
+					tt.msg, inv.name, inv.message, tt.wantName, tt.wantMessage)
+			}
+			wantUsagePrefix := "!"
+			if tt.mentionOnly {
+				wantUsagePrefix = "@pollbot "
+			}
+			if inv.usagePrefix != wantUsagePrefix {
+				t.Errorf("usage prefix = %q, want %q", inv.usagePrefix, wantUsagePrefix)
+			}
+		})
+	}
+}
+
+func TestCutMention(t *testing.T) {
+	tests := []struct {
+		msg      string
+		wantRest string
+		wantOK   bool
+	}{
+		{msg: "@pollbot poll_list", wantRest: " poll_list", wantOK: true},
+		{msg: "@pollbot: !poll_list", wantRest: " !poll_list", wantOK: true},
+		{msg: "@POLLBOT", wantRest: "", wantOK: true},
+		{msg: "@pollbot2 poll_list", wantRest: "@pollbot2 poll_list"},
+		{msg: "@poll", wantRest: "@poll"},
+		{msg: "!poll_list", wantRest: "!poll_list"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.msg, func(t *testing.T) {
+			rest, ok := cutMention(tt.msg, "pollbot")
+			if rest != tt.wantRest || ok != tt.wantOK {
+				t.Errorf("cutMention(%q) = %q, %v, want %q, %v", tt.msg, rest, ok, tt.wantRest, tt.wantOK)
+			}
+		})
+	}
+}
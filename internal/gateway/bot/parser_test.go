@@ -0,0 +1,299 @@
+package bot_test
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/Xausdorf/mattermost-poll/internal/gateway/bot"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want []string
+	}{
+		{name: "empty", in: "", want: []string{}},
+		{name: "spaces only", in: "  \t\n ", want: []string{}},
+		{name: "words", in: "!poll_vote abc 1", want: []string{"!poll_vote", "abc", "1"}},
+		{name: "repeated whitespace", in: " a \t b\n\nc ", want: []string{"a", "b", "c"}},
+		{name: "double quotes", in: `"Where to go?" "Pizza place"`, want: []string{"Where to go?", "Pizza place"}},
+		{name: "single quotes", in: `'a b' c`, want: []string{"a b", "c"}},
+		{name: "empty quotes", in: `"" x`, want: []string{"", "x"}},
+		{name: "typographic quotes", in: `“a b” «c d» ‘e f’`, want: []string{"a b", "c d", "e f"}},
+		{name: "mixed typographic pair", in: `”a b“`, want: []string{"a b"}},
+		{name: "straight quote closed by typographic", in: `"a b”`, want: []string{"a b"}},
+		{name: "apostrophe inside word", in: `don't stop`, want: []string{"don't", "stop"}},
+		{name: "quote inside word", in: `say"hi there"`, want: []string{`say"hi`, `there"`}},
+		{name: "quoted flag value", in: `--results="after close" x`, want: []string{"--results=after close", "x"}},
+		{name: "escaped quote", in: `\"a b\"`, want: []string{`"a`, `b"`}},
+		{name: "escaped space", in: `a\ b`, want: []string{"a b"}},
+		{name: "escape inside quotes", in: `"a \" b"`, want: []string{`a " b`}},
+		{name: "trailing backslash", in: `a\`, want: []string{`a\`}},
+		{name: "quoted text joined with word", in: `"a b"c`, want: []string{"a bc"}},
+		{name: "line breaks inside quotes", in: "\"a\nb\"", want: []string{"a\nb"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := bot.Tokenize(tt.in)
+			if err != nil {
+				t.Fatalf("Tokenize(%q) error: %v", tt.in, err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Tokenize(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokenizeUnterminatedQuote(t *testing.T) {
+	tests := []struct {
+		in  string
+		pos int
+	}{
+		{in: `"abc`, pos: 0},
+		{in: `a "b c`, pos: 2},
+		{in: `«a b”`, pos: 0},
+		{in: `ab 'c`, pos: 3},
+		{in: `яя "b`, pos: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			_, err := bot.Tokenize(tt.in)
+			var syntaxErr *bot.SyntaxError
+			if !errors.As(err, &syntaxErr) || !errors.Is(err, bot.ErrUnterminatedQuote) {
+				t.Fatalf("Tokenize(%q) error = %v, want unterminated quote", tt.in, err)
+			}
+			if syntaxErr.Pos != tt.pos {
+				t.Errorf("Tokenize(%q) position = %d, want %d", tt.in, syntaxErr.Pos, tt.pos)
+			}
+		})
+	}
+}
+
+func TestParseCommand(t *testing.T) {
+	tests := []struct {
+		name       string
+		in         string
+		prefix     string
+		wantName   string
+		wantArgs   []string
+		wantFlags  map[string]string
+		wantQuoted bool
+	}{
+		{
+			name:       "name only",
+			in:         "!help",
+			prefix:     "!",
+			wantName:   "help",
+			wantFlags:  map[string]string{},
+			wantQuoted: true,
+		},
+		{
+			name:      "arguments and flags",
+			in:        `!poll_start --dm-summary --quorum=50% "Lunch?" Pizza`,
+			prefix:    "!",
+			wantName:  "poll_start",
+			wantArgs:  []string{"Lunch?", "Pizza"},
+			wantFlags: map[string]string{"dm-summary": "", "quorum": "50%"},
+		},
+		{
+			name:       "quoted argument is not a flag",
+			in:         `!poll_start "--not-a-flag" "x"`,
+			prefix:     "!",
+			wantName:   "poll_start",
+			wantArgs:   []string{"--not-a-flag", "x"},
+			wantFlags:  map[string]string{},
+			wantQuoted: true,
+		},
+		{
+			name:      "terminator",
+			in:        `!poll_start --force -- --arg`,
+			prefix:    "!",
+			wantName:  "poll_start",
+			wantArgs:  []string{"--arg"},
+			wantFlags: map[string]string{"force": ""},
+		},
+		{
+			name:      "value with equal signs",
+			in:        `!poll_start --weights=alice=2,bob=3 q`,
+			prefix:    "!",
+			wantName:  "poll_start",
+			wantArgs:  []string{"q"},
+			wantFlags: map[string]string{"weights": "alice=2,bob=3"},
+		},
+		{
+			name:      "custom prefix",
+			in:        `poll/vote abc 1`,
+			prefix:    "poll/",
+			wantName:  "vote",
+			wantArgs:  []string{"abc", "1"},
+			wantFlags: map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, err := bot.ParseCommand(tt.in, tt.prefix)
+			if err != nil {
+				t.Fatalf("ParseCommand(%q) error: %v", tt.in, err)
+			}
+			if cmd.Name != tt.wantName || cmd.Prefix != tt.prefix {
+				t.Errorf("name = %q, prefix = %q, want %q, %q", cmd.Name, cmd.Prefix, tt.wantName, tt.prefix)
+			}
+			if !slices.Equal(cmd.Args, tt.wantArgs) {
+				t.Errorf("args = %q, want %q", cmd.Args, tt.wantArgs)
+			}
+			if !maps.Equal(cmd.Flags, tt.wantFlags) {
+				t.Errorf("flags = %v, want %v", cmd.Flags, tt.wantFlags)
+			}
+			if cmd.ArgsQuoted() != tt.wantQuoted {
+				t.Errorf("ArgsQuoted() = %v, want %v", cmd.ArgsQuoted(), tt.wantQuoted)
+			}
+		})
+	}
+}
+
+func TestParseCommandErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want error
+	}{
+		{name: "empty", in: "", want: bot.ErrNotCommand},
+		{name: "no prefix", in: "hello there", want: bot.ErrNotCommand},
+		{name: "quoted name", in: `"!help"`, want: bot.ErrNotCommand},
+		{name: "empty flag name", in: "!poll_start --=x q", want: bot.ErrEmptyFlagName},
+		{name: "unterminated quote", in: `!poll_start "q`, want: bot.ErrUnterminatedQuote},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := bot.ParseCommand(tt.in, "!"); !errors.Is(err, tt.want) {
+				t.Errorf("ParseCommand(%q) error = %v, want %v", tt.in, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommandBoolFlag(t *testing.T) {
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{in: "!c", want: false},
+		{in: "!c --force", want: true},
+		{in: "!c --force=true", want: true},
+		{in: "!c --force=1", want: true},
+		{in: "!c --force=false", want: false},
+		{in: "!c --force=maybe", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			cmd, err := bot.ParseCommand(tt.in, "!")
+			if err != nil {
+				t.Fatalf("ParseCommand(%q) error: %v", tt.in, err)
+			}
+			if got := cmd.BoolFlag("force"); got != tt.want {
+				t.Errorf("BoolFlag(force) = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMultilineCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       string
+		wantArgs []string
+		wantBody []string
+	}{
+		{
+			name:     "single line",
+			in:       `!poll_start "Lunch?" Pizza`,
+			wantArgs: []string{"Lunch?", "Pizza"},
+		},
+		{
+			name:     "question and list",
+			in:       "!poll_start Lunch?\n- Pizza\n- Sushi",
+			wantArgs: []string{"Lunch?"},
+			wantBody: []string{"- Pizza", "- Sushi"},
+		},
+		{
+			name:     "blank lines are skipped",
+			in:       "\n  !poll_start\n\nLunch?\n   \n- Pizza\n",
+			wantBody: []string{"Lunch?", "- Pizza"},
+		},
+		{
+			name:     "quotes are not closed across lines",
+			in:       "!poll_start Lunch?\n\"Pizza\n- Sushi\"",
+			wantArgs: []string{"Lunch?"},
+			wantBody: []string{`"Pizza`, `- Sushi"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, err := bot.ParseMultilineCommand(tt.in, "!")
+			if err != nil {
+				t.Fatalf("ParseMultilineCommand(%q) error: %v", tt.in, err)
+			}
+			if cmd.Name != "poll_start" {
+				t.Errorf("name = %q, want poll_start", cmd.Name)
+			}
+			if !slices.Equal(cmd.Args, tt.wantArgs) {
+				t.Errorf("args = %q, want %q", cmd.Args, tt.wantArgs)
+			}
+			if !slices.Equal(cmd.Body, tt.wantBody) {
+				t.Errorf("body = %q, want %q", cmd.Body, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestParseMultilineCommandErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want error
+	}{
+		{name: "not a command", in: "Lunch?\n!poll_start", want: bot.ErrNotCommand},
+		{name: "unterminated quote in first line", in: "!poll_start \"Lunch?\n- Pizza\"", want: bot.ErrUnterminatedQuote},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := bot.ParseMultilineCommand(tt.in, "!"); !errors.Is(err, tt.want) {
+				t.Errorf("ParseMultilineCommand(%q) error = %v, want %v", tt.in, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseListItem(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{in: "Pizza", want: []string{"Pizza"}},
+		{in: "  Pizza place  ", want: []string{"Pizza place"}},
+		{in: "- Pizza", want: []string{"Pizza"}},
+		{in: "* Pizza", want: []string{"Pizza"}},
+		{in: "+ Pizza", want: []string{"Pizza"}},
+		{in: "1. Pizza", want: []string{"Pizza"}},
+		{in: "12) Pizza", want: []string{"Pizza"}},
+		{in: "-Pizza", want: []string{"-Pizza"}},
+		{in: "1.5 kg", want: []string{"1.5 kg"}},
+		{in: "2024", want: []string{"2024"}},
+		{in: `- "Pizza" "Sushi"`, want: []string{"Pizza", "Sushi"}},
+		{in: `«Pizza place» “Sushi”`, want: []string{"Pizza place", "Sushi"}},
+		{in: `- "Pizza" or "Sushi"`, want: []string{`"Pizza" or "Sushi"`}},
+		{in: `- "Pizza`, want: []string{`"Pizza`}},
+		{in: "-", want: []string{"-"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := bot.ParseListItem(tt.in); !slices.Equal(got, tt.want) {
+				t.Errorf("ParseListItem(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
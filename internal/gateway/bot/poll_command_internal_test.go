@@ -0,0 +1,73 @@
+package bot
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPollFromCommand(t *testing.T) {
+	tests := []struct {
+		name         string
+		msg          string
+		wantQuestion string
+		wantOptions  []string
+	}{
+		{
+			name:         "single line",
+			msg:          `!poll_start "Lunch?" Pizza Sushi`,
+			wantQuestion: "Lunch?",
+			wantOptions:  []string{"Pizza", "Sushi"},
+		},
+		{
+			name:         "unquoted question in first line",
+			msg:          "!poll_start Where to have lunch?\n- Pizza\n- Sushi",
+			wantQuestion: "Where to have lunch?",
+			wantOptions:  []string{"Pizza", "Sushi"},
+		},
+		{
+			name:         "question in second line",
+			msg:          "!poll_start\nWhere to have lunch?\n1. Pizza\n2. Sushi",
+			wantQuestion: "Where to have lunch?",
+			wantOptions:  []string{"Pizza", "Sushi"},
+		},
+		{
+			name:         "quoted question and options in first line",
+			msg:          "!poll_start \"Lunch?\" \"Pizza\"\n- Sushi",
+			wantQuestion: "Lunch?",
+			wantOptions:  []string{"Pizza", "Sushi"},
+		},
+		{
+			name:         "several quoted options in one line",
+			msg:          "!poll_start Lunch?\n- \"Pizza\" \"Sushi\"\n- Tacos",
+			wantQuestion: "Lunch?",
+			wantOptions:  []string{"Pizza", "Sushi", "Tacos"},
+		},
+		{
+			name:         "quoted question with options in second line",
+			msg:          "!poll_start\n\"Lunch?\" \"Pizza\" \"Sushi\"",
+			wantQuestion: "Lunch?",
+			wantOptions:  []string{"Pizza", "Sushi"},
+		},
+		{
+			name:         "flags before question",
+			msg:          "!poll_start --anonymous Lunch?\n- Pizza",
+			wantQuestion: "Lunch?",
+			wantOptions:  []string{"Pizza"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, err := ParseMultilineCommand(tt.msg, "!")
+			if err != nil {
+				t.Fatalf("ParseMultilineCommand(%q) error: %v", tt.msg, err)
+			}
+			question, options := pollFromCommand(cmd)
+			if question != tt.wantQuestion {
+				t.Errorf("question = %q, want %q", question, tt.wantQuestion)
+			}
+			if !slices.Equal(options, tt.wantOptions) {
+				t.Errorf("options = %q, want %q", options, tt.wantOptions)
+			}
+		})
+	}
+}
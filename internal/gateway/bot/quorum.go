@@ -0,0 +1,34 @@
+package bot
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Xausdorf/mattermost-poll/internal/domain"
+)
+
+// ParseQuorum parses quorum given as a count of voters ("5") or a percentage of channel members ("60%").
+func ParseQuorum(s string) (domain.Quorum, error) {
+	value, percent := strings.CutSuffix(strings.TrimSpace(s), "%")
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		return domain.Quorum{}, fmt.Errorf("quorum %q must be a positive count or percentage", s)
+	}
+	if percent {
+		return domain.Quorum{Percent: n}, nil
+	}
+	return domain.Quorum{Count: n}, nil
+}
+
+// quorumMembers returns count of members of the poll's channel if its quorum depends on it, 0 otherwise.
+func (b *PollingBot) quorumMembers(poll *domain.Poll) (int, error) {
+	if poll.Quorum.Count > 0 || poll.Quorum.Percent == 0 {
+		return 0, nil
+	}
+	members, err := b.channelMembers(poll.ChannelID)
+	if err != nil {
+		return 0, err
+	}
+	return len(members), nil
+}
@@ -0,0 +1,38 @@
+package bot_test
+
+import (
+	"testing"
+
+	"github.com/Xausdorf/mattermost-poll/internal/domain"
+	"github.com/Xausdorf/mattermost-poll/internal/gateway/bot"
+)
+
+func TestParseQuorum(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    domain.Quorum
+		wantErr bool
+	}{
+		{in: "5", want: domain.Quorum{Count: 5}},
+		{in: "50%", want: domain.Quorum{Percent: 50}},
+		{in: " 30% ", want: domain.Quorum{Percent: 30}},
+		{in: "", wantErr: true},
+		{in: "%", wantErr: true},
+		{in: "0", wantErr: true},
+		{in: "-5", wantErr: true},
+		{in: "0%", wantErr: true},
+		{in: "half", wantErr: true},
+		{in: "5 %", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := bot.ParseQuorum(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseQuorum(%q) error = %v, want error %v", tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseQuorum(%q) = %+v, want %+v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
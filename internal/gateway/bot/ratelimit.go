@@ -0,0 +1,205 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultRateLimitKey = "default"
+	// rateLimiterSweepInterval - how often idle buckets are removed from memory.
+	rateLimiterSweepInterval = 10 * time.Minute
+)
+
+// RateLimit - token bucket parameters: at most Burst commands per Window.
+type RateLimit struct {
+	Burst  int
+	Window time.Duration
+}
+
+// RateLimits - rate limits of commands, keyed by command name without prefix ("poll_start").
+// Commands without their own limit use the limit stored under the "default" key.
+type RateLimits map[string]RateLimit
+
+func (l RateLimits) forCommand(command string) (RateLimit, bool) {
+	if limit, ok := l[command]; ok {
+		return limit, limit.Burst > 0
+	}
+	limit, ok := l[defaultRateLimitKey]
+	return limit, ok && limit.Burst > 0
+}
+
+// ParseRateLimits parses limits in the form "default=20/1m,poll_start=3/1m".
+func ParseRateLimits(s string) (RateLimits, error) {
+	limits := make(RateLimits)
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		command, value, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, fmt.Errorf("rate limit %q must be in the form command=burst/window", item)
+		}
+		burstStr, windowStr, ok := strings.Cut(value, "/")
+		if !ok {
+			return nil, fmt.Errorf("rate limit %q must be in the form command=burst/window", item)
+		}
+		burst, err := strconv.Atoi(burstStr)
+		if err != nil || burst < 0 {
+			return nil, fmt.Errorf("rate limit %q has invalid burst", item)
+		}
+		window, err := time.ParseDuration(windowStr)
+		if err != nil || window <= 0 {
+			return nil, fmt.Errorf("rate limit %q has invalid window", item)
+		}
+		limits[strings.TrimSpace(command)] = RateLimit{Burst: burst, Window: window}
+	}
+	return limits, nil
+}
+
+// ErrRateLimited - command was rejected by rate limiter.
+var ErrRateLimited = errors.New("rate limit exceeded")
+
+// RateLimitError - describes rejected command, Notify is true only for the first rejection in a window.
+type RateLimitError struct {
+	RetryAfter time.Duration
+	Notify     bool
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("%v, retry after %s", ErrRateLimited, e.RetryAfter)
+}
+
+func (e *RateLimitError) Unwrap() error {
+	return ErrRateLimited
+}
+
+type tokenBucket struct {
+	tokens     float64
+	updatedAt  time.Time
+	notifiedAt time.Time
+}
+
+// RateLimiter - token bucket rate limiter of bot commands, keyed by user and by channel.
+type RateLimiter struct {
+	userLimits    RateLimits
+	channelLimits RateLimits
+	now           func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	sweptAt   time.Time
+	maxWindow time.Duration
+}
+
+func NewRateLimiter(userLimits RateLimits, channelLimits RateLimits) *RateLimiter {
+	return &RateLimiter{
+		userLimits:    userLimits,
+		channelLimits: channelLimits,
+		now:           time.Now,
+		buckets:       make(map[string]*tokenBucket),
+		sweptAt:       time.Now(),
+		maxWindow:     maxWindow(userLimits, channelLimits),
+	}
+}
+
+// SetLimits replaces the limits, existing buckets are refilled according to the new limits on next use.
+func (l *RateLimiter) SetLimits(userLimits RateLimits, channelLimits RateLimits) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.userLimits = userLimits
+	l.channelLimits = channelLimits
+	l.maxWindow = maxWindow(userLimits, channelLimits)
+}
+
+func maxWindow(limits ...RateLimits) time.Duration {
+	var window time.Duration
+	for _, l := range limits {
+		for _, limit := range l {
+			window = max(window, limit.Window)
+		}
+	}
+	return window
+}
+
+// Allow takes a token from both user's and channel's buckets of the command.
+// Returns *RateLimitError if any of the buckets is empty.
+func (l *RateLimiter) Allow(command string, userID string, channelID string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	userLimit, userLimited := l.userLimits.forCommand(command)
+	channelLimit, channelLimited := l.channelLimits.forCommand(command)
+
+	var userBucket, channelBucket *tokenBucket
+	if userLimited {
+		userBucket = l.bucket("user:"+userID+":"+command, userLimit, now)
+		if userBucket.tokens < 1 {
+			return rejection(userBucket, userLimit, now)
+		}
+	}
+	if channelLimited {
+		channelBucket = l.bucket("channel:"+channelID+":"+command, channelLimit, now)
+		if channelBucket.tokens < 1 {
+			return rejection(channelBucket, channelLimit, now)
+		}
+	}
+
+	// tokens are taken only when both buckets allow the command
+	if userBucket != nil {
+		userBucket.tokens--
+	}
+	if channelBucket != nil {
+		channelBucket.tokens--
+	}
+	return nil
+}
+
+func (l *RateLimiter) bucket(key string, limit RateLimit, now time.Time) *tokenBucket {
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &tokenBucket{tokens: float64(limit.Burst), updatedAt: now}
+		l.buckets[key] = b
+		return b
+	}
+	rate := float64(limit.Burst) / limit.Window.Seconds()
+	b.tokens = min(float64(limit.Burst), b.tokens+now.Sub(b.updatedAt).Seconds()*rate)
+	b.updatedAt = now
+	return b
+}
+
+// sweep removes buckets which were not used long enough to be full again.
+func (l *RateLimiter) sweep(now time.Time) {
+	if now.Sub(l.sweptAt) < rateLimiterSweepInterval {
+		return
+	}
+	l.sweptAt = now
+	for key, b := range l.buckets {
+		if now.Sub(b.updatedAt) > l.maxWindow {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+func rejection(b *tokenBucket, limit RateLimit, now time.Time) error {
+	rate := float64(limit.Burst) / limit.Window.Seconds()
+	retryAfter := time.Duration((1 - b.tokens) / rate * float64(time.Second)).Round(time.Second)
+
+	notify := now.Sub(b.notifiedAt) >= limit.Window
+	if notify {
+		b.notifiedAt = now
+	}
+	return &RateLimitError{
+		RetryAfter: max(retryAfter, time.Second),
+		Notify:     notify,
+	}
+}
@@ -0,0 +1,178 @@
+package bot
+
+import (
+	"errors"
+	"maps"
+	"testing"
+	"time"
+)
+
+type rateLimitStep struct {
+	advance time.Duration
+	command string
+	user    string
+	channel string
+	allowed bool
+	retry   time.Duration
+	notify  bool
+}
+
+func TestRateLimiterAllow(t *testing.T) {
+	tests := []struct {
+		name    string
+		user    RateLimits
+		channel RateLimits
+		steps   []rateLimitStep
+	}{
+		{
+			name: "burst then refill",
+			user: RateLimits{defaultRateLimitKey: {Burst: 2, Window: time.Minute}},
+			steps: []rateLimitStep{
+				{command: "poll_vote", user: "u1", channel: "c1", allowed: true},
+				{command: "poll_vote", user: "u1", channel: "c1", allowed: true},
+				{command: "poll_vote", user: "u1", channel: "c1", retry: 30 * time.Second, notify: true},
+				{advance: 10 * time.Second, command: "poll_vote", user: "u1", channel: "c1", retry: 20 * time.Second},
+				{advance: 20 * time.Second, command: "poll_vote", user: "u1", channel: "c1", allowed: true},
+				{command: "poll_vote", user: "u1", channel: "c1", retry: 30 * time.Second},
+				{advance: 30 * time.Second, command: "poll_vote", user: "u2", channel: "c1", allowed: true},
+				{command: "poll_vote", user: "u1", channel: "c1", allowed: true},
+				{command: "poll_vote", user: "u1", channel: "c1", retry: 30 * time.Second, notify: true},
+			},
+		},
+		{
+			name: "refill is capped by burst",
+			user: RateLimits{defaultRateLimitKey: {Burst: 1, Window: time.Minute}},
+			steps: []rateLimitStep{
+				{command: "poll_vote", user: "u1", channel: "c1", allowed: true},
+				{advance: time.Hour, command: "poll_vote", user: "u1", channel: "c1", allowed: true},
+				{command: "poll_vote", user: "u1", channel: "c1", retry: time.Minute, notify: true},
+			},
+		},
+		{
+			name: "command limit overrides default",
+			user: RateLimits{
+				defaultRateLimitKey: {Burst: 1, Window: time.Minute},
+				"poll_start":        {Burst: 2, Window: time.Hour},
+				"poll_vote":         {Burst: 0, Window: time.Minute},
+			},
+			steps: []rateLimitStep{
+				{command: "poll_start", user: "u1", channel: "c1", allowed: true},
+				{command: "poll_start", user: "u1", channel: "c1", allowed: true},
+				{command: "poll_start", user: "u1", channel: "c1", retry: 30 * time.Minute, notify: true},
+				{command: "poll_close", user: "u1", channel: "c1", allowed: true},
+				{command: "poll_close", user: "u1", channel: "c1", retry: time.Minute, notify: true},
+				{command: "poll_vote", user: "u1", channel: "c1", allowed: true},
+				{command: "poll_vote", user: "u1", channel: "c1", allowed: true},
+			},
+		},
+		{
+			name: "no default limit",
+			user: RateLimits{"poll_start": {Burst: 1, Window: time.Minute}},
+			steps: []rateLimitStep{
+				{command: "poll_vote", user: "u1", channel: "c1", allowed: true},
+				{command: "poll_vote", user: "u1", channel: "c1", allowed: true},
+			},
+		},
+		{
+			name:    "channel limit is shared by users",
+			channel: RateLimits{defaultRateLimitKey: {Burst: 1, Window: time.Minute}},
+			steps: []rateLimitStep{
+				{command: "poll_vote", user: "u1", channel: "c1", allowed: true},
+				{command: "poll_vote", user: "u2", channel: "c1", retry: time.Minute, notify: true},
+				{command: "poll_vote", user: "u2", channel: "c2", allowed: true},
+			},
+		},
+		{
+			name:    "tokens are taken only when both buckets allow",
+			user:    RateLimits{defaultRateLimitKey: {Burst: 2, Window: time.Minute}},
+			channel: RateLimits{defaultRateLimitKey: {Burst: 1, Window: time.Minute}},
+			steps: []rateLimitStep{
+				{command: "poll_vote", user: "u1", channel: "c1", allowed: true},
+				{command: "poll_vote", user: "u1", channel: "c1", retry: time.Minute, notify: true},
+				{command: "poll_vote", user: "u1", channel: "c2", allowed: true},
+				{command: "poll_vote", user: "u1", channel: "c3", retry: 30 * time.Second, notify: true},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+			limiter := NewRateLimiter(tt.user, tt.channel)
+			limiter.now = func() time.Time { return now }
+			for i, step := range tt.steps {
+				now = now.Add(step.advance)
+				err := limiter.Allow(step.command, step.user, step.channel)
+				if step.allowed {
+					if err != nil {
+						t.Fatalf("step %d: unexpected error: %v", i, err)
+					}
+					continue
+				}
+				var limitErr *RateLimitError
+				if !errors.As(err, &limitErr) || !errors.Is(err, ErrRateLimited) {
+					t.Fatalf("step %d: error = %v, want rate limit error", i, err)
+				}
+				if limitErr.RetryAfter != step.retry || limitErr.Notify != step.notify {
+					t.Errorf("step %d: retry after %s, notify %v, want %s, %v",
+						i, limitErr.RetryAfter, limitErr.Notify, step.retry, step.notify)
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimiterSweep(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter := NewRateLimiter(RateLimits{defaultRateLimitKey: {Burst: 1, Window: time.Minute}}, nil)
+	limiter.sweptAt = now
+	limiter.now = func() time.Time { return now }
+
+	if err := limiter.Allow("poll_vote", "u1", "c1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now = now.Add(rateLimiterSweepInterval)
+	if err := limiter.Allow("poll_vote", "u2", "c1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := limiter.buckets["user:u1:poll_vote"]; ok {
+		t.Error("idle bucket was not removed")
+	}
+	if _, ok := limiter.buckets["user:u2:poll_vote"]; !ok {
+		t.Error("bucket in use was removed")
+	}
+}
+
+func TestParseRateLimits(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    RateLimits
+		wantErr bool
+	}{
+		{in: "", want: RateLimits{}},
+		{
+			in: "default=20/1m, poll_start=3/30s,",
+			want: RateLimits{
+				defaultRateLimitKey: {Burst: 20, Window: time.Minute},
+				"poll_start":        {Burst: 3, Window: 30 * time.Second},
+			},
+		},
+		{in: "poll_vote=0/1m", want: RateLimits{"poll_vote": {Burst: 0, Window: time.Minute}}},
+		{in: "default", wantErr: true},
+		{in: "default=20", wantErr: true},
+		{in: "default=x/1m", wantErr: true},
+		{in: "default=-1/1m", wantErr: true},
+		{in: "default=1/soon", wantErr: true},
+		{in: "default=1/0s", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseRateLimits(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRateLimits(%q) error = %v, want error %v", tt.in, err, tt.wantErr)
+			}
+			if !tt.wantErr && !maps.Equal(got, tt.want) {
+				t.Errorf("ParseRateLimits(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
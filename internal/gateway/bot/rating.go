@@ -0,0 +1,47 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Xausdorf/mattermost-poll/internal/domain"
+)
+
+// histogramWidth - length of the longest bar of histograms of scores.
+const histogramWidth = 10
+
+// ratingResults returns statistics and histograms of scores of every option of the rating poll.
+func (b *PollingBot) ratingResults(ctx context.Context, poll *domain.Poll) string {
+	var lines []string
+	for _, option := range poll.Options {
+		rating := option.Rating(poll.Scale)
+		// a poll without options rates its question, which is the only option then
+		if option.Text != poll.Question {
+			lines = append(lines, fmt.Sprintf("\n%d. %s", option.ID, option.Text))
+		}
+		ratingLines := b.ratingLines(ctx, poll.Scale, rating)
+		if poll.Weighted {
+			// histograms have total weight of voters, so their count is shown apart
+			ratingLines[0] = b.tr(ctx, msgRatingWeightedSummary, option.Votes, rating.Count, rating.Mean, rating.Median)
+		}
+		lines = append(lines, ratingLines...)
+	}
+	return "\n" + strings.Join(lines, "\n")
+}
+
+// ratingLines returns statistics, histogram and NPS of the scores.
+func (b *PollingBot) ratingLines(ctx context.Context, scale domain.Scale, rating domain.Rating) []string {
+	lines := []string{b.tr(ctx, msgRatingSummary, rating.Count, rating.Mean, rating.Median)}
+	most := slices.Max(rating.Histogram)
+	for i, count := range rating.Histogram {
+		bar := ""
+		if most > 0 {
+			// rounded up, so every given score is visible
+			bar = strings.Repeat("█", (count*histogramWidth+most-1)/most)
+		}
+		lines = append(lines, fmt.Sprintf("`%2d` %s %d", scale.Min+i, bar, count))
+	}
+	return append(lines, b.tr(ctx, msgRatingNPS, rating.Promoters, rating.Passives, rating.Detractors, rating.NPS()))
+}
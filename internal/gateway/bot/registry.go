@@ -0,0 +1,124 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+)
+
+type commandHandler func(ctx context.Context, post *model.Post, cmd *Command) outcome
+
+type registeredCommand struct {
+	spec    commandSpec
+	handler commandHandler
+}
+
+// commandRegistry - commands of the bot by name, aliases can be changed while the bot is running.
+type commandRegistry struct {
+	commands []registeredCommand
+
+	mu      sync.RWMutex
+	aliases map[string]string
+}
+
+func newCommandRegistry() *commandRegistry {
+	return &commandRegistry{
+		aliases: make(map[string]string),
+	}
+}
+
+func (r *commandRegistry) register(spec commandSpec, handler commandHandler) {
+	r.commands = append(r.commands, registeredCommand{spec: spec, handler: handler})
+}
+
+// setAliases replaces aliases, given as alias -> command name. Alias can't shadow a command.
+func (r *commandRegistry) setAliases(aliases map[string]string) error {
+	for alias, name := range aliases {
+		if _, ok := r.byName(alias); ok {
+			return fmt.Errorf("alias %q is a name of a command", alias)
+		}
+		if _, ok := r.byName(name); !ok {
+			return fmt.Errorf("alias %q refers to unknown command %q", alias, name)
+		}
+	}
+
+	r.mu.Lock()
+	r.aliases = aliases
+	r.mu.Unlock()
+	return nil
+}
+
+func (r *commandRegistry) byName(name string) (registeredCommand, bool) {
+	for _, command := range r.commands {
+		if command.spec.name == name {
+			return command, true
+		}
+	}
+	return registeredCommand{}, false
+}
+
+// lookup finds a command by its name or alias.
+func (r *commandRegistry) lookup(name string) (registeredCommand, bool) {
+	if command, ok := r.byName(name); ok {
+		return command, true
+	}
+	r.mu.RLock()
+	target, ok := r.aliases[name]
+	r.mu.RUnlock()
+	if !ok {
+		return registeredCommand{}, false
+	}
+	return r.byName(target)
+}
+
+func (r *commandRegistry) aliasesOf(name string) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var aliases []string
+	for alias, target := range r.aliases {
+		if target == name {
+			aliases = append(aliases, alias)
+		}
+	}
+	slices.Sort(aliases)
+	return aliases
+}
+
+// helpText returns the list of all commands with their usage and aliases.
+func (r *commandRegistry) helpText(t *Translator, locale string, prefix string) string {
+	var b strings.Builder
+	b.WriteString(t.T(locale, msgAvailableCommands))
+	for _, command := range r.commands {
+		fmt.Fprintf(&b, "\n* `%s`", command.spec.usage(prefix))
+		for _, alias := range r.aliasesOf(command.spec.name) {
+			fmt.Fprintf(&b, " `%s%s`", prefix, alias)
+		}
+		fmt.Fprintf(&b, " - %s", t.T(locale, command.spec.description))
+	}
+	b.WriteString("\n\n")
+	b.WriteString(t.T(locale, msgHelpForDetails, prefix))
+	return b.String()
+}
+
+// ParseAliases parses aliases in the form "vote=poll_vote,results=poll_results".
+func ParseAliases(s string) (map[string]string, error) {
+	aliases := make(map[string]string)
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		alias, name, ok := strings.Cut(item, "=")
+		alias, name = strings.TrimSpace(alias), strings.TrimSpace(name)
+		if !ok || alias == "" || name == "" {
+			return nil, fmt.Errorf("alias %q must be in the form alias=command", item)
+		}
+		aliases[alias] = name
+	}
+	return aliases, nil
+}
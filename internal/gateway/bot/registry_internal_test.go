@@ -0,0 +1,46 @@
+package bot
+
+import (
+	"testing"
+)
+
+func TestSetAliases(t *testing.T) {
+	tests := []struct {
+		name    string
+		aliases map[string]string
+		wantErr bool
+		// wantCommand - command found by alias "v", empty if none.
+		wantCommand string
+	}{
+		{name: "alias of a command", aliases: map[string]string{"v": "poll_vote"}, wantCommand: "poll_vote"},
+		{name: "unknown command", aliases: map[string]string{"v": "poll_unknown"}, wantErr: true},
+		{name: "alias conflicts with a command", aliases: map[string]string{"poll_list": "poll_vote"}, wantErr: true},
+		{name: "no aliases", aliases: map[string]string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newCommandRegistry()
+			r.register(commandSpec{name: "poll_vote"}, nil)
+			r.register(commandSpec{name: "poll_list"}, nil)
+			if err := r.setAliases(map[string]string{"v": "poll_list"}); err != nil {
+				t.Fatalf("setAliases() error: %v", err)
+			}
+
+			err := r.setAliases(tt.aliases)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("setAliases(%v) error = %v, want error %v", tt.aliases, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				// rejected aliases don't replace the previous ones
+				tt.wantCommand = "poll_list"
+			}
+			command, ok := r.lookup("v")
+			if ok != (tt.wantCommand != "") || command.spec.name != tt.wantCommand {
+				t.Errorf("lookup(%q) = %q, %v, want %q", "v", command.spec.name, ok, tt.wantCommand)
+			}
+			if command, ok = r.lookup("poll_list"); !ok || command.spec.name != "poll_list" {
+				t.Errorf("lookup(%q) = %q, %v, want the command", "poll_list", command.spec.name, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Xausdorf/mattermost-poll/internal/domain"
+	"github.com/Xausdorf/mattermost-poll/internal/usecase"
+	"github.com/mattermost/mattermost-server/v6/model"
+)
+
+const (
+	// minRemindInterval - the shortest schedule of reminders, so that members are not spammed.
+	minRemindInterval     = time.Hour
+	channelMembersPerPage = 200
+)
+
+// ReminderMode - how members who have not voted are reminded.
+type ReminderMode string
+
+const (
+	// ReminderMention - a single post in the poll's channel mentioning every member.
+	ReminderMention ReminderMode = "mention"
+	// ReminderDirect - a direct message to every member.
+	ReminderDirect ReminderMode = "direct"
+)
+
+// ParseReminderMode parses mode of reminders, empty string means ReminderMention.
+func ParseReminderMode(s string) (ReminderMode, error) {
+	switch mode := ReminderMode(strings.ToLower(strings.TrimSpace(s))); mode {
+	case "":
+		return ReminderMention, nil
+	case ReminderMention, ReminderDirect:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown reminder mode %q, must be %s or %s", s, ReminderMention, ReminderDirect)
+	}
+}
+
+func (b *PollingBot) setReminderMode(mode ReminderMode) {
+	b.settingsMu.Lock()
+	b.reminderMode = mode
+	b.settingsMu.Unlock()
+}
+
+func (b *PollingBot) currentReminderMode() ReminderMode {
+	b.settingsMu.RLock()
+	defer b.settingsMu.RUnlock()
+	return b.reminderMode
+}
+
+func (b *PollingBot) handleRemind(ctx context.Context, post *model.Post, cmd *Command) outcome {
+	// !poll_remind [pollID]
+	pollID := cmd.Args[0]
+	poll, err := b.pollService.GetPollByID(ctx, pollID)
+	if err != nil {
+		if errors.Is(err, usecase.ErrPollNotFound) {
+			b.Respond(ctx, post, ResponseError, b.tr(ctx, msgPollNotFound))
+			return outcomeRejected
+		}
+		b.logger.ErrorContext(ctx, "Failed to get poll", "poll_id", pollID, "error", err)
+		b.Respond(ctx, post, ResponseError, b.tr(ctx, msgRemindFailed))
+		return outcomeFailed
+	}
+
+	rootID := ""
+	if post.ChannelId == poll.ChannelID {
+		rootID = threadRootID(post)
+	}
+	reminded, err := b.remind(ctx, poll, post.UserId, rootID)
+	if err != nil {
+		if errors.Is(err, usecase.ErrUserIsNotPollAuthor) {
+			b.Respond(ctx, post, ResponseError, b.tr(ctx, msgRemindNotAuthor))
+			return outcomeRejected
+		}
+		if errors.Is(err, usecase.ErrPollIsNotActive) {
+			b.Respond(ctx, post, ResponseError, b.tr(ctx, msgRemindPollClosed))
+			return outcomeRejected
+		}
+		b.logger.ErrorContext(ctx, "Failed to remind about poll", "poll_id", pollID, "error", err)
+		b.Respond(ctx, post, ResponseError, b.tr(ctx, msgRemindFailed))
+		return outcomeFailed
+	}
+
+	if reminded == 0 {
+		b.Respond(ctx, post, ResponseConfirmation, b.tr(ctx, msgRemindNobody))
+	} else {
+		b.Respond(ctx, post, ResponseConfirmation, b.tr(ctx, msgRemindSent, reminded))
+	}
+	return outcomeOK
+}
+
+func (b *PollingBot) handleDoNotDisturb(ctx context.Context, post *model.Post, cmd *Command) outcome {
+	// !poll_dnd [on|off]
+	enabled := true
+	if len(cmd.Args) > 0 {
+		var ok bool
+		if enabled, ok = parseSwitch(cmd.Args[0]); !ok {
+			b.Respond(ctx, post, ResponseError, b.tr(ctx, msgDndInvalid, cmd.Args[0]))
+			return outcomeInvalid
+		}
+	}
+
+	if err := b.pollService.SetDoNotDisturb(ctx, post.UserId, enabled); err != nil {
+		b.logger.ErrorContext(ctx, "Failed to change do not disturb", "error", err)
+		b.Respond(ctx, post, ResponseError, b.tr(ctx, msgDndFailed))
+		return outcomeFailed
+	}
+	if enabled {
+		b.Respond(ctx, post, ResponseConfirmation, b.tr(ctx, msgDndOn))
+	} else {
+		b.Respond(ctx, post, ResponseConfirmation, b.tr(ctx, msgDndOff))
+	}
+	return outcomeOK
+}
+
+// parseSwitch parses "on"/"off" and boolean values.
+func parseSwitch(s string) (bool, bool) {
+	switch strings.ToLower(s) {
+	case "on":
+		return true, true
+	case "off":
+		return false, true
+	}
+	value, err := strconv.ParseBool(s)
+	return value, err == nil
+}
+
+// remind reminds members of the poll's channel who have not voted on behalf of senderID.
+// In mention mode the reminder is posted to the thread rootID, or to the channel if it is empty.
+func (b *PollingBot) remind(ctx context.Context, poll *domain.Poll, senderID string, rootID string) (int, error) {
+	var members []*model.User
+	users, err := b.pollService.RemindPoll(ctx, poll.ID, senderID, func(context.Context) ([]string, error) {
+		var err error
+		if members, err = b.channelMembers(poll.ChannelID); err != nil {
+			return nil, err
+		}
+		ids := make([]string, 0, len(members))
+		for _, member := range members {
+			ids = append(ids, member.Id)
+		}
+		return ids, nil
+	})
+	if err != nil || len(users) == 0 {
+		return 0, err
+	}
+
+	prefix := b.commandPrefix(b.teams.settingsFor(ctx, poll.TeamID))
+	if b.currentReminderMode() == ReminderDirect {
+		sent := 0
+		for _, userID := range users {
+			userCtx := withLocale(ctx, b.locales.userLocale(ctx, userID))
+			msg := b.tr(userCtx, msgReminderDirect, poll.ID, poll.Question, prefix)
+			if err = b.SendDirect(userCtx, userID, msg); err != nil {
+				b.logger.WarnContext(ctx, "Could not send reminder", "poll_id", poll.ID, "user_id", userID, "error", err)
+				continue
+			}
+			sent++
+		}
+		return sent, nil
+	}
+
+	names := make(map[string]string, len(members))
+	for _, member := range members {
+		names[member.Id] = member.Username
+	}
+	mentions := make([]string, len(users))
+	for i, userID := range users {
+		mentions[i] = "@" + names[userID]
+	}
+	msg := b.tr(ctx, msgReminderMention, strings.Join(mentions, " "), poll.ID, poll.Question, prefix)
+	if _, _, err = b.client.CreatePost(&model.Post{ChannelId: poll.ChannelID, RootId: rootID, Message: msg}); err != nil {
+		return 0, fmt.Errorf("could not post reminder: %w", err)
+	}
+	return len(users), nil
+}
+
+// channelMembers returns active users of the channel, except bots.
+func (b *PollingBot) channelMembers(channelID string) ([]*model.User, error) {
+	var members []*model.User
+	for page := 0; ; page++ {
+		users, _, err := b.client.GetUsersInChannel(channelID, page, channelMembersPerPage, "")
+		if err != nil {
+			return nil, fmt.Errorf("could not get members of channel %s: %w", channelID, err)
+		}
+		for _, user := range users {
+			if !user.IsBot && user.DeleteAt == 0 {
+				members = append(members, user)
+			}
+		}
+		if len(users) < channelMembersPerPage {
+			return members, nil
+		}
+	}
+}
+
+// commandPrefix returns what has to be written before a command name with the settings, like "!" or "@pollingbot ".
+func (b *PollingBot) commandPrefix(settings TeamSettings) string {
+	if settings.mentionOnly() {
+		return "@" + b.user.Username + " "
+	}
+	return settings.prefix()
+}
+
+func (b *PollingBot) sendScheduledReminders(ctx context.Context, now time.Time) {
+	ctx, span := b.tracer.Start(ctx, "scheduled reminders")
+	defer span.End()
+
+	polls, err := b.pollService.PollsToRemind(ctx, now)
+	if err != nil {
+		b.logger.ErrorContext(ctx, "Could not get polls to remind", "error", err)
+		return
+	}
+	for _, poll := range polls {
+		pollCtx := withLocale(ctx, b.locales.userLocale(ctx, poll.Author))
+		reminded, err := b.remind(pollCtx, poll, poll.Author, "")
+		if err != nil {
+			b.logger.WarnContext(ctx, "Could not send scheduled reminder", "poll_id", poll.ID, "error", err)
+			continue
+		}
+		b.logger.InfoContext(ctx, "Scheduled reminder sent", "poll_id", poll.ID, "reminded", reminded)
+	}
+}
+
+// threadRootID returns ID of the thread of the post.
+func threadRootID(post *model.Post) string {
+	if post.RootId != "" {
+		return post.RootId
+	}
+	return post.Id
+}
@@ -0,0 +1,111 @@
+package bot
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+	"sync"
+)
+
+// ResponseKind - kind of bot's response, visibility is configured for each kind.
+type ResponseKind string
+
+const (
+	// ResponseCreated - ID and options of a started poll.
+	ResponseCreated ResponseKind = "created"
+	// ResponseResults - results of a poll.
+	ResponseResults ResponseKind = "results"
+	// ResponseConfirmation - successful vote, close or delete.
+	ResponseConfirmation ResponseKind = "confirmation"
+	// ResponseError - invalid command, rejected request or failure.
+	ResponseError ResponseKind = "error"
+	// ResponseHelp - list of commands and help of a command.
+	ResponseHelp ResponseKind = "help"
+)
+
+// Visibility - who sees a response.
+type Visibility string
+
+const (
+	// VisibilityPublic - reply in the thread of the command, visible to everyone in the channel.
+	VisibilityPublic Visibility = "public"
+	// VisibilityEphemeral - ephemeral post visible only to the user who sent the command.
+	VisibilityEphemeral Visibility = "ephemeral"
+)
+
+// ResponseVisibility - visibility of responses by kind, kinds which are not listed use defaults.
+type ResponseVisibility map[ResponseKind]Visibility
+
+// DefaultResponseVisibility returns visibility used when nothing is configured:
+// polls and results are public, everything else is shown only to the user.
+func DefaultResponseVisibility() ResponseVisibility {
+	return ResponseVisibility{
+		ResponseCreated:      VisibilityPublic,
+		ResponseResults:      VisibilityPublic,
+		ResponseConfirmation: VisibilityEphemeral,
+		ResponseError:        VisibilityEphemeral,
+		ResponseHelp:         VisibilityEphemeral,
+	}
+}
+
+// Validate checks that all kinds and visibilities are known.
+func (v ResponseVisibility) Validate() error {
+	defaults := DefaultResponseVisibility()
+	for kind, visibility := range v {
+		if _, ok := defaults[kind]; !ok {
+			return fmt.Errorf("unknown response kind %q", kind)
+		}
+		if visibility != VisibilityPublic && visibility != VisibilityEphemeral {
+			return fmt.Errorf("visibility of %s must be %s or %s: %q", kind, VisibilityPublic, VisibilityEphemeral, visibility)
+		}
+	}
+	return nil
+}
+
+func (v ResponseVisibility) withDefaults() ResponseVisibility {
+	result := DefaultResponseVisibility()
+	maps.Copy(result, v)
+	return result
+}
+
+// ParseResponseVisibility parses visibility in the form "confirmation=ephemeral,results=public".
+func ParseResponseVisibility(s string) (ResponseVisibility, error) {
+	visibility := make(ResponseVisibility)
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		kind, value, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, fmt.Errorf("visibility %q must be in the form kind=visibility", item)
+		}
+		visibility[ResponseKind(strings.TrimSpace(kind))] = Visibility(strings.TrimSpace(value))
+	}
+	if err := visibility.Validate(); err != nil {
+		return nil, err
+	}
+	return visibility, nil
+}
+
+// responsePolicy - current visibility of responses, can be changed while the bot is running.
+type responsePolicy struct {
+	mu         sync.RWMutex
+	visibility ResponseVisibility
+}
+
+func newResponsePolicy(visibility ResponseVisibility) *responsePolicy {
+	return &responsePolicy{visibility: visibility.withDefaults()}
+}
+
+func (p *responsePolicy) set(visibility ResponseVisibility) {
+	p.mu.Lock()
+	p.visibility = visibility.withDefaults()
+	p.mu.Unlock()
+}
+
+func (p *responsePolicy) of(kind ResponseKind) Visibility {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.visibility[kind]
+}
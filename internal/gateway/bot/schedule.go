@@ -0,0 +1,60 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Xausdorf/mattermost-poll/internal/usecase"
+)
+
+// scheduleInterval - how often polls are checked for due reminders and automatic closing.
+const scheduleInterval = time.Minute
+
+// scheduleLoop sends scheduled reminders and closes expired polls until ctx is done.
+func (b *PollingBot) scheduleLoop(ctx context.Context) {
+	ticker := time.NewTicker(scheduleInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			b.closeExpiredPolls(ctx, now)
+			b.sendScheduledReminders(ctx, now)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// closeExpiredPolls closes polls whose time is over and posts their results to their channels.
+func (b *PollingBot) closeExpiredPolls(ctx context.Context, now time.Time) {
+	ctx, span := b.tracer.Start(ctx, "expired polls")
+	defer span.End()
+
+	polls, err := b.pollService.ExpiredPolls(ctx, now)
+	if err != nil {
+		b.logger.ErrorContext(ctx, "Could not get expired polls", "error", err)
+		return
+	}
+	for _, poll := range polls {
+		members, err := b.quorumMembers(poll)
+		if err != nil {
+			b.logger.WarnContext(ctx, "Could not count members for quorum", "poll_id", poll.ID, "error", err)
+			continue
+		}
+		closed, err := b.pollService.ExpirePoll(ctx, poll.ID, now, members)
+		if errors.Is(err, usecase.ErrPollIsNotActive) {
+			continue
+		}
+		if err != nil {
+			b.logger.WarnContext(ctx, "Could not close expired poll", "poll_id", poll.ID, "error", err)
+			continue
+		}
+
+		pollCtx := withLocale(ctx, b.locales.userLocale(ctx, closed.Poll.Author))
+		if err = b.announceClosed(pollCtx, closed, msgPollExpired, msgPollExpiredHidden); err != nil {
+			b.logger.WarnContext(ctx, "Could not post results of expired poll", "poll_id", poll.ID, "error", err)
+		}
+		b.sendClosedSummary(pollCtx, closed.Poll.ID)
+	}
+}
@@ -0,0 +1,141 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/Xausdorf/mattermost-poll/internal/domain"
+	"github.com/Xausdorf/mattermost-poll/internal/usecase"
+	"github.com/mattermost/mattermost-server/v6/model"
+)
+
+func (b *PollingBot) handleSuggest(ctx context.Context, post *model.Post, cmd *Command) outcome {
+	// !poll_suggest [pollID] [text]
+	pollID := cmd.Args[0]
+	suggestion, err := b.pollService.SuggestOption(ctx, pollID, post.UserId, cmd.Args[1])
+	if err != nil {
+		return b.suggestionError(ctx, post, pollID, err)
+	}
+
+	if suggestion.Status == domain.SuggestionApproved {
+		b.Respond(ctx, post, ResponseConfirmation,
+			b.tr(ctx, msgOptionAdded, suggestion.OptionID, domain.SanitizeText(suggestion.Text)))
+		return outcomeOK
+	}
+	b.Respond(ctx, post, ResponseConfirmation, b.tr(ctx, msgSuggestionPending))
+	b.notifySuggestion(ctx, post, suggestion)
+	return outcomeOK
+}
+
+// notifySuggestion sends the suggestion waiting for approval to the author of the poll.
+func (b *PollingBot) notifySuggestion(ctx context.Context, post *model.Post, suggestion *domain.Suggestion) {
+	poll, err := b.pollService.GetPollByID(ctx, suggestion.PollID)
+	if err != nil {
+		b.logger.WarnContext(ctx, "Could not get poll to notify about suggestion", "poll_id", suggestion.PollID, "error", err)
+		return
+	}
+	user, _, err := b.client.GetUser(post.UserId, "")
+	if err != nil {
+		b.logger.WarnContext(ctx, "Could not get user who suggested option", "user_id", post.UserId, "error", err)
+		return
+	}
+
+	ctx = withLocale(ctx, b.locales.userLocale(ctx, poll.Author))
+	prefix := b.commandPrefix(b.teams.settingsFor(ctx, poll.TeamID))
+	msg := b.tr(ctx, msgSuggestionReceived, user.Username, domain.SanitizeText(suggestion.Text), poll.ID, poll.Question,
+		prefix, suggestion.ID, prefix, suggestion.ID)
+	if err = b.SendDirect(ctx, poll.Author, msg); err != nil {
+		b.logger.WarnContext(ctx, "Could not notify author about suggestion", "poll_id", poll.ID, "error", err)
+	}
+}
+
+func (b *PollingBot) handleSuggestions(ctx context.Context, post *model.Post, cmd *Command) outcome {
+	// !poll_suggestions [pollID]
+	pollID := cmd.Args[0]
+	sender := b.sender(post)
+
+	suggestions, err := b.pollService.PendingSuggestions(ctx, pollID, sender)
+	if err != nil {
+		return b.suggestionError(ctx, post, pollID, err)
+	}
+	if len(suggestions) == 0 {
+		b.Respond(ctx, post, ResponseConfirmation, b.tr(ctx, msgNoSuggestions))
+		return outcomeOK
+	}
+
+	lines := []string{b.tr(ctx, msgSuggestionsHeader, cmd.Prefix, cmd.Prefix)}
+	for _, suggestion := range suggestions {
+		lines = append(lines, b.tr(ctx, msgSuggestionItem, suggestion.ID, domain.SanitizeText(suggestion.Text)))
+	}
+	b.Respond(ctx, post, ResponseConfirmation, strings.Join(lines, "\n"))
+	return outcomeOK
+}
+
+func (b *PollingBot) handleApprove(ctx context.Context, post *model.Post, cmd *Command) outcome {
+	// !poll_approve [suggestionID]
+	return b.reviewSuggestion(ctx, post, cmd.Args[0], true)
+}
+
+func (b *PollingBot) handleReject(ctx context.Context, post *model.Post, cmd *Command) outcome {
+	// !poll_reject [suggestionID]
+	return b.reviewSuggestion(ctx, post, cmd.Args[0], false)
+}
+
+func (b *PollingBot) reviewSuggestion(ctx context.Context, post *model.Post, id string, approve bool) outcome {
+	sender := b.sender(post)
+
+	suggestion, err := b.pollService.ReviewSuggestion(ctx, id, sender, approve)
+	if err != nil {
+		return b.suggestionError(ctx, post, "", err)
+	}
+	if approve {
+		b.Respond(ctx, post, ResponseConfirmation,
+			b.tr(ctx, msgOptionAdded, suggestion.OptionID, domain.SanitizeText(suggestion.Text)))
+	} else {
+		b.Respond(ctx, post, ResponseConfirmation, b.tr(ctx, msgSuggestionRejected))
+	}
+	return outcomeOK
+}
+
+// suggestionError responds to a failed suggestion or its review.
+func (b *PollingBot) suggestionError(ctx context.Context, post *model.Post, pollID string, err error) outcome {
+	switch {
+	case errors.Is(err, usecase.ErrPollNotFound):
+		b.Respond(ctx, post, ResponseError, b.tr(ctx, msgPollNotFound))
+		return outcomeRejected
+	case errors.Is(err, usecase.ErrPollIsNotActive):
+		b.Respond(ctx, post, ResponseError, b.tr(ctx, msgOptionsPollClosed))
+		return outcomeRejected
+	case errors.Is(err, usecase.ErrUserIsNotPollAuthor):
+		b.Respond(ctx, post, ResponseError, b.tr(ctx, msgSuggestionsNotAllowed))
+		return outcomeRejected
+	case errors.Is(err, usecase.ErrSuggestionsDisabled):
+		b.Respond(ctx, post, ResponseError, b.tr(ctx, msgSuggestionsDisabled))
+		return outcomeRejected
+	case errors.Is(err, usecase.ErrTooManySuggestions):
+		b.Respond(ctx, post, ResponseError, b.tr(ctx, msgTooManySuggestions))
+		return outcomeRejected
+	case errors.Is(err, usecase.ErrSuggestionExists):
+		b.Respond(ctx, post, ResponseError, b.tr(ctx, msgSuggestionExists))
+		return outcomeRejected
+	case errors.Is(err, usecase.ErrSuggestionNotFound):
+		b.Respond(ctx, post, ResponseError, b.tr(ctx, msgSuggestionNotFound))
+		return outcomeRejected
+	case errors.Is(err, usecase.ErrSuggestionReviewed):
+		b.Respond(ctx, post, ResponseError, b.tr(ctx, msgSuggestionReviewed))
+		return outcomeRejected
+	}
+	var validationErr *domain.ValidationError
+	if errors.As(err, &validationErr) {
+		if errors.Is(err, domain.ErrDuplicateOption) {
+			b.Respond(ctx, post, ResponseError, b.tr(ctx, msgSuggestionExists))
+		} else {
+			b.Respond(ctx, post, ResponseError, b.validationMessage(ctx, validationErr))
+		}
+		return outcomeRejected
+	}
+	b.logger.ErrorContext(ctx, "Failed to handle suggestion", "poll_id", pollID, "error", err)
+	b.Respond(ctx, post, ResponseError, b.tr(ctx, msgSuggestionsFailed))
+	return outcomeFailed
+}
@@ -0,0 +1,314 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Xausdorf/mattermost-poll/internal/domain"
+	"github.com/Xausdorf/mattermost-poll/internal/usecase"
+	"github.com/mattermost/mattermost-server/v6/model"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// surveyAnswerCommand - name under which answers to surveys are rate limited and reported, they are not commands.
+const surveyAnswerCommand = "survey_answer"
+
+// errOptionWithoutQuestion - a line of a survey is neither a question nor an option of a choice question.
+var errOptionWithoutQuestion = errors.New("option is not preceded by a choice question")
+
+func (b *PollingBot) handleSurveyStart(ctx context.Context, post *model.Post, cmd *Command) outcome {
+	// !survey_start [title]
+	// single: [question]
+	// - [option1]
+	// rating 1-5: [question]
+	// text: [question]
+	title, questions, err := surveyFromCommand(cmd)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidScale) {
+			b.Respond(ctx, post, ResponseError, b.tr(ctx, msgInvalidScale))
+		} else {
+			b.Respond(ctx, post, ResponseError, b.tr(ctx, msgSurveyOptionWithoutQuestion, cmd.Prefix))
+		}
+		return outcomeRejected
+	}
+
+	survey := domain.NewSurvey(title, questions, post.UserId)
+	survey.TeamID = originFromContext(ctx).teamID
+	survey.ChannelID = post.ChannelId
+	if err = b.pollService.CreateSurvey(ctx, survey); err != nil {
+		return b.surveyError(ctx, post, survey.ID, err)
+	}
+	b.logger.InfoContext(ctx, "Survey succesfully created", "survey_id", survey.ID)
+
+	b.Respond(ctx, post, ResponseCreated,
+		b.tr(ctx, msgSurveyCreated, survey.ID, survey.Title, len(survey.Questions), cmd.Prefix, survey.ID))
+	return outcomeOK
+}
+
+// surveyFromCommand extracts the title and questions of !survey_start. The first line is the title,
+// every following line is either a question in the form "type: text" or an option of the previous choice question.
+func surveyFromCommand(cmd *Command) (string, []domain.SurveyQuestion, error) {
+	title := strings.Join(cmd.Args, " ")
+	var questions []domain.SurveyQuestion
+	for _, line := range cmd.Body {
+		question, ok, err := parseQuestionHeader(line)
+		if err != nil {
+			return "", nil, err
+		}
+		if ok {
+			questions = append(questions, question)
+			continue
+		}
+		if len(questions) == 0 || !questions[len(questions)-1].Type.HasOptions() {
+			return "", nil, errOptionWithoutQuestion
+		}
+		last := &questions[len(questions)-1]
+		last.Options = append(last.Options, ParseListItem(line)...)
+	}
+	return title, questions, nil
+}
+
+// parseQuestionHeader parses a line like "single: text", "rating 1-5: text" or "text anonymous: text".
+// Returns false if the line doesn't start with a type of question, so it is an option. List items are always options.
+func parseQuestionHeader(line string) (domain.SurveyQuestion, bool, error) {
+	head, text, ok := strings.Cut(strings.TrimSpace(line), ":")
+	if !ok {
+		return domain.SurveyQuestion{}, false, nil
+	}
+	fields := strings.Fields(head)
+	if len(fields) == 0 || len(fields) > 2 {
+		return domain.SurveyQuestion{}, false, nil
+	}
+	questionType, err := domain.ParseQuestionType(fields[0])
+	if err != nil {
+		return domain.SurveyQuestion{}, false, nil
+	}
+
+	question := domain.SurveyQuestion{Text: strings.TrimSpace(text), Type: questionType}
+	switch {
+	case questionType == domain.QuestionRating:
+		scale := ""
+		if len(fields) == 2 {
+			scale = fields[1]
+		}
+		if question.Scale, err = domain.ParseScale(scale); err != nil {
+			return domain.SurveyQuestion{}, false, err
+		}
+	case questionType == domain.QuestionText && len(fields) == 2:
+		if !strings.EqualFold(fields[1], "anonymous") {
+			return domain.SurveyQuestion{}, false, nil
+		}
+		question.Anonymous = true
+	case len(fields) == 2:
+		return domain.SurveyQuestion{}, false, nil
+	}
+	return question, true, nil
+}
+
+func (b *PollingBot) handleSurveyTake(ctx context.Context, post *model.Post, cmd *Command) outcome {
+	// !survey_take [surveyID]
+	surveyID := cmd.Args[0]
+	survey, response, err := b.pollService.TakeSurvey(ctx, surveyID, post.UserId)
+	if err != nil {
+		return b.surveyError(ctx, post, surveyID, err)
+	}
+
+	// questions are asked in the direct channel, in the language of the user
+	directCtx := withLocale(ctx, b.locales.userLocale(ctx, post.UserId))
+	msg := b.surveyQuestionMessage(directCtx, survey, survey.NextQuestion(response))
+	if err = b.SendDirect(directCtx, post.UserId, msg); err != nil {
+		b.logger.ErrorContext(ctx, "Failed to send survey question", "survey_id", surveyID, "error", err)
+		b.Respond(ctx, post, ResponseError, b.tr(ctx, msgSurveyFailed))
+		return outcomeFailed
+	}
+	if !originFromContext(ctx).direct {
+		b.Respond(ctx, post, ResponseConfirmation, b.tr(ctx, msgSurveyQuestionsSent))
+	}
+	return outcomeOK
+}
+
+// handleDirectMessage treats a direct message which is not a command as an answer to the survey in progress.
+// Messages of users without a survey in progress are ignored and don't take tokens of the rate limiter.
+func (b *PollingBot) handleDirectMessage(ctx context.Context, post *model.Post) {
+	ctx = withLocale(ctx, b.locales.resolve(ctx, post))
+	if _, _, err := b.pollService.SurveyInProgress(ctx, post.UserId); err != nil {
+		if !errors.Is(err, usecase.ErrSurveyResponseNotFound) {
+			b.logger.ErrorContext(ctx, "Failed to get survey in progress", "user_id", post.UserId, "error", err)
+			b.Respond(ctx, post, ResponseError, b.tr(ctx, msgSurveyFailed))
+		}
+		return
+	}
+
+	result := outcomeRateLimited
+	if b.allowCommand(ctx, post, surveyAnswerCommand) {
+		var ok bool
+		if result, ok = b.answerSurvey(ctx, post); !ok {
+			return
+		}
+	}
+	b.observer.CommandHandled(surveyAnswerCommand, string(result))
+
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(attribute.String("command", surveyAnswerCommand), attribute.String("outcome", string(result)))
+	if result == outcomeFailed {
+		span.SetStatus(codes.Error, "survey answer failed")
+	}
+}
+
+// answerSurvey saves the answer and asks the next question. Returns false if the user has no survey in progress.
+func (b *PollingBot) answerSurvey(ctx context.Context, post *model.Post) (outcome, bool) {
+	survey, response, err := b.pollService.AnswerSurvey(ctx, post.UserId, post.Message)
+	switch {
+	case errors.Is(err, usecase.ErrSurveyResponseNotFound):
+		return "", false
+	case errors.Is(err, domain.ErrInvalidAnswer) && survey != nil:
+		question := survey.NextQuestion(response)
+		if question == nil {
+			// every question is answered already, e.g. the last answer was sent twice
+			b.Respond(ctx, post, ResponseConfirmation, b.tr(ctx, msgSurveyCompleted, survey.Title))
+			return outcomeOK, true
+		}
+		b.Respond(ctx, post, ResponseError, b.tr(ctx, msgInvalidSurveyAnswer, b.surveyAnswerHint(ctx, question)))
+		return outcomeRejected, true
+	case errors.Is(err, domain.ErrAnswerTooLong):
+		var validationErr *domain.ValidationError
+		errors.As(err, &validationErr)
+		b.Respond(ctx, post, ResponseError, b.validationMessage(ctx, validationErr))
+		return outcomeRejected, true
+	case err != nil:
+		b.logger.ErrorContext(ctx, "Failed to answer survey", "user_id", post.UserId, "error", err)
+		b.Respond(ctx, post, ResponseError, b.tr(ctx, msgSurveyFailed))
+		return outcomeFailed, true
+	}
+
+	if question := survey.NextQuestion(response); !response.Completed && question != nil {
+		b.Respond(ctx, post, ResponseConfirmation, b.surveyQuestionMessage(ctx, survey, question))
+	} else {
+		b.Respond(ctx, post, ResponseConfirmation, b.tr(ctx, msgSurveyCompleted, survey.Title))
+	}
+	return outcomeOK, true
+}
+
+// surveyQuestionMessage formats the question with its number, options and a hint how to answer it.
+func (b *PollingBot) surveyQuestionMessage(
+	ctx context.Context,
+	survey *domain.Survey,
+	question *domain.SurveyQuestion,
+) string {
+	lines := []string{b.tr(ctx, msgSurveyQuestion, survey.Title, question.ID+1, len(survey.Questions), question.Text)}
+	for i, option := range question.Options {
+		lines = append(lines, fmt.Sprintf("%d. %s", i, option))
+	}
+	lines = append(lines, b.surveyAnswerHint(ctx, question))
+	return strings.Join(lines, "\n")
+}
+
+// surveyAnswerHint explains how to answer a question of the type.
+func (b *PollingBot) surveyAnswerHint(ctx context.Context, question *domain.SurveyQuestion) string {
+	switch question.Type {
+	case domain.QuestionSingle:
+		return b.tr(ctx, msgSurveyHintSingle)
+	case domain.QuestionMulti:
+		return b.tr(ctx, msgSurveyHintMulti)
+	case domain.QuestionRating:
+		return b.tr(ctx, msgSurveyHintRating, question.Scale.Min, question.Scale.Max)
+	case domain.QuestionText:
+		if question.Anonymous {
+			return b.tr(ctx, msgSurveyHintAnonymous)
+		}
+		return b.tr(ctx, msgSurveyHintText)
+	default:
+		return b.tr(ctx, msgSurveyHintText)
+	}
+}
+
+func (b *PollingBot) handleSurveyResults(ctx context.Context, post *model.Post, cmd *Command) outcome {
+	// !survey_results [surveyID]
+	surveyID := cmd.Args[0]
+	results, err := b.pollService.SurveyResults(ctx, surveyID, b.sender(post))
+	if err != nil {
+		return b.surveyError(ctx, post, surveyID, err)
+	}
+	b.Respond(ctx, post, ResponseResults, b.surveyResultsMessage(ctx, results))
+	return outcomeOK
+}
+
+// surveyResultsMessage formats answers to every question: votes of options, statistics of scores
+// or text answers published by the author.
+func (b *PollingBot) surveyResultsMessage(ctx context.Context, results *domain.SurveyResults) string {
+	lines := []string{b.tr(ctx, msgSurveyResults, results.Survey.Title, results.Respondents, results.Completed)}
+	for _, result := range results.Questions {
+		question := result.Question
+		lines = append(lines, fmt.Sprintf("\n**%d. %s**", question.ID+1, question.Text),
+			b.tr(ctx, msgSurveyQuestionAnswers, result.Answers))
+		switch question.Type {
+		case domain.QuestionSingle, domain.QuestionMulti:
+			for i, option := range question.Options {
+				lines = append(lines, b.tr(ctx, msgSurveyOptionVotes, i, option, result.Votes[i]))
+			}
+		case domain.QuestionRating:
+			lines = append(lines, b.ratingLines(ctx, question.Scale, result.Rating)...)
+		case domain.QuestionText:
+			for _, answer := range result.Published {
+				lines = append(lines, "> "+domain.SanitizeText(answer.Text))
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (b *PollingBot) handleSurveyClose(ctx context.Context, post *model.Post, cmd *Command) outcome {
+	// !survey_close [surveyID]
+	surveyID := cmd.Args[0]
+	sender := b.sender(post)
+
+	if err := b.pollService.CloseSurvey(ctx, surveyID, sender); err != nil {
+		return b.surveyError(ctx, post, surveyID, err)
+	}
+	b.Respond(ctx, post, ResponseConfirmation, b.tr(ctx, msgSurveyClosed))
+	return outcomeOK
+}
+
+// surveyError responds to a failed survey command.
+func (b *PollingBot) surveyError(ctx context.Context, post *model.Post, surveyID string, err error) outcome {
+	switch {
+	case errors.Is(err, usecase.ErrSurveyNotFound):
+		b.Respond(ctx, post, ResponseError, b.tr(ctx, msgSurveyNotFound))
+		return outcomeRejected
+	case errors.Is(err, usecase.ErrSurveyIsNotActive):
+		b.Respond(ctx, post, ResponseError, b.tr(ctx, msgSurveyIsClosed))
+		return outcomeRejected
+	case errors.Is(err, usecase.ErrSurveyCompleted):
+		b.Respond(ctx, post, ResponseError, b.tr(ctx, msgSurveyAlreadyCompleted))
+		return outcomeRejected
+	case errors.Is(err, usecase.ErrUserIsNotPollAuthor):
+		b.Respond(ctx, post, ResponseError, b.tr(ctx, msgSurveyNotAllowed))
+		return outcomeRejected
+	case errors.Is(err, usecase.ErrSurveyQuestionNotFound):
+		b.Respond(ctx, post, ResponseError, b.tr(ctx, msgSurveyQuestionNotFound))
+		return outcomeRejected
+	case errors.Is(err, usecase.ErrNotTextQuestion):
+		b.Respond(ctx, post, ResponseError, b.tr(ctx, msgNotTextQuestion))
+		return outcomeRejected
+	case errors.Is(err, usecase.ErrTextAnswerNotFound):
+		b.Respond(ctx, post, ResponseError, b.tr(ctx, msgTextAnswersChanged))
+		return outcomeRejected
+	}
+	var validationErr *domain.ValidationError
+	if errors.As(err, &validationErr) {
+		msg := b.validationMessage(ctx, validationErr)
+		var questionErr *domain.QuestionError
+		if errors.As(err, &questionErr) {
+			msg = b.tr(ctx, msgSurveyInvalidQuestion, questionErr.Question+1, msg)
+		}
+		b.Respond(ctx, post, ResponseError, msg)
+		return outcomeRejected
+	}
+	b.logger.ErrorContext(ctx, "Failed to handle survey", "survey_id", surveyID, "error", err)
+	b.Respond(ctx, post, ResponseError, b.tr(ctx, msgSurveyFailed))
+	return outcomeFailed
+}
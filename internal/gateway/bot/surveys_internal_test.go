@@ -0,0 +1,151 @@
+package bot
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Xausdorf/mattermost-poll/internal/domain"
+)
+
+func TestParseQuestionHeader(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    string
+		want    domain.SurveyQuestion
+		wantOK  bool
+		wantErr error
+	}{
+		{
+			name:   "single",
+			line:   "single: Lunch?",
+			want:   domain.SurveyQuestion{Text: "Lunch?", Type: domain.QuestionSingle},
+			wantOK: true,
+		},
+		{
+			name:   "type in upper case",
+			line:   "  MULTI :  Drinks? ",
+			want:   domain.SurveyQuestion{Text: "Drinks?", Type: domain.QuestionMulti},
+			wantOK: true,
+		},
+		{
+			name:   "rating with default scale",
+			line:   "rating: How was it?",
+			want:   domain.SurveyQuestion{Text: "How was it?", Type: domain.QuestionRating, Scale: domain.DefaultScale},
+			wantOK: true,
+		},
+		{
+			name:   "rating with scale",
+			line:   "rating 0-3: How was it?",
+			want:   domain.SurveyQuestion{Text: "How was it?", Type: domain.QuestionRating, Scale: domain.Scale{Max: 3}},
+			wantOK: true,
+		},
+		{
+			name:   "rating with nps scale",
+			line:   "rating nps: Recommend us?",
+			want:   domain.SurveyQuestion{Text: "Recommend us?", Type: domain.QuestionRating, Scale: domain.NPSScale},
+			wantOK: true,
+		},
+		{name: "rating with invalid scale", line: "rating 5-1: How was it?", wantErr: domain.ErrInvalidScale},
+		{
+			name:   "text",
+			line:   "text: Comments?",
+			want:   domain.SurveyQuestion{Text: "Comments?", Type: domain.QuestionText},
+			wantOK: true,
+		},
+		{
+			name:   "anonymous text",
+			line:   "text Anonymous: Comments?",
+			want:   domain.SurveyQuestion{Text: "Comments?", Type: domain.QuestionText, Anonymous: true},
+			wantOK: true,
+		},
+		{name: "text with unknown modifier", line: "text public: Comments?"},
+		{name: "modifier of choice question", line: "single anonymous: Lunch?"},
+		{name: "option without colon", line: "Pizza"},
+		{name: "option with colon", line: "Time: 12:00"},
+		{name: "too many words before colon", line: "rating 1-5 nps: How was it?"},
+		{name: "empty type", line: ": Lunch?"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok, err := parseQuestionHeader(tt.line)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("parseQuestionHeader(%q) error = %v, want %v", tt.line, err, tt.wantErr)
+			}
+			if ok != tt.wantOK || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseQuestionHeader(%q) = %+v, %v, want %+v, %v", tt.line, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestSurveyFromCommand(t *testing.T) {
+	tests := []struct {
+		name          string
+		msg           string
+		wantTitle     string
+		wantQuestions []domain.SurveyQuestion
+		wantErr       error
+	}{
+		{
+			name:      "questions with options",
+			msg:       "!survey_start Team retro\nsingle: Lunch?\n- Pizza\n- Sushi\nrating: Sprint?\ntext anonymous: Comments?",
+			wantTitle: "Team retro",
+			wantQuestions: []domain.SurveyQuestion{
+				{Text: "Lunch?", Type: domain.QuestionSingle, Options: []string{"Pizza", "Sushi"}},
+				{Text: "Sprint?", Type: domain.QuestionRating, Scale: domain.DefaultScale},
+				{Text: "Comments?", Type: domain.QuestionText, Anonymous: true},
+			},
+		},
+		{
+			name:      "several quoted options in one line",
+			msg:       "!survey_start Retro\nmulti: Drinks?\n- \"Tea\" \"Coffee\"\n- Water",
+			wantTitle: "Retro",
+			wantQuestions: []domain.SurveyQuestion{
+				{Text: "Drinks?", Type: domain.QuestionMulti, Options: []string{"Tea", "Coffee", "Water"}},
+			},
+		},
+		{
+			name:      "option with colon",
+			msg:       "!survey_start Retro\nsingle: Time?\n- Start: 12:00",
+			wantTitle: "Retro",
+			wantQuestions: []domain.SurveyQuestion{
+				{Text: "Time?", Type: domain.QuestionSingle, Options: []string{"Start: 12:00"}},
+			},
+		},
+		{
+			name:    "option before questions",
+			msg:     "!survey_start Retro\n- Pizza\nsingle: Lunch?",
+			wantErr: errOptionWithoutQuestion,
+		},
+		{
+			name:    "option of rating question",
+			msg:     "!survey_start Retro\nrating: Sprint?\n- Good",
+			wantErr: errOptionWithoutQuestion,
+		},
+		{
+			name:    "option of text question",
+			msg:     "!survey_start Retro\ntext: Comments?\n- None",
+			wantErr: errOptionWithoutQuestion,
+		},
+		{name: "invalid scale", msg: "!survey_start Retro\nrating 1-x: Sprint?", wantErr: domain.ErrInvalidScale},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, err := ParseMultilineCommand(tt.msg, "!")
+			if err != nil {
+				t.Fatalf("ParseMultilineCommand(%q) error: %v", tt.msg, err)
+			}
+			title, questions, err := surveyFromCommand(cmd)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("surveyFromCommand() error = %v, want %v", err, tt.wantErr)
+			}
+			if title != tt.wantTitle {
+				t.Errorf("title = %q, want %q", title, tt.wantTitle)
+			}
+			if !reflect.DeepEqual(questions, tt.wantQuestions) {
+				t.Errorf("questions = %+v, want %+v", questions, tt.wantQuestions)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+)
+
+// TeamSettings - settings of a team, keyed by team name or ID in Settings.Teams.
+type TeamSettings struct {
+	// AllowedChannels - names or IDs of channels where commands are handled, empty means every channel.
+	AllowedChannels []string
+	// CommandPrefix - prefix of commands in the team, prefix of the deployment if empty.
+	CommandPrefix string
+	// MentionOnly - the bot handles only commands which start with its mention, like "@pollingbot poll_vote".
+	// Nil means the setting of the deployment, so a team can turn the mode off too.
+	MentionOnly *bool
+	// DefaultOptions - options of polls started with a question only.
+	DefaultOptions []string
+}
+
+func (s TeamSettings) prefix() string {
+	if s.CommandPrefix == "" {
+		return defaultCommandPrefix
+	}
+	return s.CommandPrefix
+}
+
+func (s TeamSettings) mentionOnly() bool {
+	return s.MentionOnly != nil && *s.MentionOnly
+}
+
+// withDefaults fills the settings which are not set for the team from settings of the deployment.
+func (s TeamSettings) withDefaults(defaults TeamSettings) TeamSettings {
+	if s.CommandPrefix == "" {
+		s.CommandPrefix = defaults.CommandPrefix
+	}
+	if len(s.DefaultOptions) == 0 {
+		s.DefaultOptions = defaults.DefaultOptions
+	}
+	if s.MentionOnly == nil {
+		s.MentionOnly = defaults.MentionOnly
+	}
+	return s
+}
+
+// allows returns true if commands can be used in the channel.
+func (s TeamSettings) allows(channelID string, channelName string) bool {
+	return len(s.AllowedChannels) == 0 ||
+		slices.Contains(s.AllowedChannels, channelID) ||
+		slices.Contains(s.AllowedChannels, channelName)
+}
+
+// postOrigin - where the post being handled was written: its team with settings and type of the channel.
+type postOrigin struct {
+	// teamID - empty for direct and group messages.
+	teamID string
+	// direct - the post is a direct message to the bot.
+	direct   bool
+	settings TeamSettings
+}
+
+type originContextKey struct{}
+
+func withOrigin(ctx context.Context, origin postOrigin) context.Context {
+	return context.WithValue(ctx, originContextKey{}, origin)
+}
+
+func originFromContext(ctx context.Context) postOrigin {
+	origin, _ := ctx.Value(originContextKey{}).(postOrigin)
+	return origin
+}
+
+// teamRegistry - teams the bot is a member of, updated by websocket events.
+type teamRegistry struct {
+	client *model.Client4
+	logger *slog.Logger
+
+	mu       sync.RWMutex
+	teams    map[string]*model.Team
+	settings map[string]TeamSettings
+	defaults TeamSettings
+}
+
+func newTeamRegistry(client *model.Client4, logger *slog.Logger) *teamRegistry {
+	return &teamRegistry{
+		client: client,
+		logger: logger,
+		teams:  make(map[string]*model.Team),
+	}
+}
+
+// discover loads all teams of the user.
+func (r *teamRegistry) discover(ctx context.Context, userID string) error {
+	teams, _, err := r.client.GetTeamsForUser(userID, "")
+	if err != nil {
+		return fmt.Errorf("could not get teams of the bot: %w", err)
+	}
+
+	r.mu.Lock()
+	for _, team := range teams {
+		r.teams[team.Id] = team
+	}
+	r.mu.Unlock()
+
+	if len(teams) == 0 {
+		r.logger.WarnContext(ctx, "Bot is not a member of any team")
+	}
+	for _, team := range teams {
+		r.logger.InfoContext(ctx, "Team found", "team_id", team.Id, "team", team.Name)
+	}
+	return nil
+}
+
+func (r *teamRegistry) add(ctx context.Context, teamID string) (*model.Team, error) {
+	team, _, err := r.client.GetTeam(teamID, "")
+	if err != nil {
+		return nil, fmt.Errorf("could not get team %s: %w", teamID, err)
+	}
+
+	r.mu.Lock()
+	r.teams[team.Id] = team
+	r.mu.Unlock()
+
+	r.logger.InfoContext(ctx, "Added to team", "team_id", team.Id, "team", team.Name)
+	return team, nil
+}
+
+func (r *teamRegistry) remove(ctx context.Context, teamID string) {
+	r.mu.Lock()
+	delete(r.teams, teamID)
+	r.mu.Unlock()
+
+	r.logger.InfoContext(ctx, "Removed from team", "team_id", teamID)
+}
+
+// setSettings replaces settings of teams, defaults are used for everything not set for a team.
+func (r *teamRegistry) setSettings(settings map[string]TeamSettings, defaults TeamSettings) {
+	r.mu.Lock()
+	r.settings = settings
+	r.defaults = defaults
+	r.mu.Unlock()
+}
+
+// settingsFor returns settings of the team by its ID or name, default settings for posts outside of teams.
+func (r *teamRegistry) settingsFor(ctx context.Context, teamID string) TeamSettings {
+	return r.teamSettings(ctx, teamID).withDefaults(r.defaultSettings())
+}
+
+func (r *teamRegistry) defaultSettings() TeamSettings {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.defaults
+}
+
+func (r *teamRegistry) teamSettings(ctx context.Context, teamID string) TeamSettings {
+	if teamID == "" {
+		return TeamSettings{}
+	}
+
+	r.mu.RLock()
+	team, known := r.teams[teamID]
+	settings, ok := r.settings[teamID]
+	r.mu.RUnlock()
+	if ok {
+		return settings
+	}
+
+	if !known {
+		// membership event may come after the post, so the team is loaded on demand
+		var err error
+		if team, err = r.add(ctx, teamID); err != nil {
+			r.logger.WarnContext(ctx, "Could not get team of the post", "team_id", teamID, "error", err)
+			return TeamSettings{}
+		}
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.settings[team.Name]
+}
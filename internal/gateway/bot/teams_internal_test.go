@@ -0,0 +1,65 @@
+package bot
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTeamSettingsWithDefaults(t *testing.T) {
+	on, off := true, false
+	tests := []struct {
+		name            string
+		team            TeamSettings
+		defaults        TeamSettings
+		wantPrefix      string
+		wantMentionOnly bool
+		wantOptions     []string
+	}{
+		{
+			name:       "nothing set",
+			wantPrefix: defaultCommandPrefix,
+		},
+		{
+			name:            "defaults",
+			defaults:        TeamSettings{CommandPrefix: "poll/", MentionOnly: &on, DefaultOptions: []string{"Yes", "No"}},
+			wantPrefix:      "poll/",
+			wantMentionOnly: true,
+			wantOptions:     []string{"Yes", "No"},
+		},
+		{
+			name:            "team overrides",
+			team:            TeamSettings{CommandPrefix: "?", MentionOnly: &on, DefaultOptions: []string{"Da", "Net"}},
+			defaults:        TeamSettings{CommandPrefix: "poll/", MentionOnly: &off, DefaultOptions: []string{"Yes", "No"}},
+			wantPrefix:      "?",
+			wantMentionOnly: true,
+			wantOptions:     []string{"Da", "Net"},
+		},
+		{
+			name:       "team turns mention-only mode off",
+			team:       TeamSettings{MentionOnly: &off},
+			defaults:   TeamSettings{MentionOnly: &on},
+			wantPrefix: defaultCommandPrefix,
+		},
+		{
+			name:            "team does not set mention-only mode",
+			team:            TeamSettings{CommandPrefix: "?"},
+			defaults:        TeamSettings{MentionOnly: &on},
+			wantPrefix:      "?",
+			wantMentionOnly: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.team.withDefaults(tt.defaults)
+			if got.prefix() != tt.wantPrefix {
+				t.Errorf("prefix() = %q, want %q", got.prefix(), tt.wantPrefix)
+			}
+			if got.mentionOnly() != tt.wantMentionOnly {
+				t.Errorf("mentionOnly() = %v, want %v", got.mentionOnly(), tt.wantMentionOnly)
+			}
+			if !slices.Equal(got.DefaultOptions, tt.wantOptions) {
+				t.Errorf("DefaultOptions = %q, want %q", got.DefaultOptions, tt.wantOptions)
+			}
+		})
+	}
+}
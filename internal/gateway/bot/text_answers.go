@@ -0,0 +1,246 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Xausdorf/mattermost-poll/internal/domain"
+	"github.com/Xausdorf/mattermost-poll/internal/usecase"
+	"github.com/mattermost/mattermost-server/v6/model"
+)
+
+// textAnswersPageSize - count of text answers shown by a single !survey_answers.
+const textAnswersPageSize = 10
+
+func (b *PollingBot) handleSurveyAnswers(ctx context.Context, post *model.Post, cmd *Command) outcome {
+	// !survey_answers [--page=N] [surveyID] [question]
+	surveyID := cmd.Args[0]
+	page := 1
+	if value, ok := cmd.Flag("page"); ok {
+		var err error
+		if page, err = strconv.Atoi(value); err != nil || page < 1 {
+			b.Respond(ctx, post, ResponseError, b.tr(ctx, msgInvalidPage))
+			return outcomeRejected
+		}
+	}
+	sender := b.sender(post)
+	survey, answers, result := b.textAnswers(ctx, post, sender, surveyID, cmd.Args[1])
+	if result != outcomeOK {
+		return result
+	}
+	if len(answers) == 0 {
+		b.Respond(ctx, post, ResponseConfirmation, b.tr(ctx, msgNoTextAnswers))
+		return outcomeOK
+	}
+
+	pages := (len(answers) + textAnswersPageSize - 1) / textAnswersPageSize
+	if page > pages {
+		b.Respond(ctx, post, ResponseError, b.tr(ctx, msgPageOutOfRange, pages))
+		return outcomeRejected
+	}
+	first := (page - 1) * textAnswersPageSize
+	last := min(first+textAnswersPageSize, len(answers))
+	names := b.usernames(ctx, answers[first:last])
+
+	question := cmd.Args[1]
+	lines := []string{b.tr(ctx, msgTextAnswersHeader, question, survey.Title, page, pages, len(answers),
+		cmd.Prefix, surveyID, question)}
+	for i, answer := range answers[first:last] {
+		line := fmt.Sprintf("%d. %s", first+i+1, domain.SanitizeText(answer.Text))
+		if name, ok := names[answer.UserID]; ok {
+			line += b.tr(ctx, msgTextAnswerAuthor, name)
+		}
+		if answer.Published {
+			line += b.tr(ctx, msgTextAnswerPublished)
+		}
+		lines = append(lines, line)
+	}
+	if page < pages {
+		lines = append(lines, b.tr(ctx, msgTextAnswersNextPage, cmd.Prefix, page+1, surveyID, question))
+	}
+	// the list has answers which are not published yet and names of users, only the author or an admin sees it
+	b.RespondPrivately(ctx, post, strings.Join(lines, "\n"))
+	return outcomeOK
+}
+
+func (b *PollingBot) handleSurveyExport(ctx context.Context, post *model.Post, cmd *Command) outcome {
+	// !survey_export [surveyID] [question]
+	surveyID := cmd.Args[0]
+	sender := b.sender(post)
+	survey, answers, result := b.textAnswers(ctx, post, sender, surveyID, cmd.Args[1])
+	if result != outcomeOK {
+		return result
+	}
+
+	data, err := textAnswersCSV(answers, b.usernames(ctx, answers))
+	if err != nil {
+		b.logger.ErrorContext(ctx, "Failed to build export of text answers", "survey_id", surveyID, "error", err)
+		b.Respond(ctx, post, ResponseError, b.tr(ctx, msgSurveyFailed))
+		return outcomeFailed
+	}
+	filename := fmt.Sprintf("survey-%s-question-%s.csv", surveyID, cmd.Args[1])
+	msg := b.tr(ctx, msgTextAnswersExport, cmd.Args[1], survey.Title, len(answers))
+	if err = b.sendDirectFile(ctx, post.UserId, msg, filename, data); err != nil {
+		b.logger.ErrorContext(ctx, "Failed to send export of text answers", "survey_id", surveyID, "error", err)
+		b.Respond(ctx, post, ResponseError, b.tr(ctx, msgSurveyFailed))
+		return outcomeFailed
+	}
+	if !originFromContext(ctx).direct {
+		b.Respond(ctx, post, ResponseConfirmation, b.tr(ctx, msgTextAnswersExportSent))
+	}
+	return outcomeOK
+}
+
+// textAnswersCSV returns answers as CSV with a header, users of anonymous answers are empty.
+func textAnswersCSV(answers []*domain.TextAnswer, names map[string]string) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write([]string{"number", "created_at", "user", "published", "text"}); err != nil {
+		return nil, err
+	}
+	for i, answer := range answers {
+		record := []string{
+			strconv.Itoa(i + 1),
+			answer.CreatedAt.UTC().Format(time.RFC3339),
+			csvCell(names[answer.UserID]),
+			strconv.FormatBool(answer.Published),
+			csvCell(answer.Text),
+		}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// csvCell escapes text written by users, so spreadsheets don't run it as a formula.
+func csvCell(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+// sendDirectFile sends the message with the attached file to the user in the direct channel with the bot.
+func (b *PollingBot) sendDirectFile(
+	ctx context.Context,
+	userID string,
+	msg string,
+	filename string,
+	data []byte,
+) error {
+	channel, _, err := b.client.CreateDirectChannel(b.user.Id, userID)
+	if err != nil {
+		return fmt.Errorf("could not open direct channel: %w", err)
+	}
+	upload, _, err := b.client.UploadFile(data, channel.Id, filename)
+	if err != nil {
+		return fmt.Errorf("could not upload file: %w", err)
+	}
+	fileIDs := make(model.StringArray, len(upload.FileInfos))
+	for i, info := range upload.FileInfos {
+		fileIDs[i] = info.Id
+	}
+	resp := &model.Post{ChannelId: channel.Id, Message: msg, FileIds: fileIDs}
+	if _, _, err = b.client.CreatePost(resp); err != nil {
+		return fmt.Errorf("could not send direct message: %w", err)
+	}
+	b.logger.DebugContext(ctx, "Direct message with file sent", "user_id", userID, "filename", filename)
+	return nil
+}
+
+func (b *PollingBot) handleSurveyPublish(ctx context.Context, post *model.Post, cmd *Command) outcome {
+	// !survey_publish [surveyID] [question] [answer]...
+	surveyID := cmd.Args[0]
+	sender := b.sender(post)
+	survey, answers, result := b.textAnswers(ctx, post, sender, surveyID, cmd.Args[1])
+	if result != outcomeOK {
+		return result
+	}
+	ids := make([]string, 0, len(cmd.Args)-2)
+	for _, arg := range cmd.Args[2:] {
+		number, err := strconv.Atoi(arg)
+		if err != nil || number < 1 || number > len(answers) {
+			b.Respond(ctx, post, ResponseError, b.tr(ctx, msgTextAnswerNotFound, arg))
+			return outcomeRejected
+		}
+		if id := answers[number-1].ID; !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+
+	_, published, err := b.pollService.PublishTextAnswers(ctx, surveyID, sender, ids)
+	if err != nil {
+		return b.surveyError(ctx, post, surveyID, err)
+	}
+	if len(published) == 0 {
+		b.Respond(ctx, post, ResponseError, b.tr(ctx, msgNoTextAnswers))
+		return outcomeRejected
+	}
+
+	questionID := published[0].QuestionID
+	lines := []string{b.tr(ctx, msgTextAnswersPublished, survey.Questions[questionID].Text, survey.Title)}
+	for _, answer := range published {
+		lines = append(lines, "> "+domain.SanitizeText(answer.Text))
+	}
+	msg := strings.Join(lines, "\n")
+	if _, _, err = b.client.CreatePost(&model.Post{ChannelId: survey.ChannelID, Message: msg}); err != nil {
+		b.logger.ErrorContext(ctx, "Failed to post published text answers", "survey_id", surveyID, "error", err)
+		b.Respond(ctx, post, ResponseError, b.tr(ctx, msgSurveyFailed))
+		return outcomeFailed
+	}
+	b.Respond(ctx, post, ResponseConfirmation, b.tr(ctx, msgTextAnswersPublishedCount, len(published)))
+	return outcomeOK
+}
+
+// textAnswers returns the survey and answers to its text question, given by its number starting from 1.
+// Responds to the post and returns the outcome if answers can't be shown.
+func (b *PollingBot) textAnswers(
+	ctx context.Context,
+	post *model.Post,
+	sender usecase.Sender,
+	surveyID string,
+	question string,
+) (*domain.Survey, []*domain.TextAnswer, outcome) {
+	number, err := strconv.Atoi(question)
+	if err != nil {
+		b.Respond(ctx, post, ResponseError, b.tr(ctx, msgSurveyQuestionNotFound))
+		return nil, nil, outcomeRejected
+	}
+	survey, answers, err := b.pollService.TextAnswers(ctx, surveyID, number-1, sender)
+	if err != nil {
+		return nil, nil, b.surveyError(ctx, post, surveyID, err)
+	}
+	return survey, answers, outcomeOK
+}
+
+// usernames returns names of users who gave the answers, keyed by user ID. Anonymous answers have no user.
+// If users can't be fetched, answers are shown without names.
+func (b *PollingBot) usernames(ctx context.Context, answers []*domain.TextAnswer) map[string]string {
+	var ids []string
+	for _, answer := range answers {
+		if answer.UserID != "" {
+			ids = append(ids, answer.UserID)
+		}
+	}
+	names := make(map[string]string, len(ids))
+	if len(ids) == 0 {
+		return names
+	}
+	users, _, err := b.client.GetUsersByIds(ids)
+	if err != nil {
+		b.logger.WarnContext(ctx, "Could not get users who answered", "error", err)
+		return names
+	}
+	for _, user := range users {
+		names[user.Id] = user.Username
+	}
+	return names
+}
@@ -0,0 +1,86 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"testing"
+
+	"github.com/mattermost/mattermost-server/v6/model"
+
+	"github.com/Xausdorf/mattermost-poll/internal/domain"
+)
+
+func TestHandleSurveyAnswersIsPrivate(t *testing.T) {
+	tests := []struct {
+		name          string
+		failEphemeral bool
+		wantEphemeral int
+		wantDirect    int
+	}{
+		{name: "ephemeral post", wantEphemeral: 1},
+		{name: "direct message if ephemeral post fails", failEphemeral: true, wantDirect: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, api := newTestBot(t, &pollService{
+				survey: &domain.Survey{ID: "s1", Title: "Retro", Author: "author"},
+				textAnswers: []*domain.TextAnswer{
+					{ID: "a1", SurveyID: "s1", UserID: "alice", Text: "Not published"},
+					{ID: "a2", SurveyID: "s1", Text: "Anonymous", Published: true},
+				},
+			})
+			api.failEphemeral = tt.failEphemeral
+			post := &model.Post{Id: "post", ChannelId: "channel", UserId: "author"}
+			cmd := &Command{Prefix: "!", Args: []string{"s1", "1"}}
+
+			if got := b.handleSurveyAnswers(context.Background(), post, cmd); got != outcomeOK {
+				t.Fatalf("handleSurveyAnswers() = %v, want %v", got, outcomeOK)
+			}
+			public, ephemeral, direct := api.posts()
+			if public != 0 || ephemeral != tt.wantEphemeral || direct != tt.wantDirect {
+				t.Errorf("public posts %d, ephemeral posts %d, direct messages %d, want 0, %d, %d",
+					public, ephemeral, direct, tt.wantEphemeral, tt.wantDirect)
+			}
+		})
+	}
+}
+
+func TestTextAnswersCSV(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{text: "Plain answer", want: "Plain answer"},
+		{text: "=HYPERLINK(\"http://example.com\")", want: "'=HYPERLINK(\"http://example.com\")"},
+		{text: "+1", want: "'+1"},
+		{text: "-1+2", want: "'-1+2"},
+		{text: "@SUM(A1)", want: "'@SUM(A1)"},
+		{text: "\t=1", want: "'\t=1"},
+		{text: "\r=1", want: "'\r=1"},
+		{text: "a=1", want: "a=1"},
+		{text: "", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			answers := []*domain.TextAnswer{{ID: "a1", UserID: "u1", Text: tt.text}}
+			data, err := textAnswersCSV(answers, map[string]string{"u1": "=alice"})
+			if err != nil {
+				t.Fatalf("textAnswersCSV() error: %v", err)
+			}
+			records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+			if err != nil {
+				t.Fatalf("could not read CSV: %v", err)
+			}
+			if len(records) != 2 {
+				t.Fatalf("CSV has %d records, want header and an answer", len(records))
+			}
+			if got := records[1][4]; got != tt.want {
+				t.Errorf("text = %q, want %q", got, tt.want)
+			}
+			if got := records[1][2]; got != "'=alice" {
+				t.Errorf("user = %q, want %q", got, "'=alice")
+			}
+		})
+	}
+}
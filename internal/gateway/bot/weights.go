@@ -0,0 +1,166 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Xausdorf/mattermost-poll/internal/domain"
+	"github.com/mattermost/mattermost-server/v6/model"
+)
+
+// VoteWeights - weights of votes in polls started with --weighted. A voter gets the largest weight
+// of matching users and groups, voters who match nothing count as 1.
+type VoteWeights struct {
+	// Groups - weights of members of Mattermost groups, keyed by group name or ID.
+	Groups map[string]int
+	// Users - weights of users, keyed by username or user ID.
+	Users map[string]int
+}
+
+// Validate checks that every weight is from 1 to domain.MaxVoteWeight.
+func (w VoteWeights) Validate() error {
+	for group, weight := range w.Groups {
+		if !domain.ValidWeight(weight) {
+			return fmt.Errorf("weight of group %s must be from 1 to %d: %d", group, domain.MaxVoteWeight, weight)
+		}
+	}
+	for user, weight := range w.Users {
+		if !domain.ValidWeight(weight) {
+			return fmt.Errorf("weight of user %s must be from 1 to %d: %d", user, domain.MaxVoteWeight, weight)
+		}
+	}
+	return nil
+}
+
+// ParseVoteWeights parses weights in the form "group:maintainers=2,user:alice=3".
+func ParseVoteWeights(s string) (VoteWeights, error) {
+	weights := VoteWeights{Groups: make(map[string]int), Users: make(map[string]int)}
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		kind, rest, _ := strings.Cut(item, ":")
+		name, value, ok := strings.Cut(rest, "=")
+		name = strings.TrimSpace(name)
+		weight, err := strconv.Atoi(strings.TrimSpace(value))
+		if !ok || name == "" || err != nil {
+			return VoteWeights{}, fmt.Errorf("weight %q must be in the form group:name=weight or user:name=weight", item)
+		}
+		switch strings.TrimSpace(kind) {
+		case "group":
+			weights.Groups[name] = weight
+		case "user":
+			weights.Users[strings.TrimPrefix(name, "@")] = weight
+		default:
+			return VoteWeights{}, fmt.Errorf("weight %q must be in the form group:name=weight or user:name=weight", item)
+		}
+	}
+	if err := weights.Validate(); err != nil {
+		return VoteWeights{}, err
+	}
+	return weights, nil
+}
+
+// ParsePollWeights parses weights of a single poll in the form "alice=2,@bob=3", keyed by username.
+func ParsePollWeights(s string) (map[string]int, error) {
+	weights := make(map[string]int)
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(item, "=")
+		name = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(name), "@"))
+		weight, err := strconv.Atoi(strings.TrimSpace(value))
+		if !ok || name == "" || err != nil || !domain.ValidWeight(weight) {
+			return nil, fmt.Errorf("weight %q must be in the form username=weight", item)
+		}
+		weights[name] = weight
+	}
+	if len(weights) == 0 {
+		return nil, fmt.Errorf("no weights in %q", s)
+	}
+	return weights, nil
+}
+
+// weightResolver - current weights of votes from settings, can be changed while the bot is running.
+type weightResolver struct {
+	client *model.Client4
+
+	mu      sync.RWMutex
+	weights VoteWeights
+}
+
+func newWeightResolver(client *model.Client4, weights VoteWeights) *weightResolver {
+	return &weightResolver{client: client, weights: weights}
+}
+
+func (r *weightResolver) set(weights VoteWeights) {
+	r.mu.Lock()
+	r.weights = weights
+	r.mu.Unlock()
+}
+
+// resolve returns weight of the user's vote: the largest weight of the user and of groups the user is a member of,
+// 1 if nothing matches. Mattermost is asked only if weights of users or groups are configured.
+func (r *weightResolver) resolve(userID string) (int, error) {
+	r.mu.RLock()
+	weights := r.weights
+	r.mu.RUnlock()
+
+	weight := 1
+	if len(weights.Users) > 0 {
+		weight = max(weight, weights.Users[userID])
+		user, _, err := r.client.GetUser(userID, "")
+		if err != nil {
+			return 0, fmt.Errorf("could not get user: %w", err)
+		}
+		weight = max(weight, weights.Users[user.Username])
+	}
+	if len(weights.Groups) > 0 {
+		groups, _, err := r.client.GetGroupsByUserId(userID)
+		if err != nil {
+			return 0, fmt.Errorf("could not get groups of user: %w", err)
+		}
+		for _, group := range groups {
+			weight = max(weight, weights.Groups[group.Id])
+			if group.Name != nil {
+				weight = max(weight, weights.Groups[*group.Name])
+			}
+		}
+	}
+	return weight, nil
+}
+
+// pollWeights parses weights of a single poll and replaces usernames by user IDs, so renamed users keep their weights.
+// Responds to the post and returns the outcome if weights are not valid.
+func (b *PollingBot) pollWeights(ctx context.Context, post *model.Post, value string) (map[string]int, outcome) {
+	byName, err := ParsePollWeights(value)
+	if err != nil {
+		b.Respond(ctx, post, ResponseError, b.tr(ctx, msgInvalidWeights, domain.MaxVoteWeight))
+		return nil, outcomeRejected
+	}
+	users, _, err := b.client.GetUsersByUsernames(slices.Collect(maps.Keys(byName)))
+	if err != nil {
+		b.logger.ErrorContext(ctx, "Failed to get users of weights", "error", err)
+		b.Respond(ctx, post, ResponseError, b.tr(ctx, msgStartFailed))
+		return nil, outcomeFailed
+	}
+	weights := make(map[string]int, len(users))
+	for _, user := range users {
+		weights[user.Id] = byName[user.Username]
+	}
+	for name := range byName {
+		if !slices.ContainsFunc(users, func(user *model.User) bool { return user.Username == name }) {
+			b.Respond(ctx, post, ResponseError, b.tr(ctx, msgWeightUserNotFound, domain.SanitizeText(name)))
+			return nil, outcomeRejected
+		}
+	}
+	return weights, outcomeOK
+}
@@ -0,0 +1,74 @@
+package bot_test
+
+import (
+	"maps"
+	"testing"
+
+	"github.com/Xausdorf/mattermost-poll/internal/gateway/bot"
+)
+
+func TestParseVoteWeights(t *testing.T) {
+	tests := []struct {
+		in         string
+		wantGroups map[string]int
+		wantUsers  map[string]int
+		wantErr    bool
+	}{
+		{in: "", wantGroups: map[string]int{}, wantUsers: map[string]int{}},
+		{
+			in:         "group:maintainers=2, user:@alice=3,user:bob = 1,",
+			wantGroups: map[string]int{"maintainers": 2},
+			wantUsers:  map[string]int{"alice": 3, "bob": 1},
+		},
+		{in: "maintainers=2", wantErr: true},
+		{in: "team:maintainers=2", wantErr: true},
+		{in: "group:maintainers", wantErr: true},
+		{in: "group:=2", wantErr: true},
+		{in: "group:maintainers=x", wantErr: true},
+		{in: "group:maintainers=0", wantErr: true},
+		{in: "user:alice=101", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := bot.ParseVoteWeights(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseVoteWeights(%q) error = %v, want error %v", tt.in, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !maps.Equal(got.Groups, tt.wantGroups) || !maps.Equal(got.Users, tt.wantUsers) {
+				t.Errorf("ParseVoteWeights(%q) = %+v, want groups %v, users %v", tt.in, got, tt.wantGroups, tt.wantUsers)
+			}
+		})
+	}
+}
+
+func TestParsePollWeights(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    map[string]int
+		wantErr bool
+	}{
+		{in: "alice=2", want: map[string]int{"alice": 2}},
+		{in: " @Alice = 2 , bob=100,", want: map[string]int{"alice": 2, "bob": 100}},
+		{in: "", wantErr: true},
+		{in: ",", wantErr: true},
+		{in: "alice", wantErr: true},
+		{in: "=2", wantErr: true},
+		{in: "alice=two", wantErr: true},
+		{in: "alice=0", wantErr: true},
+		{in: "alice=101", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := bot.ParsePollWeights(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePollWeights(%q) error = %v, want error %v", tt.in, err, tt.wantErr)
+			}
+			if !tt.wantErr && !maps.Equal(got, tt.want) {
+				t.Errorf("ParsePollWeights(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
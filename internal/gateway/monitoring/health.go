@@ -0,0 +1,50 @@
+package monitoring
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+const checkTimeout = 3 * time.Second
+
+// Check - named health check of a dependency, returns nil if the dependency is fine.
+type Check struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+type healthResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
+
+func healthHandler(logger *slog.Logger, checks []Check) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), checkTimeout)
+		defer cancel()
+
+		resp := healthResponse{
+			Status: "ok",
+			Checks: make(map[string]string, len(checks)),
+		}
+		status := http.StatusOK
+		for _, check := range checks {
+			if err := check.Check(ctx); err != nil {
+				resp.Checks[check.Name] = err.Error()
+				resp.Status = "fail"
+				status = http.StatusServiceUnavailable
+				continue
+			}
+			resp.Checks[check.Name] = "ok"
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			logger.ErrorContext(r.Context(), "Could not write health response", "error", err)
+		}
+	})
+}
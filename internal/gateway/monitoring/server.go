@@ -0,0 +1,54 @@
+package monitoring
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+const readHeaderTimeout = 5 * time.Second
+
+// Server - HTTP server of operational endpoints, such as /metrics.
+type Server struct {
+	srv    *http.Server
+	mux    *http.ServeMux
+	logger *slog.Logger
+}
+
+func NewServer(addr string, logger *slog.Logger) *Server {
+	mux := http.NewServeMux()
+	return &Server{
+		srv: &http.Server{
+			Addr:              addr,
+			Handler:           mux,
+			ReadHeaderTimeout: readHeaderTimeout,
+		},
+		mux:    mux,
+		logger: logger,
+	}
+}
+
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+// Start serves requests in background.
+func (s *Server) Start() {
+	go func() {
+		s.logger.Info("Monitoring server listening", "addr", s.srv.Addr)
+		if err := s.srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.logger.Error("Monitoring server stopped", "error", err)
+		}
+	}()
+}
+
+// HealthHandler runs all checks on every request and responds 200 if all of them passed, 503 otherwise.
+func (s *Server) HealthHandler(checks ...Check) http.Handler {
+	return healthHandler(s.logger, checks)
+}
+
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.srv.Shutdown(ctx)
+}
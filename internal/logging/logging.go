@@ -0,0 +1,87 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const (
+	CorrelationIDKey = "correlation_id"
+	redacted         = "[REDACTED]"
+)
+
+// sensitiveKeys - attributes which are never written to logs: message bodies and credentials.
+func sensitiveKeys() map[string]struct{} {
+	return map[string]struct{}{
+		"message":       {},
+		"text":          {},
+		"token":         {},
+		"auth_token":    {},
+		"password":      {},
+		"authorization": {},
+	}
+}
+
+// New returns JSON logger, which adds correlation ID from context to every record and redacts sensitive attributes.
+func New(w io.Writer, level slog.Leveler) *slog.Logger {
+	sensitive := sensitiveKeys()
+	handler := slog.NewJSONHandler(w, &slog.HandlerOptions{
+		Level: level,
+		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+			if _, ok := sensitive[strings.ToLower(a.Key)]; ok {
+				return slog.String(a.Key, redacted)
+			}
+			return a
+		},
+	})
+	return slog.New(&contextHandler{Handler: handler})
+}
+
+// ParseLevel parses level name: debug, info, warn or error.
+func ParseLevel(s string) (slog.Level, error) {
+	var level slog.Level
+	if s == "" {
+		return slog.LevelInfo, nil
+	}
+	if err := level.UnmarshalText([]byte(s)); err != nil {
+		return level, fmt.Errorf("invalid log level %q: %w", s, err)
+	}
+	return level, nil
+}
+
+type correlationIDKey struct{}
+
+// WithCorrelationID returns context with a new correlation ID, which is logged by every call using this context.
+func WithCorrelationID(ctx context.Context) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, uuid.NewString())
+}
+
+// CorrelationID returns correlation ID of the context or empty string.
+func CorrelationID(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDKey{}).(string)
+	return id
+}
+
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h *contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := CorrelationID(ctx); id != "" {
+		r.AddAttrs(slog.String(CorrelationIDKey, id))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,92 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "pollingbot"
+
+// Metrics - Prometheus collectors of the bot, usecases and repositories.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	commands            *prometheus.CounterVec
+	votes               prometheus.Counter
+	usecaseErrors       *prometheus.CounterVec
+	tarantoolDuration   *prometheus.HistogramVec
+	websocketReconnects prometheus.Counter
+	eventQueueDepth     prometheus.Gauge
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		commands: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "commands_total",
+			Help:      "Count of handled bot commands by command and outcome.",
+		}, []string{"command", "outcome"}),
+		votes: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "votes_total",
+			Help:      "Count of registered votes.",
+		}),
+		usecaseErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "usecase_errors_total",
+			Help:      "Count of errors returned by poll usecases by method and error.",
+		}, []string{"method", "error"}),
+		tarantoolDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "tarantool_request_duration_seconds",
+			Help:      "Latency of Tarantool requests by request type and status.",
+			Buckets:   prometheus.ExponentialBuckets(0.0005, 2, 14),
+		}, []string{"request", "status"}),
+		websocketReconnects: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "websocket_reconnects_total",
+			Help:      "Count of reconnections of Mattermost websocket.",
+		}),
+		eventQueueDepth: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "websocket_event_queue_depth",
+			Help:      "Count of websocket events waiting to be handled.",
+		}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.commands,
+		m.votes,
+		m.usecaseErrors,
+		m.tarantoolDuration,
+		m.websocketReconnects,
+		m.eventQueueDepth,
+	)
+	return m
+}
+
+// Handler returns HTTP handler of /metrics endpoint.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// CommandHandled implements bot.Observer.
+func (m *Metrics) CommandHandled(command string, outcome string) {
+	m.commands.WithLabelValues(command, outcome).Inc()
+}
+
+// WebSocketReconnected implements bot.Observer.
+func (m *Metrics) WebSocketReconnected() {
+	m.websocketReconnects.Inc()
+}
+
+// EventQueueDepth implements bot.Observer.
+func (m *Metrics) EventQueueDepth(depth int) {
+	m.eventQueueDepth.Set(float64(depth))
+}
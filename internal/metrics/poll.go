@@ -0,0 +1,276 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Xausdorf/mattermost-poll/internal/domain"
+	"github.com/Xausdorf/mattermost-poll/internal/usecase"
+)
+
+// PollService - poll usecases which count votes and errors.
+type PollService struct {
+	poll    *usecase.Poll
+	metrics *Metrics
+}
+
+// InstrumentPollService wraps poll usecases, the bot should use the result instead of usecases.
+func (m *Metrics) InstrumentPollService(poll *usecase.Poll) *PollService {
+	return &PollService{
+		poll:    poll,
+		metrics: m,
+	}
+}
+
+func (s *PollService) CreatePoll(ctx context.Context, poll *domain.Poll) error {
+	err := s.poll.CreatePoll(ctx, poll)
+	s.observe("create_poll", err)
+	return err
+}
+
+func (s *PollService) AddAnswer(ctx context.Context, answer *domain.Answer) error {
+	err := s.poll.AddAnswer(ctx, answer)
+	s.observe("add_answer", err)
+	if err == nil {
+		s.metrics.votes.Inc()
+	}
+	return err
+}
+
+func (s *PollService) GetPollByID(ctx context.Context, id string) (*domain.Poll, error) {
+	poll, err := s.poll.GetPollByID(ctx, id)
+	s.observe("get_poll", err)
+	return poll, err
+}
+
+func (s *PollService) GetResults(ctx context.Context, id string, userID string) (*usecase.PollResults, error) {
+	results, err := s.poll.GetResults(ctx, id, userID)
+	s.observe("get_results", err)
+	return results, err
+}
+
+func (s *PollService) ClosePollByID(
+	ctx context.Context, id string, senderID string, opts usecase.CloseOptions,
+) (*usecase.PollResults, error) {
+	closed, err := s.poll.ClosePollByID(ctx, id, senderID, opts)
+	s.observe("close_poll", err)
+	return closed, err
+}
+
+func (s *PollService) ExpiredPolls(ctx context.Context, now time.Time) ([]*domain.Poll, error) {
+	polls, err := s.poll.ExpiredPolls(ctx, now)
+	s.observe("expired_polls", err)
+	return polls, err
+}
+
+func (s *PollService) ExpirePoll(
+	ctx context.Context, id string, now time.Time, members int,
+) (*usecase.PollResults, error) {
+	closed, err := s.poll.ExpirePoll(ctx, id, now, members)
+	s.observe("expire_poll", err)
+	return closed, err
+}
+
+func (s *PollService) ReopenPoll(ctx context.Context, id string, sender usecase.Sender) error {
+	err := s.poll.ReopenPoll(ctx, id, sender)
+	s.observe("reopen_poll", err)
+	return err
+}
+
+func (s *PollService) ExtendPoll(ctx context.Context, id string, sender usecase.Sender, d time.Duration) (*domain.Poll, error) {
+	poll, err := s.poll.ExtendPoll(ctx, id, sender, d)
+	s.observe("extend_poll", err)
+	return poll, err
+}
+
+func (s *PollService) AddOption(
+	ctx context.Context, id string, sender usecase.Sender, text string,
+) (*domain.PollOption, error) {
+	option, err := s.poll.AddOption(ctx, id, sender, text)
+	s.observe("add_option", err)
+	return option, err
+}
+
+func (s *PollService) EditOption(ctx context.Context, id string, sender usecase.Sender, optionID int, text string) error {
+	err := s.poll.EditOption(ctx, id, sender, optionID, text)
+	s.observe("edit_option", err)
+	return err
+}
+
+func (s *PollService) RemoveOption(
+	ctx context.Context, id string, sender usecase.Sender, optionID int, opts usecase.RemoveOptionOptions,
+) (int, error) {
+	votes, err := s.poll.RemoveOption(ctx, id, sender, optionID, opts)
+	s.observe("remove_option", err)
+	return votes, err
+}
+
+func (s *PollService) SuggestOption(
+	ctx context.Context, pollID string, userID string, text string,
+) (*domain.Suggestion, error) {
+	suggestion, err := s.poll.SuggestOption(ctx, pollID, userID, text)
+	s.observe("suggest_option", err)
+	return suggestion, err
+}
+
+func (s *PollService) PendingSuggestions(
+	ctx context.Context, pollID string, sender usecase.Sender,
+) ([]*domain.Suggestion, error) {
+	suggestions, err := s.poll.PendingSuggestions(ctx, pollID, sender)
+	s.observe("pending_suggestions", err)
+	return suggestions, err
+}
+
+func (s *PollService) ReviewSuggestion(
+	ctx context.Context, id string, sender usecase.Sender, approve bool,
+) (*domain.Suggestion, error) {
+	suggestion, err := s.poll.ReviewSuggestion(ctx, id, sender, approve)
+	s.observe("review_suggestion", err)
+	return suggestion, err
+}
+
+func (s *PollService) CreateSurvey(ctx context.Context, survey *domain.Survey) error {
+	err := s.poll.CreateSurvey(ctx, survey)
+	s.observe("create_survey", err)
+	return err
+}
+
+func (s *PollService) TakeSurvey(
+	ctx context.Context, id string, userID string,
+) (*domain.Survey, *domain.SurveyResponse, error) {
+	survey, response, err := s.poll.TakeSurvey(ctx, id, userID)
+	s.observe("take_survey", err)
+	return survey, response, err
+}
+
+func (s *PollService) AnswerSurvey(
+	ctx context.Context, userID string, input string,
+) (*domain.Survey, *domain.SurveyResponse, error) {
+	survey, response, err := s.poll.AnswerSurvey(ctx, userID, input)
+	s.observe("answer_survey", err)
+	return survey, response, err
+}
+
+func (s *PollService) SurveyInProgress(
+	ctx context.Context, userID string,
+) (*domain.Survey, *domain.SurveyResponse, error) {
+	survey, response, err := s.poll.SurveyInProgress(ctx, userID)
+	s.observe("survey_in_progress", err)
+	return survey, response, err
+}
+
+func (s *PollService) SurveyResults(
+	ctx context.Context, id string, sender usecase.Sender,
+) (*domain.SurveyResults, error) {
+	results, err := s.poll.SurveyResults(ctx, id, sender)
+	s.observe("survey_results", err)
+	return results, err
+}
+
+func (s *PollService) CloseSurvey(ctx context.Context, id string, sender usecase.Sender) error {
+	err := s.poll.CloseSurvey(ctx, id, sender)
+	s.observe("close_survey", err)
+	return err
+}
+
+func (s *PollService) TextAnswers(
+	ctx context.Context, surveyID string, questionID int, sender usecase.Sender,
+) (*domain.Survey, []*domain.TextAnswer, error) {
+	survey, answers, err := s.poll.TextAnswers(ctx, surveyID, questionID, sender)
+	s.observe("text_answers", err)
+	return survey, answers, err
+}
+
+func (s *PollService) PublishTextAnswers(
+	ctx context.Context, surveyID string, sender usecase.Sender, ids []string,
+) (*domain.Survey, []*domain.TextAnswer, error) {
+	survey, answers, err := s.poll.PublishTextAnswers(ctx, surveyID, sender, ids)
+	s.observe("publish_text_answers", err)
+	return survey, answers, err
+}
+
+func (s *PollService) DeletePollByID(ctx context.Context, id string, senderID string) error {
+	err := s.poll.DeletePollByID(ctx, id, senderID)
+	s.observe("delete_poll", err)
+	return err
+}
+
+func (s *PollService) RemindPoll(
+	ctx context.Context, id string, senderID string, listMembers func(ctx context.Context) ([]string, error),
+) ([]string, error) {
+	users, err := s.poll.RemindPoll(ctx, id, senderID, listMembers)
+	s.observe("remind_poll", err)
+	return users, err
+}
+
+func (s *PollService) PollsToRemind(ctx context.Context, now time.Time) ([]*domain.Poll, error) {
+	polls, err := s.poll.PollsToRemind(ctx, now)
+	s.observe("polls_to_remind", err)
+	return polls, err
+}
+
+func (s *PollService) SetDoNotDisturb(ctx context.Context, userID string, enabled bool) error {
+	err := s.poll.SetDoNotDisturb(ctx, userID, enabled)
+	s.observe("set_do_not_disturb", err)
+	return err
+}
+
+func (s *PollService) observe(method string, err error) {
+	if err != nil {
+		s.metrics.usecaseErrors.WithLabelValues(method, errorLabel(err)).Inc()
+	}
+}
+
+// errorLabel returns a label of sentinel error wrapped by err, so the label has bounded cardinality.
+func errorLabel(err error) string {
+	labels := []struct {
+		err   error
+		label string
+	}{
+		{usecase.ErrInvalidUserID, "invalid_user_id"},
+		{usecase.ErrUserIsNotPollAuthor, "user_is_not_poll_author"},
+		{usecase.ErrPollNotFound, "poll_not_found"},
+		{usecase.ErrPollIsNotActive, "poll_is_not_active"},
+		{usecase.ErrNoSuchOption, "no_such_option"},
+		{usecase.ErrAnswerNotFound, "answer_not_found"},
+		{usecase.ErrAnswerAlreadyExists, "answer_already_exists"},
+		{usecase.ErrTooManyActivePolls, "too_many_active_polls"},
+		{usecase.ErrQuorumNotReached, "quorum_not_reached"},
+		{usecase.ErrResultsHidden, "results_hidden"},
+		{usecase.ErrPollDeleted, "poll_deleted"},
+		{usecase.ErrPollIsActive, "poll_is_active"},
+		{usecase.ErrPollHasNoDeadline, "poll_has_no_deadline"},
+		{usecase.ErrInvalidDuration, "invalid_duration"},
+		{usecase.ErrSuggestionsDisabled, "suggestions_disabled"},
+		{usecase.ErrTooManySuggestions, "too_many_suggestions"},
+		{usecase.ErrSuggestionExists, "suggestion_exists"},
+		{usecase.ErrSuggestionNotFound, "suggestion_not_found"},
+		{usecase.ErrSuggestionReviewed, "suggestion_reviewed"},
+		{usecase.ErrSurveyNotFound, "survey_not_found"},
+		{usecase.ErrSurveyIsNotActive, "survey_is_not_active"},
+		{usecase.ErrSurveyCompleted, "survey_completed"},
+		{usecase.ErrSurveyResponseNotFound, "survey_response_not_found"},
+		{usecase.ErrSurveyQuestionNotFound, "survey_question_not_found"},
+		{usecase.ErrNotTextQuestion, "not_text_question"},
+		{usecase.ErrTextAnswerNotFound, "text_answer_not_found"},
+	}
+	for _, l := range labels {
+		if errors.Is(err, l.err) {
+			return l.label
+		}
+	}
+
+	if errors.Is(err, domain.ErrInvalidScore) || errors.Is(err, domain.ErrCannotMoveScores) {
+		return "invalid_score"
+	}
+	if errors.Is(err, domain.ErrInvalidAnswer) || errors.Is(err, domain.ErrAnswerTooLong) {
+		return "invalid_answer"
+	}
+
+	var validationErr *domain.ValidationError
+	if errors.As(err, &validationErr) {
+		return "validation"
+	}
+	return "internal"
+}
@@ -0,0 +1,39 @@
+package metrics
+
+import (
+	"strings"
+	"time"
+
+	"github.com/tarantool/go-tarantool/v2"
+)
+
+// Doer - tarantool.Doer which measures latency of requests.
+type Doer struct {
+	doer    tarantool.Doer
+	metrics *Metrics
+}
+
+// InstrumentDoer wraps the doer, repositories should use the result instead of the connection.
+func (m *Metrics) InstrumentDoer(doer tarantool.Doer) *Doer {
+	return &Doer{
+		doer:    doer,
+		metrics: m,
+	}
+}
+
+func (d *Doer) Do(req tarantool.Request) *tarantool.Future {
+	start := time.Now()
+	fut := d.doer.Do(req)
+
+	go func() {
+		<-fut.WaitChan()
+		status := "ok"
+		if _, err := fut.GetResponse(); err != nil {
+			status = "error"
+		}
+		request := strings.ToLower(strings.TrimPrefix(req.Type().String(), "IPROTO_"))
+		d.metrics.tarantoolDuration.WithLabelValues(request, status).Observe(time.Since(start).Seconds())
+	}()
+
+	return fut
+}
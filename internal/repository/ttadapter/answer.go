@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 
 	"github.com/Xausdorf/mattermost-poll/internal/domain"
 	"github.com/Xausdorf/mattermost-poll/internal/usecase"
@@ -12,15 +13,19 @@ import (
 
 const (
 	answerSpace = "answers"
+	// answerOptionIDField - number of OptionID field in tuples of answers, starting from 0.
+	answerOptionIDField = 3
 )
 
 type AnswerRepository struct {
-	conn *tarantool.Connection
+	conn   tarantool.Doer
+	logger *slog.Logger
 }
 
-func NewAnswerRepository(conn *tarantool.Connection) *AnswerRepository {
+func NewAnswerRepository(conn tarantool.Doer, logger *slog.Logger) *AnswerRepository {
 	return &AnswerRepository{
-		conn: conn,
+		conn:   conn,
+		logger: logger,
 	}
 }
 
@@ -31,6 +36,7 @@ func (r *AnswerRepository) Save(ctx context.Context, answer *domain.Answer) erro
 	} else if !errors.Is(err, usecase.ErrAnswerNotFound) {
 		return err
 	}
+	r.logger.DebugContext(ctx, "Inserting answer", "space", answerSpace, "poll_id", answer.PollID, "user_id", answer.UserID)
 	_, err = r.conn.Do(
 		tarantool.NewInsertRequest(answerSpace).
 			Context(ctx).
@@ -40,6 +46,7 @@ func (r *AnswerRepository) Save(ctx context.Context, answer *domain.Answer) erro
 }
 
 func (r *AnswerRepository) GetByUserAndPoll(ctx context.Context, userID string, pollID string) (*domain.Answer, error) {
+	r.logger.DebugContext(ctx, "Selecting answer", "space", answerSpace, "poll_id", pollID, "user_id", userID)
 	var res []AnswerModel
 	if err := r.conn.Do(
 		tarantool.NewSelectRequest(answerSpace).
@@ -56,6 +63,83 @@ func (r *AnswerRepository) GetByUserAndPoll(ctx context.Context, userID string,
 	return res[0].ToAnswer(), nil
 }
 
+func (r *AnswerRepository) ListByPoll(ctx context.Context, pollID string) ([]*domain.Answer, error) {
+	res, err := r.selectByPoll(ctx, pollID)
+	if err != nil {
+		return nil, err
+	}
+	answers := make([]*domain.Answer, len(res))
+	for i := range res {
+		answers[i] = res[i].ToAnswer()
+	}
+	return answers, nil
+}
+
+// DeleteByOption deletes answers for the option, so their users can vote again.
+func (r *AnswerRepository) DeleteByOption(ctx context.Context, pollID string, optionID int) (int, error) {
+	res, err := r.selectByPoll(ctx, pollID)
+	if err != nil {
+		return 0, err
+	}
+	deleted := 0
+	for _, answer := range res {
+		if answer.OptionID != optionID {
+			continue
+		}
+		r.logger.DebugContext(ctx, "Deleting answer", "space", answerSpace, "poll_id", pollID, "user_id", answer.UserID)
+		if _, err = r.conn.Do(
+			tarantool.NewDeleteRequest(answerSpace).
+				Context(ctx).
+				Index("primary").
+				Key(tarantool.StringKey{S: answer.ID}),
+		).Get(); err != nil {
+			return deleted, fmt.Errorf("could not delete answer in tarantool: %w", err)
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+// MoveToOption changes the chosen option of answers for the option from to the option to.
+func (r *AnswerRepository) MoveToOption(ctx context.Context, pollID string, from int, to int) (int, error) {
+	res, err := r.selectByPoll(ctx, pollID)
+	if err != nil {
+		return 0, err
+	}
+	moved := 0
+	for _, answer := range res {
+		if answer.OptionID != from {
+			continue
+		}
+		r.logger.DebugContext(ctx, "Updating answer", "space", answerSpace, "poll_id", pollID, "user_id", answer.UserID)
+		if _, err = r.conn.Do(
+			tarantool.NewUpdateRequest(answerSpace).
+				Context(ctx).
+				Index("primary").
+				Key(tarantool.StringKey{S: answer.ID}).
+				Operations(tarantool.NewOperations().Assign(answerOptionIDField, to)),
+		).Get(); err != nil {
+			return moved, fmt.Errorf("could not update answer in tarantool: %w", err)
+		}
+		moved++
+	}
+	return moved, nil
+}
+
+func (r *AnswerRepository) selectByPoll(ctx context.Context, pollID string) ([]AnswerModel, error) {
+	r.logger.DebugContext(ctx, "Selecting answers of poll", "space", answerSpace, "poll_id", pollID)
+	var res []AnswerModel
+	if err := r.conn.Do(
+		tarantool.NewSelectRequest(answerSpace).
+			Context(ctx).
+			Index("poll").
+			Key(tarantool.StringKey{S: pollID}),
+	).GetTyped(&res); err != nil {
+		return nil, fmt.Errorf("could not select typed answers in tarantool: %w", err)
+	}
+	return res, nil
+}
+
 func (r *AnswerRepository) DeleteByPoll(context.Context, string) error {
 	// Do nothing, tarantool does not allow delete by a non-unique key
 	return nil
@@ -0,0 +1,67 @@
+package ttadapter
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/tarantool/go-tarantool/v2"
+)
+
+const (
+	doNotDisturbSpace = "do_not_disturb"
+)
+
+// DoNotDisturbRepository - users who opted out of reminders.
+type DoNotDisturbRepository struct {
+	conn   tarantool.Doer
+	logger *slog.Logger
+}
+
+func NewDoNotDisturbRepository(conn tarantool.Doer, logger *slog.Logger) *DoNotDisturbRepository {
+	return &DoNotDisturbRepository{
+		conn:   conn,
+		logger: logger,
+	}
+}
+
+func (r *DoNotDisturbRepository) Add(ctx context.Context, userID string) error {
+	r.logger.DebugContext(ctx, "Replacing user", "space", doNotDisturbSpace, "user_id", userID)
+	_, err := r.conn.Do(
+		tarantool.NewReplaceRequest(doNotDisturbSpace).
+			Context(ctx).
+			Tuple([]interface{}{userID}),
+	).Get()
+	return err
+}
+
+func (r *DoNotDisturbRepository) Remove(ctx context.Context, userID string) error {
+	r.logger.DebugContext(ctx, "Deleting user", "space", doNotDisturbSpace, "user_id", userID)
+	_, err := r.conn.Do(
+		tarantool.NewDeleteRequest(doNotDisturbSpace).
+			Context(ctx).
+			Index("primary").
+			Key(tarantool.StringKey{S: userID}),
+	).Get()
+	return err
+}
+
+func (r *DoNotDisturbRepository) List(ctx context.Context) ([]string, error) {
+	r.logger.DebugContext(ctx, "Selecting users", "space", doNotDisturbSpace)
+	var res [][]string
+	if err := r.conn.Do(
+		tarantool.NewSelectRequest(doNotDisturbSpace).
+			Context(ctx).
+			Index("primary").
+			Iterator(tarantool.IterAll),
+	).GetTyped(&res); err != nil {
+		return nil, fmt.Errorf("could not select typed users in tarantool: %w", err)
+	}
+	users := make([]string, 0, len(res))
+	for _, tuple := range res {
+		if len(tuple) > 0 {
+			users = append(users, tuple[0])
+		}
+	}
+	return users, nil
+}
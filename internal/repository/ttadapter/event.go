@@ -0,0 +1,55 @@
+package ttadapter
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/Xausdorf/mattermost-poll/internal/domain"
+	"github.com/tarantool/go-tarantool/v2"
+)
+
+const (
+	eventSpace = "poll_events"
+)
+
+// EventRepository - lifecycle events of polls, kept after polls are deleted.
+type EventRepository struct {
+	conn   tarantool.Doer
+	logger *slog.Logger
+}
+
+func NewEventRepository(conn tarantool.Doer, logger *slog.Logger) *EventRepository {
+	return &EventRepository{
+		conn:   conn,
+		logger: logger,
+	}
+}
+
+func (r *EventRepository) Save(ctx context.Context, event *domain.PollEvent) error {
+	r.logger.DebugContext(ctx, "Inserting event", "space", eventSpace, "poll_id", event.PollID, "type", event.Type)
+	_, err := r.conn.Do(
+		tarantool.NewInsertRequest(eventSpace).
+			Context(ctx).
+			Tuple(NewEventModel(event)),
+	).Get()
+	return err
+}
+
+func (r *EventRepository) ListByPoll(ctx context.Context, pollID string) ([]*domain.PollEvent, error) {
+	r.logger.DebugContext(ctx, "Selecting events of poll", "space", eventSpace, "poll_id", pollID)
+	var res []EventModel
+	if err := r.conn.Do(
+		tarantool.NewSelectRequest(eventSpace).
+			Context(ctx).
+			Index("poll").
+			Key(tarantool.StringKey{S: pollID}),
+	).GetTyped(&res); err != nil {
+		return nil, fmt.Errorf("could not select typed events in tarantool: %w", err)
+	}
+	events := make([]*domain.PollEvent, len(res))
+	for i := range res {
+		events[i] = res[i].ToEvent()
+	}
+	return events, nil
+}
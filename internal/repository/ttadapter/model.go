@@ -2,6 +2,7 @@ package ttadapter
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/Xausdorf/mattermost-poll/internal/domain"
 	"github.com/google/uuid"
@@ -9,42 +10,166 @@ import (
 )
 
 type PollModel struct {
+	ID           string
+	Question     string
+	Options      []domain.PollOption
+	IsActive     bool
+	Author       string
+	TeamID       string
+	ChannelID    string
+	NotifyAuthor bool
+	// RemindEvery - interval of reminders in seconds.
+	RemindEvery int64
+	// RemindedAt - unix time in seconds, 0 if not set.
+	RemindedAt    int64
+	QuorumCount   int64
+	QuorumPercent int64
+	// ClosesAt - unix time in seconds, 0 if not set.
+	ClosesAt          int64
+	QuorumNotReached  bool
+	ResultsVisibility string
+	NextOptionID      int64
+	Suggestions       string
+	Type              string
+	ScaleMin          int64
+	ScaleMax          int64
+	Weighted          bool
+	// Weights - weights of voters keyed by user ID, set for this poll only.
+	Weights map[string]int
+}
+
+type SuggestionModel struct {
+	ID       string
+	PollID   string
+	UserID   string
+	Text     string
+	Status   string
+	OptionID int64
+}
+
+type SurveyModel struct {
+	ID        string
+	Title     string
+	Questions []domain.SurveyQuestion
+	IsActive  bool
+	Author    string
+	TeamID    string
+	ChannelID string
+}
+
+type SurveyResponseModel struct {
 	ID       string
-	Question string
-	Options  []domain.PollOption
-	IsActive bool
-	Author   string
+	SurveyID string
+	UserID   string
+	// Answers - answers keyed by question ID.
+	Answers   map[int]domain.SurveyAnswer
+	Completed bool
+	// UpdatedAt - unix time in seconds.
+	UpdatedAt int64
+}
+
+type TextAnswerModel struct {
+	ID         string
+	SurveyID   string
+	QuestionID int64
+	UserID     string
+	Text       string
+	// CreatedAt - unix time in seconds.
+	CreatedAt int64
+	Published bool
 }
 
 type AnswerModel struct {
+	ID       string
+	UserID   string
+	PollID   string
+	OptionID int
+	// Scores - scores of a rating poll keyed by option ID, missing in answers saved before rating polls.
+	Scores map[int]int
+	// Weight - weight of the vote, missing in answers saved before weighted votes.
+	Weight int
+}
+
+type EventModel struct {
 	ID     string
-	UserID string
 	PollID string
-	Vote   int
+	Type   string
+	UserID string
+	// At - unix time in seconds.
+	At      int64
+	Details string
 }
 
 const (
-	pollModelFields   = 5
-	answerModelFields = 4
+	pollModelFields = 22
+	// pollModelRequiredFields - fields of the first version of polls, the rest were added later
+	// and may be missing in old tuples.
+	pollModelRequiredFields = 5
+	answerModelFields       = 6
+	// answerModelRequiredFields - fields of answers saved before rating polls, the rest were added later.
+	answerModelRequiredFields = 4
+	eventModelFields          = 6
+	suggestionModelFields     = 6
+	surveyModelFields         = 7
+	surveyResponseModelFields = 6
+	textAnswerModelFields     = 7
 )
 
 func NewPollModel(poll *domain.Poll) *PollModel {
 	return &PollModel{
-		ID:       poll.ID,
-		Question: poll.Question,
-		Options:  poll.Options,
-		IsActive: poll.IsActive,
-		Author:   poll.Author,
+		ID:                poll.ID,
+		Question:          poll.Question,
+		Options:           poll.Options,
+		IsActive:          poll.IsActive,
+		Author:            poll.Author,
+		TeamID:            poll.TeamID,
+		ChannelID:         poll.ChannelID,
+		NotifyAuthor:      poll.NotifyAuthor,
+		RemindEvery:       int64(poll.RemindEvery / time.Second),
+		RemindedAt:        unixOrZero(poll.RemindedAt),
+		QuorumCount:       int64(poll.Quorum.Count),
+		QuorumPercent:     int64(poll.Quorum.Percent),
+		ClosesAt:          unixOrZero(poll.ClosesAt),
+		QuorumNotReached:  poll.QuorumNotReached,
+		ResultsVisibility: string(poll.ResultsVisibility),
+		NextOptionID:      int64(poll.NextOptionID),
+		Suggestions:       string(poll.Suggestions),
+		Type:              string(poll.Type),
+		ScaleMin:          int64(poll.Scale.Min),
+		ScaleMax:          int64(poll.Scale.Max),
+		Weighted:          poll.Weighted,
+		Weights:           poll.Weights,
 	}
 }
 
 func (p *PollModel) ToPoll() *domain.Poll {
 	return &domain.Poll{
-		ID:       p.ID,
-		Question: p.Question,
-		Options:  p.Options,
-		IsActive: p.IsActive,
-		Author:   p.Author,
+		ID:           p.ID,
+		Question:     p.Question,
+		Options:      p.Options,
+		IsActive:     p.IsActive,
+		Author:       p.Author,
+		TeamID:       p.TeamID,
+		ChannelID:    p.ChannelID,
+		NotifyAuthor: p.NotifyAuthor,
+		RemindEvery:  time.Duration(p.RemindEvery) * time.Second,
+		RemindedAt:   timeOrZero(p.RemindedAt),
+		Quorum: domain.Quorum{
+			Count:   int(p.QuorumCount),
+			Percent: int(p.QuorumPercent),
+		},
+		ClosesAt:          timeOrZero(p.ClosesAt),
+		QuorumNotReached:  p.QuorumNotReached,
+		ResultsVisibility: domain.ResultsVisibility(p.ResultsVisibility),
+		NextOptionID:      int(p.NextOptionID),
+		Suggestions:       domain.SuggestionMode(p.Suggestions),
+		Type:              domain.PollType(p.Type),
+		Scale: domain.Scale{
+			Min: int(p.ScaleMin),
+			Max: int(p.ScaleMax),
+		},
+		Weighted: p.Weighted,
+		Weights:  p.Weights,
 	}
 }
 
@@ -67,6 +192,57 @@ func (p *PollModel) EncodeMsgpack(e *msgpack.Encoder) error {
 	if err := e.EncodeString(p.Author); err != nil {
 		return err
 	}
+	if err := e.EncodeString(p.TeamID); err != nil {
+		return err
+	}
+	if err := e.EncodeString(p.ChannelID); err != nil {
+		return err
+	}
+	if err := e.EncodeBool(p.NotifyAuthor); err != nil {
+		return err
+	}
+	if err := e.EncodeInt(p.RemindEvery); err != nil {
+		return err
+	}
+	if err := e.EncodeInt(p.RemindedAt); err != nil {
+		return err
+	}
+	if err := e.EncodeInt(p.QuorumCount); err != nil {
+		return err
+	}
+	if err := e.EncodeInt(p.QuorumPercent); err != nil {
+		return err
+	}
+	if err := e.EncodeInt(p.ClosesAt); err != nil {
+		return err
+	}
+	if err := e.EncodeBool(p.QuorumNotReached); err != nil {
+		return err
+	}
+	if err := e.EncodeString(p.ResultsVisibility); err != nil {
+		return err
+	}
+	if err := e.EncodeInt(p.NextOptionID); err != nil {
+		return err
+	}
+	if err := e.EncodeString(p.Suggestions); err != nil {
+		return err
+	}
+	if err := e.EncodeString(p.Type); err != nil {
+		return err
+	}
+	if err := e.EncodeInt(p.ScaleMin); err != nil {
+		return err
+	}
+	if err := e.EncodeInt(p.ScaleMax); err != nil {
+		return err
+	}
+	if err := e.EncodeBool(p.Weighted); err != nil {
+		return err
+	}
+	if err := e.Encode(p.Weights); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -76,9 +252,10 @@ func (p *PollModel) DecodeMsgpack(d *msgpack.Decoder) error {
 	if l, err = d.DecodeArrayLen(); err != nil {
 		return err
 	}
-	if l != pollModelFields {
+	if l < pollModelRequiredFields || l > pollModelFields {
 		return fmt.Errorf("array len doesn't match: %d", l)
 	}
+	fields := l
 	if p.ID, err = d.DecodeString(); err != nil {
 		return err
 	}
@@ -100,23 +277,67 @@ func (p *PollModel) DecodeMsgpack(d *msgpack.Decoder) error {
 	if p.Author, err = d.DecodeString(); err != nil {
 		return err
 	}
+
+	// optional fields in the order they were added, missing ones keep zero values
+	optional := []func() error{
+		func() (err error) { p.TeamID, err = d.DecodeString(); return err },
+		func() (err error) { p.ChannelID, err = d.DecodeString(); return err },
+		func() (err error) { p.NotifyAuthor, err = d.DecodeBool(); return err },
+		func() (err error) { p.RemindEvery, err = d.DecodeInt64(); return err },
+		func() (err error) { p.RemindedAt, err = d.DecodeInt64(); return err },
+		func() (err error) { p.QuorumCount, err = d.DecodeInt64(); return err },
+		func() (err error) { p.QuorumPercent, err = d.DecodeInt64(); return err },
+		func() (err error) { p.ClosesAt, err = d.DecodeInt64(); return err },
+		func() (err error) { p.QuorumNotReached, err = d.DecodeBool(); return err },
+		func() (err error) { p.ResultsVisibility, err = d.DecodeString(); return err },
+		func() (err error) { p.NextOptionID, err = d.DecodeInt64(); return err },
+		func() (err error) { p.Suggestions, err = d.DecodeString(); return err },
+		func() (err error) { p.Type, err = d.DecodeString(); return err },
+		func() (err error) { p.ScaleMin, err = d.DecodeInt64(); return err },
+		func() (err error) { p.ScaleMax, err = d.DecodeInt64(); return err },
+		func() (err error) { p.Weighted, err = d.DecodeBool(); return err },
+		func() error { return d.Decode(&p.Weights) },
+	}
+	for _, decode := range optional[:fields-pollModelRequiredFields] {
+		if err = decode(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+func unixOrZero(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
+func timeOrZero(unix int64) time.Time {
+	if unix == 0 {
+		return time.Time{}
+	}
+	return time.Unix(unix, 0)
+}
+
 func NewAnswerModel(answer *domain.Answer) *AnswerModel {
 	return &AnswerModel{
-		ID:     uuid.NewString(),
-		UserID: answer.UserID,
-		PollID: answer.PollID,
-		Vote:   answer.Vote,
+		ID:       uuid.NewString(),
+		UserID:   answer.UserID,
+		PollID:   answer.PollID,
+		OptionID: answer.OptionID,
+		Scores:   answer.Scores,
+		Weight:   answer.Weight,
 	}
 }
 
 func (a *AnswerModel) ToAnswer() *domain.Answer {
 	return &domain.Answer{
-		UserID: a.UserID,
-		PollID: a.PollID,
-		Vote:   a.Vote,
+		UserID:   a.UserID,
+		PollID:   a.PollID,
+		OptionID: a.OptionID,
+		Scores:   a.Scores,
+		Weight:   a.Weight,
 	}
 }
 
@@ -133,7 +354,13 @@ func (a *AnswerModel) EncodeMsgpack(e *msgpack.Encoder) error {
 	if err := e.EncodeString(a.PollID); err != nil {
 		return err
 	}
-	if err := e.EncodeInt(int64(a.Vote)); err != nil {
+	if err := e.EncodeInt(int64(a.OptionID)); err != nil {
+		return err
+	}
+	if err := e.Encode(a.Scores); err != nil {
+		return err
+	}
+	if err := e.EncodeInt(int64(a.Weight)); err != nil {
 		return err
 	}
 	return nil
@@ -145,9 +372,10 @@ func (a *AnswerModel) DecodeMsgpack(d *msgpack.Decoder) error {
 	if l, err = d.DecodeArrayLen(); err != nil {
 		return err
 	}
-	if l != answerModelFields {
+	if l < answerModelRequiredFields || l > answerModelFields {
 		return fmt.Errorf("array len doesn't match: %d", l)
 	}
+	fields := l
 	if a.ID, err = d.DecodeString(); err != nil {
 		return err
 	}
@@ -157,7 +385,418 @@ func (a *AnswerModel) DecodeMsgpack(d *msgpack.Decoder) error {
 	if a.PollID, err = d.DecodeString(); err != nil {
 		return err
 	}
-	if a.Vote, err = d.DecodeInt(); err != nil {
+	if a.OptionID, err = d.DecodeInt(); err != nil {
+		return err
+	}
+
+	// optional fields in the order they were added, missing ones keep zero values
+	optional := []func() error{
+		func() error { return d.Decode(&a.Scores) },
+		func() (err error) { a.Weight, err = d.DecodeInt(); return err },
+	}
+	for _, decode := range optional[:fields-answerModelRequiredFields] {
+		if err = decode(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func NewEventModel(event *domain.PollEvent) *EventModel {
+	return &EventModel{
+		ID:      uuid.NewString(),
+		PollID:  event.PollID,
+		Type:    string(event.Type),
+		UserID:  event.UserID,
+		At:      unixOrZero(event.At),
+		Details: event.Details,
+	}
+}
+
+func (e *EventModel) ToEvent() *domain.PollEvent {
+	return &domain.PollEvent{
+		PollID:  e.PollID,
+		Type:    domain.PollEventType(e.Type),
+		UserID:  e.UserID,
+		At:      timeOrZero(e.At),
+		Details: e.Details,
+	}
+}
+
+func (e *EventModel) EncodeMsgpack(enc *msgpack.Encoder) error {
+	if err := enc.EncodeArrayLen(eventModelFields); err != nil {
+		return err
+	}
+	if err := enc.EncodeString(e.ID); err != nil {
+		return err
+	}
+	if err := enc.EncodeString(e.PollID); err != nil {
+		return err
+	}
+	if err := enc.EncodeString(e.Type); err != nil {
+		return err
+	}
+	if err := enc.EncodeString(e.UserID); err != nil {
+		return err
+	}
+	if err := enc.EncodeInt(e.At); err != nil {
+		return err
+	}
+	if err := enc.EncodeString(e.Details); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (e *EventModel) DecodeMsgpack(d *msgpack.Decoder) error {
+	var err error
+	var l int
+	if l, err = d.DecodeArrayLen(); err != nil {
+		return err
+	}
+	if l != eventModelFields {
+		return fmt.Errorf("array len doesn't match: %d", l)
+	}
+	if e.ID, err = d.DecodeString(); err != nil {
+		return err
+	}
+	if e.PollID, err = d.DecodeString(); err != nil {
+		return err
+	}
+	if e.Type, err = d.DecodeString(); err != nil {
+		return err
+	}
+	if e.UserID, err = d.DecodeString(); err != nil {
+		return err
+	}
+	if e.At, err = d.DecodeInt64(); err != nil {
+		return err
+	}
+	if e.Details, err = d.DecodeString(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func NewSuggestionModel(suggestion *domain.Suggestion) *SuggestionModel {
+	return &SuggestionModel{
+		ID:       suggestion.ID,
+		PollID:   suggestion.PollID,
+		UserID:   suggestion.UserID,
+		Text:     suggestion.Text,
+		Status:   string(suggestion.Status),
+		OptionID: int64(suggestion.OptionID),
+	}
+}
+
+func (s *SuggestionModel) ToSuggestion() *domain.Suggestion {
+	return &domain.Suggestion{
+		ID:       s.ID,
+		PollID:   s.PollID,
+		UserID:   s.UserID,
+		Text:     s.Text,
+		Status:   domain.SuggestionStatus(s.Status),
+		OptionID: int(s.OptionID),
+	}
+}
+
+func (s *SuggestionModel) EncodeMsgpack(e *msgpack.Encoder) error {
+	if err := e.EncodeArrayLen(suggestionModelFields); err != nil {
+		return err
+	}
+	if err := e.EncodeString(s.ID); err != nil {
+		return err
+	}
+	if err := e.EncodeString(s.PollID); err != nil {
+		return err
+	}
+	if err := e.EncodeString(s.UserID); err != nil {
+		return err
+	}
+	if err := e.EncodeString(s.Text); err != nil {
+		return err
+	}
+	if err := e.EncodeString(s.Status); err != nil {
+		return err
+	}
+	if err := e.EncodeInt(s.OptionID); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *SuggestionModel) DecodeMsgpack(d *msgpack.Decoder) error {
+	var err error
+	var l int
+	if l, err = d.DecodeArrayLen(); err != nil {
+		return err
+	}
+	if l != suggestionModelFields {
+		return fmt.Errorf("array len doesn't match: %d", l)
+	}
+	if s.ID, err = d.DecodeString(); err != nil {
+		return err
+	}
+	if s.PollID, err = d.DecodeString(); err != nil {
+		return err
+	}
+	if s.UserID, err = d.DecodeString(); err != nil {
+		return err
+	}
+	if s.Text, err = d.DecodeString(); err != nil {
+		return err
+	}
+	if s.Status, err = d.DecodeString(); err != nil {
+		return err
+	}
+	if s.OptionID, err = d.DecodeInt64(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func NewSurveyModel(survey *domain.Survey) *SurveyModel {
+	return &SurveyModel{
+		ID:        survey.ID,
+		Title:     survey.Title,
+		Questions: survey.Questions,
+		IsActive:  survey.IsActive,
+		Author:    survey.Author,
+		TeamID:    survey.TeamID,
+		ChannelID: survey.ChannelID,
+	}
+}
+
+func (s *SurveyModel) ToSurvey() *domain.Survey {
+	return &domain.Survey{
+		ID:        s.ID,
+		Title:     s.Title,
+		Questions: s.Questions,
+		IsActive:  s.IsActive,
+		Author:    s.Author,
+		TeamID:    s.TeamID,
+		ChannelID: s.ChannelID,
+	}
+}
+
+func (s *SurveyModel) EncodeMsgpack(e *msgpack.Encoder) error {
+	if err := e.EncodeArrayLen(surveyModelFields); err != nil {
+		return err
+	}
+	if err := e.EncodeString(s.ID); err != nil {
+		return err
+	}
+	if err := e.EncodeString(s.Title); err != nil {
+		return err
+	}
+	if err := e.Encode(s.Questions); err != nil {
+		return err
+	}
+	if err := e.EncodeBool(s.IsActive); err != nil {
+		return err
+	}
+	if err := e.EncodeString(s.Author); err != nil {
+		return err
+	}
+	if err := e.EncodeString(s.TeamID); err != nil {
+		return err
+	}
+	if err := e.EncodeString(s.ChannelID); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *SurveyModel) DecodeMsgpack(d *msgpack.Decoder) error {
+	var err error
+	var l int
+	if l, err = d.DecodeArrayLen(); err != nil {
+		return err
+	}
+	if l != surveyModelFields {
+		return fmt.Errorf("array len doesn't match: %d", l)
+	}
+	if s.ID, err = d.DecodeString(); err != nil {
+		return err
+	}
+	if s.Title, err = d.DecodeString(); err != nil {
+		return err
+	}
+	if err = d.Decode(&s.Questions); err != nil {
+		return err
+	}
+	if s.IsActive, err = d.DecodeBool(); err != nil {
+		return err
+	}
+	if s.Author, err = d.DecodeString(); err != nil {
+		return err
+	}
+	if s.TeamID, err = d.DecodeString(); err != nil {
+		return err
+	}
+	if s.ChannelID, err = d.DecodeString(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func NewSurveyResponseModel(response *domain.SurveyResponse) *SurveyResponseModel {
+	return &SurveyResponseModel{
+		ID:        response.ID,
+		SurveyID:  response.SurveyID,
+		UserID:    response.UserID,
+		Answers:   response.Answers,
+		Completed: response.Completed,
+		UpdatedAt: unixOrZero(response.UpdatedAt),
+	}
+}
+
+func (r *SurveyResponseModel) ToSurveyResponse() *domain.SurveyResponse {
+	return &domain.SurveyResponse{
+		ID:        r.ID,
+		SurveyID:  r.SurveyID,
+		UserID:    r.UserID,
+		Answers:   r.Answers,
+		Completed: r.Completed,
+		UpdatedAt: timeOrZero(r.UpdatedAt),
+	}
+}
+
+func (r *SurveyResponseModel) EncodeMsgpack(e *msgpack.Encoder) error {
+	if err := e.EncodeArrayLen(surveyResponseModelFields); err != nil {
+		return err
+	}
+	if err := e.EncodeString(r.ID); err != nil {
+		return err
+	}
+	if err := e.EncodeString(r.SurveyID); err != nil {
+		return err
+	}
+	if err := e.EncodeString(r.UserID); err != nil {
+		return err
+	}
+	if err := e.Encode(r.Answers); err != nil {
+		return err
+	}
+	if err := e.EncodeBool(r.Completed); err != nil {
+		return err
+	}
+	if err := e.EncodeInt(r.UpdatedAt); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *SurveyResponseModel) DecodeMsgpack(d *msgpack.Decoder) error {
+	var err error
+	var l int
+	if l, err = d.DecodeArrayLen(); err != nil {
+		return err
+	}
+	if l != surveyResponseModelFields {
+		return fmt.Errorf("array len doesn't match: %d", l)
+	}
+	if r.ID, err = d.DecodeString(); err != nil {
+		return err
+	}
+	if r.SurveyID, err = d.DecodeString(); err != nil {
+		return err
+	}
+	if r.UserID, err = d.DecodeString(); err != nil {
+		return err
+	}
+	if err = d.Decode(&r.Answers); err != nil {
+		return err
+	}
+	if r.Completed, err = d.DecodeBool(); err != nil {
+		return err
+	}
+	if r.UpdatedAt, err = d.DecodeInt64(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func NewTextAnswerModel(answer *domain.TextAnswer) *TextAnswerModel {
+	return &TextAnswerModel{
+		ID:         answer.ID,
+		SurveyID:   answer.SurveyID,
+		QuestionID: int64(answer.QuestionID),
+		UserID:     answer.UserID,
+		Text:       answer.Text,
+		CreatedAt:  unixOrZero(answer.CreatedAt),
+		Published:  answer.Published,
+	}
+}
+
+func (a *TextAnswerModel) ToTextAnswer() *domain.TextAnswer {
+	return &domain.TextAnswer{
+		ID:         a.ID,
+		SurveyID:   a.SurveyID,
+		QuestionID: int(a.QuestionID),
+		UserID:     a.UserID,
+		Text:       a.Text,
+		CreatedAt:  timeOrZero(a.CreatedAt),
+		Published:  a.Published,
+	}
+}
+
+func (a *TextAnswerModel) EncodeMsgpack(e *msgpack.Encoder) error {
+	if err := e.EncodeArrayLen(textAnswerModelFields); err != nil {
+		return err
+	}
+	if err := e.EncodeString(a.ID); err != nil {
+		return err
+	}
+	if err := e.EncodeString(a.SurveyID); err != nil {
+		return err
+	}
+	if err := e.EncodeInt(a.QuestionID); err != nil {
+		return err
+	}
+	if err := e.EncodeString(a.UserID); err != nil {
+		return err
+	}
+	if err := e.EncodeString(a.Text); err != nil {
+		return err
+	}
+	if err := e.EncodeInt(a.CreatedAt); err != nil {
+		return err
+	}
+	if err := e.EncodeBool(a.Published); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (a *TextAnswerModel) DecodeMsgpack(d *msgpack.Decoder) error {
+	var err error
+	var l int
+	if l, err = d.DecodeArrayLen(); err != nil {
+		return err
+	}
+	if l != textAnswerModelFields {
+		return fmt.Errorf("array len doesn't match: %d", l)
+	}
+	if a.ID, err = d.DecodeString(); err != nil {
+		return err
+	}
+	if a.SurveyID, err = d.DecodeString(); err != nil {
+		return err
+	}
+	if a.QuestionID, err = d.DecodeInt64(); err != nil {
+		return err
+	}
+	if a.UserID, err = d.DecodeString(); err != nil {
+		return err
+	}
+	if a.Text, err = d.DecodeString(); err != nil {
+		return err
+	}
+	if a.CreatedAt, err = d.DecodeInt64(); err != nil {
+		return err
+	}
+	if a.Published, err = d.DecodeBool(); err != nil {
 		return err
 	}
 	return nil
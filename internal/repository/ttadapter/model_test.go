@@ -0,0 +1,203 @@
+package ttadapter_test
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Xausdorf/mattermost-poll/internal/domain"
+	"github.com/Xausdorf/mattermost-poll/internal/repository/ttadapter"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// option returns an option as the option_ids migration of init.lua stores it: a map without Weight and Scores.
+func option(id int, text string, votes int) map[string]any {
+	return map[string]any{"ID": id, "Text": text, "Votes": votes}
+}
+
+func TestPollModelDecodeMigrated(t *testing.T) {
+	migrated := []any{"p1", "Lunch?", []any{option(0, "Pizza", 2), option(1, "Sushi", 1)}, true, "author"}
+	tests := []struct {
+		name  string
+		tuple []any
+		want  domain.Poll
+	}{
+		{
+			name:  "first version",
+			tuple: migrated,
+			want: domain.Poll{
+				ID:       "p1",
+				Question: "Lunch?",
+				Options:  []domain.PollOption{{ID: 0, Text: "Pizza", Votes: 2}, {ID: 1, Text: "Sushi", Votes: 1}},
+				IsActive: true,
+				Author:   "author",
+			},
+		},
+		{
+			name: "before option IDs",
+			// TeamID, ChannelID, NotifyAuthor, RemindEvery, RemindedAt, QuorumCount, QuorumPercent,
+			// ClosesAt, QuorumNotReached, ResultsVisibility
+			tuple: append(slices.Clone(migrated), "t1", "c1", true, 3600, 1700000000, 3, 0, 0, false, "after_close"),
+			want: domain.Poll{
+				ID:                "p1",
+				Question:          "Lunch?",
+				Options:           []domain.PollOption{{ID: 0, Text: "Pizza", Votes: 2}, {ID: 1, Text: "Sushi", Votes: 1}},
+				IsActive:          true,
+				Author:            "author",
+				TeamID:            "t1",
+				ChannelID:         "c1",
+				NotifyAuthor:      true,
+				RemindEvery:       time.Hour,
+				RemindedAt:        time.Unix(1700000000, 0),
+				Quorum:            domain.Quorum{Count: 3},
+				ResultsVisibility: "after_close",
+			},
+		},
+		{
+			name:  "with next option ID",
+			tuple: append(slices.Clone(migrated), "t1", "c1", false, 0, 0, 0, 0, 0, false, "", 5),
+			want: domain.Poll{
+				ID:           "p1",
+				Question:     "Lunch?",
+				Options:      []domain.PollOption{{ID: 0, Text: "Pizza", Votes: 2}, {ID: 1, Text: "Sushi", Votes: 1}},
+				IsActive:     true,
+				Author:       "author",
+				TeamID:       "t1",
+				ChannelID:    "c1",
+				NextOptionID: 5,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := msgpack.Marshal(tt.tuple)
+			if err != nil {
+				t.Fatalf("could not encode tuple: %v", err)
+			}
+			var model ttadapter.PollModel
+			if err = msgpack.Unmarshal(data, &model); err != nil {
+				t.Fatalf("could not decode poll: %v", err)
+			}
+			if got := model.ToPoll(); !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("decoded poll = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMigratedPollAddOption(t *testing.T) {
+	// polls saved before NextOptionID continue numbering after IDs given by the migration
+	tuple := []any{"p1", "Lunch?", []any{option(0, "Pizza", 2), option(1, "Sushi", 1)}, true, "author"}
+	data, err := msgpack.Marshal(tuple)
+	if err != nil {
+		t.Fatalf("could not encode tuple: %v", err)
+	}
+	var model ttadapter.PollModel
+	if err = msgpack.Unmarshal(data, &model); err != nil {
+		t.Fatalf("could not decode poll: %v", err)
+	}
+	poll := model.ToPoll()
+	added, err := poll.AddOption("Tacos", domain.PollLimits{})
+	if err != nil {
+		t.Fatalf("AddOption() error: %v", err)
+	}
+	if added.ID != 2 || poll.NextOptionID != 3 {
+		t.Errorf("added option ID %d, next option ID %d, want 2, 3", added.ID, poll.NextOptionID)
+	}
+}
+
+func TestPollModelDecodeInvalidLength(t *testing.T) {
+	tests := []struct {
+		name  string
+		tuple []any
+	}{
+		{name: "too short", tuple: []any{"p1", "Lunch?", []any{}, true}},
+		{name: "too long", tuple: make([]any, 23)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := msgpack.Marshal(tt.tuple)
+			if err != nil {
+				t.Fatalf("could not encode tuple: %v", err)
+			}
+			var model ttadapter.PollModel
+			if err = msgpack.Unmarshal(data, &model); err == nil {
+				t.Error("tuple of invalid length was decoded")
+			}
+		})
+	}
+}
+
+func TestPollModelRoundTrip(t *testing.T) {
+	poll := &domain.Poll{
+		ID:       "p1",
+		Question: "Lunch?",
+		Options: []domain.PollOption{
+			{ID: 0, Text: "Pizza", Votes: 2, Weight: 5, Scores: []int{0, 1, 0, 0, 1}},
+			{ID: 3, Text: "Sushi", Votes: 1, Weight: 1},
+		},
+		IsActive:     true,
+		Author:       "author",
+		TeamID:       "t1",
+		ChannelID:    "c1",
+		NextOptionID: 4,
+		Type:         domain.PollRating,
+		Scale:        domain.DefaultScale,
+		Weighted:     true,
+		Weights:      map[string]int{"u1": 3},
+	}
+	data, err := msgpack.Marshal(ttadapter.NewPollModel(poll))
+	if err != nil {
+		t.Fatalf("could not encode poll: %v", err)
+	}
+	var model ttadapter.PollModel
+	if err = msgpack.Unmarshal(data, &model); err != nil {
+		t.Fatalf("could not decode poll: %v", err)
+	}
+	if got := model.ToPoll(); !reflect.DeepEqual(got, poll) {
+		t.Errorf("decoded poll = %+v, want %+v", got, poll)
+	}
+}
+
+func TestAnswerModelDecode(t *testing.T) {
+	tests := []struct {
+		name  string
+		tuple []any
+		want  domain.Answer
+	}{
+		{
+			name:  "option ID stored as Vote",
+			tuple: []any{"a1", "u1", "p1", 1},
+			want:  domain.Answer{UserID: "u1", PollID: "p1", OptionID: 1},
+		},
+		{
+			name:  "with scores",
+			tuple: []any{"a1", "u1", "p1", 0, map[int]int{0: 5, 1: 3}},
+			want:  domain.Answer{UserID: "u1", PollID: "p1", Scores: map[int]int{0: 5, 1: 3}},
+		},
+		{
+			name:  "with weight",
+			tuple: []any{"a1", "u1", "p1", 2, nil, 3},
+			want:  domain.Answer{UserID: "u1", PollID: "p1", OptionID: 2, Weight: 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := msgpack.Marshal(tt.tuple)
+			if err != nil {
+				t.Fatalf("could not encode tuple: %v", err)
+			}
+			var model ttadapter.AnswerModel
+			if err = msgpack.Unmarshal(data, &model); err != nil {
+				t.Fatalf("could not decode answer: %v", err)
+			}
+			got := model.ToAnswer()
+			if got.UserID != tt.want.UserID || got.PollID != tt.want.PollID || got.OptionID != tt.want.OptionID ||
+				got.Weight != tt.want.Weight || !maps.Equal(got.Scores, tt.want.Scores) {
+				t.Errorf("decoded answer = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package ttadapter
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"sync"
 
 	"github.com/Xausdorf/mattermost-poll/internal/domain"
@@ -15,17 +16,20 @@ const (
 )
 
 type PollRepository struct {
-	conn *tarantool.Connection
-	mu   sync.Mutex
+	conn   tarantool.Doer
+	mu     sync.Mutex
+	logger *slog.Logger
 }
 
-func NewPollRepository(conn *tarantool.Connection) *PollRepository {
+func NewPollRepository(conn tarantool.Doer, logger *slog.Logger) *PollRepository {
 	return &PollRepository{
-		conn: conn,
+		conn:   conn,
+		logger: logger,
 	}
 }
 
 func (r *PollRepository) Save(ctx context.Context, poll *domain.Poll) error {
+	r.logger.DebugContext(ctx, "Inserting poll", "space", pollSpace, "poll_id", poll.ID)
 	_, err := r.conn.Do(
 		tarantool.NewInsertRequest(pollSpace).
 			Context(ctx).
@@ -35,6 +39,7 @@ func (r *PollRepository) Save(ctx context.Context, poll *domain.Poll) error {
 }
 
 func (r *PollRepository) GetByID(ctx context.Context, id string) (*domain.Poll, error) {
+	r.logger.DebugContext(ctx, "Selecting poll", "space", pollSpace, "poll_id", id)
 	var res []PollModel
 	if err := r.conn.Do(
 		tarantool.NewSelectRequest(pollSpace).
@@ -53,6 +58,7 @@ func (r *PollRepository) GetByID(ctx context.Context, id string) (*domain.Poll,
 
 func (r *PollRepository) UpdateByID(ctx context.Context, id string, updateFn func(poll *domain.Poll) error) error {
 	r.mu.Lock()
+	defer r.mu.Unlock()
 	poll, err := r.GetByID(ctx, id)
 	if err != nil {
 		return err
@@ -60,6 +66,7 @@ func (r *PollRepository) UpdateByID(ctx context.Context, id string, updateFn fun
 	if err = updateFn(poll); err != nil {
 		return fmt.Errorf("could not update poll: %w", err)
 	}
+	r.logger.DebugContext(ctx, "Replacing poll", "space", pollSpace, "poll_id", id)
 	if _, err = r.conn.Do(
 		tarantool.NewReplaceRequest(pollSpace).
 			Context(ctx).
@@ -67,11 +74,43 @@ func (r *PollRepository) UpdateByID(ctx context.Context, id string, updateFn fun
 	).Get(); err != nil {
 		return fmt.Errorf("could not replace in tarantool: %w", err)
 	}
-	r.mu.Unlock()
 	return nil
 }
 
+func (r *PollRepository) CountActiveByAuthor(ctx context.Context, author string) (int, error) {
+	r.logger.DebugContext(ctx, "Selecting active polls of author", "space", pollSpace, "author", author)
+	var res []PollModel
+	if err := r.conn.Do(
+		tarantool.NewSelectRequest(pollSpace).
+			Context(ctx).
+			Index("author_active").
+			Key([]interface{}{author, true}),
+	).GetTyped(&res); err != nil {
+		return 0, fmt.Errorf("could not select typed polls in tarantool: %w", err)
+	}
+	return len(res), nil
+}
+
+func (r *PollRepository) ListActive(ctx context.Context) ([]*domain.Poll, error) {
+	r.logger.DebugContext(ctx, "Selecting active polls", "space", pollSpace)
+	var res []PollModel
+	if err := r.conn.Do(
+		tarantool.NewSelectRequest(pollSpace).
+			Context(ctx).
+			Index("active").
+			Key([]interface{}{true}),
+	).GetTyped(&res); err != nil {
+		return nil, fmt.Errorf("could not select typed polls in tarantool: %w", err)
+	}
+	polls := make([]*domain.Poll, len(res))
+	for i := range res {
+		polls[i] = res[i].ToPoll()
+	}
+	return polls, nil
+}
+
 func (r *PollRepository) DeleteByID(ctx context.Context, id string) error {
+	r.logger.DebugContext(ctx, "Deleting poll", "space", pollSpace, "poll_id", id)
 	_, err := r.conn.Do(
 		tarantool.NewDeleteRequest(pollSpace).
 			Context(ctx).
@@ -0,0 +1,76 @@
+package ttadapter
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/Xausdorf/mattermost-poll/internal/domain"
+	"github.com/Xausdorf/mattermost-poll/internal/usecase"
+	"github.com/tarantool/go-tarantool/v2"
+)
+
+const (
+	suggestionSpace = "suggestions"
+)
+
+// SuggestionRepository - options of polls suggested by voters.
+type SuggestionRepository struct {
+	conn   tarantool.Doer
+	logger *slog.Logger
+}
+
+func NewSuggestionRepository(conn tarantool.Doer, logger *slog.Logger) *SuggestionRepository {
+	return &SuggestionRepository{
+		conn:   conn,
+		logger: logger,
+	}
+}
+
+// Save inserts the suggestion or replaces the existing one with the same ID.
+func (r *SuggestionRepository) Save(ctx context.Context, suggestion *domain.Suggestion) error {
+	r.logger.DebugContext(ctx, "Replacing suggestion", "space", suggestionSpace,
+		"poll_id", suggestion.PollID, "suggestion_id", suggestion.ID)
+	_, err := r.conn.Do(
+		tarantool.NewReplaceRequest(suggestionSpace).
+			Context(ctx).
+			Tuple(NewSuggestionModel(suggestion)),
+	).Get()
+	return err
+}
+
+func (r *SuggestionRepository) GetByID(ctx context.Context, id string) (*domain.Suggestion, error) {
+	r.logger.DebugContext(ctx, "Selecting suggestion", "space", suggestionSpace, "suggestion_id", id)
+	var res []SuggestionModel
+	if err := r.conn.Do(
+		tarantool.NewSelectRequest(suggestionSpace).
+			Context(ctx).
+			Index("primary").
+			Limit(1).
+			Key(tarantool.StringKey{S: id}),
+	).GetTyped(&res); err != nil {
+		return nil, fmt.Errorf("could not select typed suggestion in tarantool: %w", err)
+	}
+	if len(res) == 0 {
+		return nil, usecase.ErrSuggestionNotFound
+	}
+	return res[0].ToSuggestion(), nil
+}
+
+func (r *SuggestionRepository) ListByPoll(ctx context.Context, pollID string) ([]*domain.Suggestion, error) {
+	r.logger.DebugContext(ctx, "Selecting suggestions of poll", "space", suggestionSpace, "poll_id", pollID)
+	var res []SuggestionModel
+	if err := r.conn.Do(
+		tarantool.NewSelectRequest(suggestionSpace).
+			Context(ctx).
+			Index("poll").
+			Key(tarantool.StringKey{S: pollID}),
+	).GetTyped(&res); err != nil {
+		return nil, fmt.Errorf("could not select typed suggestions in tarantool: %w", err)
+	}
+	suggestions := make([]*domain.Suggestion, len(res))
+	for i := range res {
+		suggestions[i] = res[i].ToSuggestion()
+	}
+	return suggestions, nil
+}
@@ -0,0 +1,212 @@
+package ttadapter
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/Xausdorf/mattermost-poll/internal/domain"
+	"github.com/Xausdorf/mattermost-poll/internal/usecase"
+	"github.com/tarantool/go-tarantool/v2"
+)
+
+const (
+	surveySpace         = "surveys"
+	surveyResponseSpace = "survey_responses"
+	textAnswerSpace     = "text_answers"
+)
+
+// SurveyRepository - surveys with several questions.
+type SurveyRepository struct {
+	conn   tarantool.Doer
+	logger *slog.Logger
+}
+
+func NewSurveyRepository(conn tarantool.Doer, logger *slog.Logger) *SurveyRepository {
+	return &SurveyRepository{
+		conn:   conn,
+		logger: logger,
+	}
+}
+
+// Save inserts the survey or replaces the existing one with the same ID.
+func (r *SurveyRepository) Save(ctx context.Context, survey *domain.Survey) error {
+	r.logger.DebugContext(ctx, "Replacing survey", "space", surveySpace, "survey_id", survey.ID)
+	_, err := r.conn.Do(
+		tarantool.NewReplaceRequest(surveySpace).
+			Context(ctx).
+			Tuple(NewSurveyModel(survey)),
+	).Get()
+	return err
+}
+
+func (r *SurveyRepository) GetByID(ctx context.Context, id string) (*domain.Survey, error) {
+	r.logger.DebugContext(ctx, "Selecting survey", "space", surveySpace, "survey_id", id)
+	var res []SurveyModel
+	if err := r.conn.Do(
+		tarantool.NewSelectRequest(surveySpace).
+			Context(ctx).
+			Index("primary").
+			Limit(1).
+			Key(tarantool.StringKey{S: id}),
+	).GetTyped(&res); err != nil {
+		return nil, fmt.Errorf("could not select typed survey in tarantool: %w", err)
+	}
+	if len(res) == 0 {
+		return nil, usecase.ErrSurveyNotFound
+	}
+	return res[0].ToSurvey(), nil
+}
+
+// SurveyResponseRepository - answers of users to surveys, including responses in progress.
+type SurveyResponseRepository struct {
+	conn   tarantool.Doer
+	logger *slog.Logger
+}
+
+func NewSurveyResponseRepository(conn tarantool.Doer, logger *slog.Logger) *SurveyResponseRepository {
+	return &SurveyResponseRepository{
+		conn:   conn,
+		logger: logger,
+	}
+}
+
+// Save inserts the response or replaces the existing one with the same ID.
+func (r *SurveyResponseRepository) Save(ctx context.Context, response *domain.SurveyResponse) error {
+	r.logger.DebugContext(ctx, "Replacing survey response", "space", surveyResponseSpace,
+		"survey_id", response.SurveyID, "user_id", response.UserID)
+	_, err := r.conn.Do(
+		tarantool.NewReplaceRequest(surveyResponseSpace).
+			Context(ctx).
+			Tuple(NewSurveyResponseModel(response)),
+	).Get()
+	return err
+}
+
+func (r *SurveyResponseRepository) GetByUserAndSurvey(
+	ctx context.Context,
+	userID string,
+	surveyID string,
+) (*domain.SurveyResponse, error) {
+	r.logger.DebugContext(ctx, "Selecting survey response", "space", surveyResponseSpace,
+		"survey_id", surveyID, "user_id", userID)
+	var res []SurveyResponseModel
+	if err := r.conn.Do(
+		tarantool.NewSelectRequest(surveyResponseSpace).
+			Context(ctx).
+			Index("user_survey").
+			Limit(1).
+			Key([]interface{}{userID, surveyID}),
+	).GetTyped(&res); err != nil {
+		return nil, fmt.Errorf("could not select typed survey response in tarantool: %w", err)
+	}
+	if len(res) == 0 {
+		return nil, usecase.ErrSurveyResponseNotFound
+	}
+	return res[0].ToSurveyResponse(), nil
+}
+
+// ListInProgressByUser returns responses of the user which are not completed.
+func (r *SurveyResponseRepository) ListInProgressByUser(
+	ctx context.Context,
+	userID string,
+) ([]*domain.SurveyResponse, error) {
+	r.logger.DebugContext(ctx, "Selecting survey responses in progress", "space", surveyResponseSpace,
+		"user_id", userID)
+	var res []SurveyResponseModel
+	if err := r.conn.Do(
+		tarantool.NewSelectRequest(surveyResponseSpace).
+			Context(ctx).
+			Index("user_completed").
+			Key([]interface{}{userID, false}),
+	).GetTyped(&res); err != nil {
+		return nil, fmt.Errorf("could not select typed survey responses in tarantool: %w", err)
+	}
+	return toSurveyResponses(res), nil
+}
+
+func (r *SurveyResponseRepository) ListBySurvey(
+	ctx context.Context,
+	surveyID string,
+) ([]*domain.SurveyResponse, error) {
+	r.logger.DebugContext(ctx, "Selecting responses of survey", "space", surveyResponseSpace, "survey_id", surveyID)
+	var res []SurveyResponseModel
+	if err := r.conn.Do(
+		tarantool.NewSelectRequest(surveyResponseSpace).
+			Context(ctx).
+			Index("survey").
+			Key(tarantool.StringKey{S: surveyID}),
+	).GetTyped(&res); err != nil {
+		return nil, fmt.Errorf("could not select typed survey responses in tarantool: %w", err)
+	}
+	return toSurveyResponses(res), nil
+}
+
+func toSurveyResponses(res []SurveyResponseModel) []*domain.SurveyResponse {
+	responses := make([]*domain.SurveyResponse, len(res))
+	for i := range res {
+		responses[i] = res[i].ToSurveyResponse()
+	}
+	return responses
+}
+
+// TextAnswerRepository - answers to text questions of surveys.
+type TextAnswerRepository struct {
+	conn   tarantool.Doer
+	logger *slog.Logger
+}
+
+func NewTextAnswerRepository(conn tarantool.Doer, logger *slog.Logger) *TextAnswerRepository {
+	return &TextAnswerRepository{
+		conn:   conn,
+		logger: logger,
+	}
+}
+
+// Save inserts the answer or replaces the existing one with the same ID.
+func (r *TextAnswerRepository) Save(ctx context.Context, answer *domain.TextAnswer) error {
+	r.logger.DebugContext(ctx, "Replacing text answer", "space", textAnswerSpace,
+		"survey_id", answer.SurveyID, "answer_id", answer.ID)
+	_, err := r.conn.Do(
+		tarantool.NewReplaceRequest(textAnswerSpace).
+			Context(ctx).
+			Tuple(NewTextAnswerModel(answer)),
+	).Get()
+	return err
+}
+
+func (r *TextAnswerRepository) GetByID(ctx context.Context, id string) (*domain.TextAnswer, error) {
+	r.logger.DebugContext(ctx, "Selecting text answer", "space", textAnswerSpace, "answer_id", id)
+	var res []TextAnswerModel
+	if err := r.conn.Do(
+		tarantool.NewSelectRequest(textAnswerSpace).
+			Context(ctx).
+			Index("primary").
+			Limit(1).
+			Key(tarantool.StringKey{S: id}),
+	).GetTyped(&res); err != nil {
+		return nil, fmt.Errorf("could not select typed text answer in tarantool: %w", err)
+	}
+	if len(res) == 0 {
+		return nil, usecase.ErrTextAnswerNotFound
+	}
+	return res[0].ToTextAnswer(), nil
+}
+
+func (r *TextAnswerRepository) ListBySurvey(ctx context.Context, surveyID string) ([]*domain.TextAnswer, error) {
+	r.logger.DebugContext(ctx, "Selecting text answers of survey", "space", textAnswerSpace, "survey_id", surveyID)
+	var res []TextAnswerModel
+	if err := r.conn.Do(
+		tarantool.NewSelectRequest(textAnswerSpace).
+			Context(ctx).
+			Index("survey").
+			Key(tarantool.StringKey{S: surveyID}),
+	).GetTyped(&res); err != nil {
+		return nil, fmt.Errorf("could not select typed text answers in tarantool: %w", err)
+	}
+	answers := make([]*domain.TextAnswer, len(res))
+	for i := range res {
+		answers[i] = res[i].ToTextAnswer()
+	}
+	return answers, nil
+}
@@ -0,0 +1,357 @@
+package tracing
+
+import (
+	"context"
+	"time"
+
+	"github.com/Xausdorf/mattermost-poll/internal/domain"
+	"github.com/Xausdorf/mattermost-poll/internal/usecase"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// pollService - poll usecases, possibly already wrapped by other decorators.
+type pollService interface {
+	CreatePoll(ctx context.Context, poll *domain.Poll) error
+	AddAnswer(ctx context.Context, answer *domain.Answer) error
+	GetPollByID(ctx context.Context, id string) (*domain.Poll, error)
+	GetResults(ctx context.Context, id string, userID string) (*usecase.PollResults, error)
+	ClosePollByID(
+		ctx context.Context, id string, senderID string, opts usecase.CloseOptions,
+	) (*usecase.PollResults, error)
+	ExpiredPolls(ctx context.Context, now time.Time) ([]*domain.Poll, error)
+	ExpirePoll(ctx context.Context, id string, now time.Time, members int) (*usecase.PollResults, error)
+	ReopenPoll(ctx context.Context, id string, sender usecase.Sender) error
+	ExtendPoll(ctx context.Context, id string, sender usecase.Sender, d time.Duration) (*domain.Poll, error)
+	AddOption(ctx context.Context, id string, sender usecase.Sender, text string) (*domain.PollOption, error)
+	EditOption(ctx context.Context, id string, sender usecase.Sender, optionID int, text string) error
+	RemoveOption(
+		ctx context.Context, id string, sender usecase.Sender, optionID int, opts usecase.RemoveOptionOptions,
+	) (int, error)
+	SuggestOption(ctx context.Context, pollID string, userID string, text string) (*domain.Suggestion, error)
+	PendingSuggestions(ctx context.Context, pollID string, sender usecase.Sender) ([]*domain.Suggestion, error)
+	ReviewSuggestion(ctx context.Context, id string, sender usecase.Sender, approve bool) (*domain.Suggestion, error)
+	CreateSurvey(ctx context.Context, survey *domain.Survey) error
+	TakeSurvey(ctx context.Context, id string, userID string) (*domain.Survey, *domain.SurveyResponse, error)
+	AnswerSurvey(ctx context.Context, userID string, input string) (*domain.Survey, *domain.SurveyResponse, error)
+	SurveyInProgress(ctx context.Context, userID string) (*domain.Survey, *domain.SurveyResponse, error)
+	SurveyResults(ctx context.Context, id string, sender usecase.Sender) (*domain.SurveyResults, error)
+	CloseSurvey(ctx context.Context, id string, sender usecase.Sender) error
+	TextAnswers(
+		ctx context.Context, surveyID string, questionID int, sender usecase.Sender,
+	) (*domain.Survey, []*domain.TextAnswer, error)
+	PublishTextAnswers(
+		ctx context.Context, surveyID string, sender usecase.Sender, ids []string,
+	) (*domain.Survey, []*domain.TextAnswer, error)
+	DeletePollByID(ctx context.Context, id string, senderID string) error
+	RemindPoll(
+		ctx context.Context, id string, senderID string, listMembers func(ctx context.Context) ([]string, error),
+	) ([]string, error)
+	PollsToRemind(ctx context.Context, now time.Time) ([]*domain.Poll, error)
+	SetDoNotDisturb(ctx context.Context, userID string, enabled bool) error
+}
+
+// PollService - poll usecases which create a span for each call.
+type PollService struct {
+	poll   pollService
+	tracer trace.Tracer
+}
+
+// InstrumentPollService wraps poll usecases, the bot should use the result instead of usecases.
+func (t *Tracing) InstrumentPollService(poll pollService) *PollService {
+	return &PollService{
+		poll:   poll,
+		tracer: t.tracer,
+	}
+}
+
+func (s *PollService) CreatePoll(ctx context.Context, poll *domain.Poll) error {
+	ctx, span := s.start(ctx, "Poll.CreatePoll", attribute.String("user_id", poll.Author))
+	err := s.poll.CreatePoll(ctx, poll)
+	span.SetAttributes(attribute.String("poll_id", poll.ID))
+	end(span, err)
+	return err
+}
+
+func (s *PollService) AddAnswer(ctx context.Context, answer *domain.Answer) error {
+	ctx, span := s.start(ctx, "Poll.AddAnswer",
+		attribute.String("poll_id", answer.PollID), attribute.String("user_id", answer.UserID))
+	err := s.poll.AddAnswer(ctx, answer)
+	end(span, err)
+	return err
+}
+
+func (s *PollService) GetPollByID(ctx context.Context, id string) (*domain.Poll, error) {
+	ctx, span := s.start(ctx, "Poll.GetPollByID", attribute.String("poll_id", id))
+	poll, err := s.poll.GetPollByID(ctx, id)
+	end(span, err)
+	return poll, err
+}
+
+func (s *PollService) GetResults(ctx context.Context, id string, userID string) (*usecase.PollResults, error) {
+	ctx, span := s.start(ctx, "Poll.GetResults",
+		attribute.String("poll_id", id), attribute.String("user_id", userID))
+	results, err := s.poll.GetResults(ctx, id, userID)
+	end(span, err)
+	return results, err
+}
+
+func (s *PollService) ClosePollByID(
+	ctx context.Context, id string, senderID string, opts usecase.CloseOptions,
+) (*usecase.PollResults, error) {
+	ctx, span := s.start(ctx, "Poll.ClosePollByID",
+		attribute.String("poll_id", id), attribute.String("user_id", senderID), attribute.Bool("force", opts.Force))
+	closed, err := s.poll.ClosePollByID(ctx, id, senderID, opts)
+	if err == nil {
+		span.SetAttributes(attribute.Bool("publish_results", closed.PublishResults))
+	}
+	end(span, err)
+	return closed, err
+}
+
+func (s *PollService) ExpiredPolls(ctx context.Context, now time.Time) ([]*domain.Poll, error) {
+	ctx, span := s.start(ctx, "Poll.ExpiredPolls")
+	polls, err := s.poll.ExpiredPolls(ctx, now)
+	span.SetAttributes(attribute.Int("polls", len(polls)))
+	end(span, err)
+	return polls, err
+}
+
+func (s *PollService) ExpirePoll(
+	ctx context.Context, id string, now time.Time, members int,
+) (*usecase.PollResults, error) {
+	ctx, span := s.start(ctx, "Poll.ExpirePoll", attribute.String("poll_id", id))
+	closed, err := s.poll.ExpirePoll(ctx, id, now, members)
+	if err == nil {
+		span.SetAttributes(attribute.Bool("publish_results", closed.PublishResults))
+	}
+	end(span, err)
+	return closed, err
+}
+
+func (s *PollService) ReopenPoll(ctx context.Context, id string, sender usecase.Sender) error {
+	ctx, span := s.start(ctx, "Poll.ReopenPoll",
+		attribute.String("poll_id", id), attribute.String("user_id", sender.ID))
+	err := s.poll.ReopenPoll(ctx, id, sender)
+	end(span, err)
+	return err
+}
+
+func (s *PollService) ExtendPoll(ctx context.Context, id string, sender usecase.Sender, d time.Duration) (*domain.Poll, error) {
+	ctx, span := s.start(ctx, "Poll.ExtendPoll",
+		attribute.String("poll_id", id), attribute.String("user_id", sender.ID),
+		attribute.String("duration", d.String()))
+	poll, err := s.poll.ExtendPoll(ctx, id, sender, d)
+	end(span, err)
+	return poll, err
+}
+
+func (s *PollService) AddOption(
+	ctx context.Context, id string, sender usecase.Sender, text string,
+) (*domain.PollOption, error) {
+	ctx, span := s.start(ctx, "Poll.AddOption",
+		attribute.String("poll_id", id), attribute.String("user_id", sender.ID))
+	option, err := s.poll.AddOption(ctx, id, sender, text)
+	if option != nil {
+		span.SetAttributes(attribute.Int("option_id", option.ID))
+	}
+	end(span, err)
+	return option, err
+}
+
+func (s *PollService) EditOption(ctx context.Context, id string, sender usecase.Sender, optionID int, text string) error {
+	ctx, span := s.start(ctx, "Poll.EditOption",
+		attribute.String("poll_id", id), attribute.String("user_id", sender.ID),
+		attribute.Int("option_id", optionID))
+	err := s.poll.EditOption(ctx, id, sender, optionID, text)
+	end(span, err)
+	return err
+}
+
+func (s *PollService) RemoveOption(
+	ctx context.Context, id string, sender usecase.Sender, optionID int, opts usecase.RemoveOptionOptions,
+) (int, error) {
+	ctx, span := s.start(ctx, "Poll.RemoveOption",
+		attribute.String("poll_id", id), attribute.String("user_id", sender.ID),
+		attribute.Int("option_id", optionID), attribute.Bool("move_votes", opts.MoveTo != nil))
+	votes, err := s.poll.RemoveOption(ctx, id, sender, optionID, opts)
+	span.SetAttributes(attribute.Int("votes", votes))
+	end(span, err)
+	return votes, err
+}
+
+func (s *PollService) SuggestOption(
+	ctx context.Context, pollID string, userID string, text string,
+) (*domain.Suggestion, error) {
+	ctx, span := s.start(ctx, "Poll.SuggestOption",
+		attribute.String("poll_id", pollID), attribute.String("user_id", userID))
+	suggestion, err := s.poll.SuggestOption(ctx, pollID, userID, text)
+	if suggestion != nil {
+		span.SetAttributes(attribute.String("suggestion_id", suggestion.ID), attribute.String("status", string(suggestion.Status)))
+	}
+	end(span, err)
+	return suggestion, err
+}
+
+func (s *PollService) PendingSuggestions(
+	ctx context.Context, pollID string, sender usecase.Sender,
+) ([]*domain.Suggestion, error) {
+	ctx, span := s.start(ctx, "Poll.PendingSuggestions",
+		attribute.String("poll_id", pollID), attribute.String("user_id", sender.ID))
+	suggestions, err := s.poll.PendingSuggestions(ctx, pollID, sender)
+	span.SetAttributes(attribute.Int("suggestions", len(suggestions)))
+	end(span, err)
+	return suggestions, err
+}
+
+func (s *PollService) ReviewSuggestion(
+	ctx context.Context, id string, sender usecase.Sender, approve bool,
+) (*domain.Suggestion, error) {
+	ctx, span := s.start(ctx, "Poll.ReviewSuggestion",
+		attribute.String("suggestion_id", id), attribute.String("user_id", sender.ID),
+		attribute.Bool("approve", approve))
+	suggestion, err := s.poll.ReviewSuggestion(ctx, id, sender, approve)
+	end(span, err)
+	return suggestion, err
+}
+
+func (s *PollService) CreateSurvey(ctx context.Context, survey *domain.Survey) error {
+	ctx, span := s.start(ctx, "Poll.CreateSurvey",
+		attribute.String("user_id", survey.Author), attribute.Int("questions", len(survey.Questions)))
+	err := s.poll.CreateSurvey(ctx, survey)
+	span.SetAttributes(attribute.String("survey_id", survey.ID))
+	end(span, err)
+	return err
+}
+
+func (s *PollService) TakeSurvey(
+	ctx context.Context, id string, userID string,
+) (*domain.Survey, *domain.SurveyResponse, error) {
+	ctx, span := s.start(ctx, "Poll.TakeSurvey",
+		attribute.String("survey_id", id), attribute.String("user_id", userID))
+	survey, response, err := s.poll.TakeSurvey(ctx, id, userID)
+	if response != nil {
+		span.SetAttributes(attribute.Int("answered", len(response.Answers)))
+	}
+	end(span, err)
+	return survey, response, err
+}
+
+func (s *PollService) AnswerSurvey(
+	ctx context.Context, userID string, input string,
+) (*domain.Survey, *domain.SurveyResponse, error) {
+	ctx, span := s.start(ctx, "Poll.AnswerSurvey", attribute.String("user_id", userID))
+	survey, response, err := s.poll.AnswerSurvey(ctx, userID, input)
+	if survey != nil && response != nil {
+		span.SetAttributes(attribute.String("survey_id", survey.ID),
+			attribute.Int("answered", len(response.Answers)), attribute.Bool("completed", response.Completed))
+	}
+	end(span, err)
+	return survey, response, err
+}
+
+func (s *PollService) SurveyInProgress(
+	ctx context.Context, userID string,
+) (*domain.Survey, *domain.SurveyResponse, error) {
+	ctx, span := s.start(ctx, "Poll.SurveyInProgress", attribute.String("user_id", userID))
+	survey, response, err := s.poll.SurveyInProgress(ctx, userID)
+	if survey != nil {
+		span.SetAttributes(attribute.String("survey_id", survey.ID))
+	}
+	end(span, err)
+	return survey, response, err
+}
+
+func (s *PollService) SurveyResults(
+	ctx context.Context, id string, sender usecase.Sender,
+) (*domain.SurveyResults, error) {
+	ctx, span := s.start(ctx, "Poll.SurveyResults",
+		attribute.String("survey_id", id), attribute.String("user_id", sender.ID))
+	results, err := s.poll.SurveyResults(ctx, id, sender)
+	if results != nil {
+		span.SetAttributes(attribute.Int("respondents", results.Respondents))
+	}
+	end(span, err)
+	return results, err
+}
+
+func (s *PollService) CloseSurvey(ctx context.Context, id string, sender usecase.Sender) error {
+	ctx, span := s.start(ctx, "Poll.CloseSurvey",
+		attribute.String("survey_id", id), attribute.String("user_id", sender.ID))
+	err := s.poll.CloseSurvey(ctx, id, sender)
+	end(span, err)
+	return err
+}
+
+func (s *PollService) TextAnswers(
+	ctx context.Context, surveyID string, questionID int, sender usecase.Sender,
+) (*domain.Survey, []*domain.TextAnswer, error) {
+	ctx, span := s.start(ctx, "Poll.TextAnswers", attribute.String("survey_id", surveyID),
+		attribute.Int("question_id", questionID), attribute.String("user_id", sender.ID))
+	survey, answers, err := s.poll.TextAnswers(ctx, surveyID, questionID, sender)
+	span.SetAttributes(attribute.Int("answers", len(answers)))
+	end(span, err)
+	return survey, answers, err
+}
+
+func (s *PollService) PublishTextAnswers(
+	ctx context.Context, surveyID string, sender usecase.Sender, ids []string,
+) (*domain.Survey, []*domain.TextAnswer, error) {
+	ctx, span := s.start(ctx, "Poll.PublishTextAnswers", attribute.String("survey_id", surveyID),
+		attribute.String("user_id", sender.ID), attribute.Int("answers", len(ids)))
+	survey, answers, err := s.poll.PublishTextAnswers(ctx, surveyID, sender, ids)
+	end(span, err)
+	return survey, answers, err
+}
+
+func (s *PollService) DeletePollByID(ctx context.Context, id string, senderID string) error {
+	ctx, span := s.start(ctx, "Poll.DeletePollByID",
+		attribute.String("poll_id", id), attribute.String("user_id", senderID))
+	err := s.poll.DeletePollByID(ctx, id, senderID)
+	end(span, err)
+	return err
+}
+
+func (s *PollService) RemindPoll(
+	ctx context.Context, id string, senderID string, listMembers func(ctx context.Context) ([]string, error),
+) ([]string, error) {
+	ctx, span := s.start(ctx, "Poll.RemindPoll", attribute.String("poll_id", id), attribute.String("user_id", senderID))
+	members := 0
+	users, err := s.poll.RemindPoll(ctx, id, senderID, func(ctx context.Context) ([]string, error) {
+		ids, err := listMembers(ctx)
+		members = len(ids)
+		return ids, err
+	})
+	span.SetAttributes(attribute.Int("members", members), attribute.Int("reminded", len(users)))
+	end(span, err)
+	return users, err
+}
+
+func (s *PollService) PollsToRemind(ctx context.Context, now time.Time) ([]*domain.Poll, error) {
+	ctx, span := s.start(ctx, "Poll.PollsToRemind")
+	polls, err := s.poll.PollsToRemind(ctx, now)
+	span.SetAttributes(attribute.Int("polls", len(polls)))
+	end(span, err)
+	return polls, err
+}
+
+func (s *PollService) SetDoNotDisturb(ctx context.Context, userID string, enabled bool) error {
+	ctx, span := s.start(ctx, "Poll.SetDoNotDisturb",
+		attribute.String("user_id", userID), attribute.Bool("enabled", enabled))
+	err := s.poll.SetDoNotDisturb(ctx, userID, enabled)
+	end(span, err)
+	return err
+}
+
+func (s *PollService) start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return s.tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+func end(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
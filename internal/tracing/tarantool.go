@@ -0,0 +1,52 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+
+	"github.com/tarantool/go-tarantool/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Doer - tarantool.Doer which creates a span for each request.
+type Doer struct {
+	doer   tarantool.Doer
+	tracer trace.Tracer
+}
+
+// InstrumentDoer wraps the doer, repositories should use the result instead of the connection.
+func (t *Tracing) InstrumentDoer(doer tarantool.Doer) *Doer {
+	return &Doer{
+		doer:   doer,
+		tracer: t.tracer,
+	}
+}
+
+func (d *Doer) Do(req tarantool.Request) *tarantool.Future {
+	ctx := req.Ctx()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	request := strings.ToLower(strings.TrimPrefix(req.Type().String(), "IPROTO_"))
+	_, span := d.tracer.Start(ctx, "tarantool "+request,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "tarantool"),
+			attribute.String("db.operation.name", request),
+		),
+	)
+
+	fut := d.doer.Do(req)
+	go func() {
+		<-fut.WaitChan()
+		if _, err := fut.GetResponse(); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
+	return fut
+}
@@ -0,0 +1,104 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+const (
+	serviceName = "mattermost-poll"
+	scopeName   = "github.com/Xausdorf/mattermost-poll"
+)
+
+const (
+	ExporterNone   = "none"
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+)
+
+// Config - tracing settings. Endpoint and headers of OTLP exporter are read by the exporter itself
+// from standard OTEL_EXPORTER_OTLP_* variables.
+type Config struct {
+	Exporter string
+}
+
+// ParseExporter returns name of the exporter, "console" of OpenTelemetry specification means stdout.
+func ParseExporter(s string) (string, error) {
+	exporter := strings.ToLower(strings.TrimSpace(s))
+	switch exporter {
+	case "":
+		return ExporterNone, nil
+	case "console":
+		return ExporterStdout, nil
+	case ExporterNone, ExporterOTLP, ExporterStdout:
+		return exporter, nil
+	default:
+		return "", fmt.Errorf("trace exporter %q is not supported", s)
+	}
+}
+
+// Tracing - OpenTelemetry tracer provider of the bot, usecases and repositories.
+type Tracing struct {
+	provider trace.TracerProvider
+	tracer   trace.Tracer
+	shutdown func(context.Context) error
+}
+
+func New(ctx context.Context, cfg Config) (*Tracing, error) {
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch cfg.Exporter {
+	case ExporterOTLP:
+		exporter, err = otlptracehttp.New(ctx)
+	case ExporterStdout:
+		exporter, err = stdouttrace.New()
+	default:
+		provider := noop.NewTracerProvider()
+		return &Tracing{
+			provider: provider,
+			tracer:   provider.Tracer(scopeName),
+			shutdown: func(context.Context) error { return nil },
+		}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not create %s trace exporter: %w", cfg.Exporter, err)
+	}
+
+	// OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES override the defaults
+	res, err := resource.Merge(
+		resource.NewSchemaless(semconv.ServiceName(serviceName)),
+		resource.Environment(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not create trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	return &Tracing{
+		provider: provider,
+		tracer:   provider.Tracer(scopeName),
+		shutdown: provider.Shutdown,
+	}, nil
+}
+
+// TracerProvider returns provider for components which create their own spans.
+func (t *Tracing) TracerProvider() trace.TracerProvider {
+	return t.provider
+}
+
+// Shutdown flushes spans which are not exported yet.
+func (t *Tracing) Shutdown(ctx context.Context) error {
+	return t.shutdown(ctx)
+}
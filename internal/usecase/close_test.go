@@ -0,0 +1,56 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Xausdorf/mattermost-poll/internal/domain"
+	"github.com/Xausdorf/mattermost-poll/internal/usecase"
+)
+
+func TestClosedPollPublishResults(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		visibility domain.ResultsVisibility
+		want       bool
+	}{
+		{visibility: "", want: true},
+		{visibility: domain.ResultsAlways, want: true},
+		{visibility: domain.ResultsAfterClose, want: true},
+		{visibility: domain.ResultsAfterVote, want: false},
+		{visibility: domain.ResultsAuthorOnly, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.visibility), func(t *testing.T) {
+			newUsecase := func() *usecase.Poll {
+				polls := newPollRepository(domain.Poll{
+					ID:                "p1",
+					Author:            "author",
+					IsActive:          true,
+					ClosesAt:          now,
+					ResultsVisibility: tt.visibility,
+					Options:           []domain.PollOption{{ID: 0, Votes: 1}, {ID: 1}},
+				})
+				return usecase.NewPoll(polls, nil, nil, &eventRepository{}, nil, nil, nil, nil,
+					usecase.Config{}, discardLogger())
+			}
+
+			closed, err := newUsecase().ClosePollByID(context.Background(), "p1", "author", usecase.CloseOptions{})
+			if err != nil {
+				t.Fatalf("ClosePollByID() error: %v", err)
+			}
+			expired, err := newUsecase().ExpirePoll(context.Background(), "p1", now, 0)
+			if err != nil {
+				t.Fatalf("ExpirePoll() error: %v", err)
+			}
+			if closed.PublishResults != tt.want || expired.PublishResults != tt.want {
+				t.Errorf("publish results on close %v, on expiry %v, want %v",
+					closed.PublishResults, expired.PublishResults, tt.want)
+			}
+			if closed.Poll.IsActive || expired.Poll.IsActive {
+				t.Error("closed poll is still active")
+			}
+		})
+	}
+}
@@ -0,0 +1,253 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Xausdorf/mattermost-poll/internal/domain"
+	"github.com/Xausdorf/mattermost-poll/internal/usecase"
+)
+
+func TestReopenPoll(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+	tests := []struct {
+		name         string
+		poll         domain.Poll
+		others       []domain.Poll
+		sender       usecase.Sender
+		limit        int
+		wantErr      error
+		wantActive   bool
+		wantClosesAt time.Time
+	}{
+		{
+			name:       "by author",
+			poll:       domain.Poll{ID: "p1", Author: "author", ChannelID: "c1", QuorumNotReached: true},
+			sender:     usecase.Sender{ID: "author"},
+			wantActive: true,
+		},
+		{
+			name:       "by admin of the poll's channel",
+			poll:       domain.Poll{ID: "p1", Author: "author", ChannelID: "c1"},
+			sender:     usecase.Sender{ID: "admin", Roles: roles{adminOf: []string{"c1"}}},
+			wantActive: true,
+		},
+		{
+			name:    "by admin of another channel",
+			poll:    domain.Poll{ID: "p1", Author: "author", ChannelID: "c1"},
+			sender:  usecase.Sender{ID: "admin", Roles: roles{adminOf: []string{"c2"}}},
+			wantErr: usecase.ErrUserIsNotPollAuthor,
+		},
+		{
+			name:    "by another user",
+			poll:    domain.Poll{ID: "p1", Author: "author", ChannelID: "c1"},
+			sender:  usecase.Sender{ID: "user"},
+			wantErr: usecase.ErrUserIsNotPollAuthor,
+		},
+		{
+			name:       "active already",
+			poll:       domain.Poll{ID: "p1", Author: "author", ChannelID: "c1", IsActive: true},
+			sender:     usecase.Sender{ID: "author"},
+			limit:      1,
+			wantErr:    usecase.ErrPollIsActive,
+			wantActive: true,
+		},
+		{
+			name:    "limit of active polls",
+			poll:    domain.Poll{ID: "p1", Author: "author", ChannelID: "c1"},
+			others:  []domain.Poll{{ID: "p2", Author: "author", IsActive: true}},
+			sender:  usecase.Sender{ID: "author"},
+			limit:   1,
+			wantErr: usecase.ErrTooManyActivePolls,
+		},
+		{
+			name:       "limit counts only polls of the author",
+			poll:       domain.Poll{ID: "p1", Author: "author", ChannelID: "c1"},
+			others:     []domain.Poll{{ID: "p2", Author: "user", IsActive: true}},
+			sender:     usecase.Sender{ID: "author"},
+			limit:      1,
+			wantActive: true,
+		},
+		{
+			name:       "passed deadline is removed",
+			poll:       domain.Poll{ID: "p1", Author: "author", ChannelID: "c1", ClosesAt: past},
+			sender:     usecase.Sender{ID: "author"},
+			wantActive: true,
+		},
+		{
+			name:         "future deadline is kept",
+			poll:         domain.Poll{ID: "p1", Author: "author", ChannelID: "c1", ClosesAt: future},
+			sender:       usecase.Sender{ID: "author"},
+			wantActive:   true,
+			wantClosesAt: future,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			polls := newPollRepository(append(tt.others, tt.poll)...)
+			events := &eventRepository{}
+			uc := usecase.NewPoll(polls, nil, nil, events, nil, nil, nil, nil,
+				usecase.Config{MaxActivePollsPerAuthor: tt.limit}, discardLogger())
+
+			if err := uc.ReopenPoll(context.Background(), "p1", tt.sender); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ReopenPoll() error = %v, want %v", err, tt.wantErr)
+			}
+			reopened := polls.get("p1")
+			if reopened.IsActive != tt.wantActive {
+				t.Errorf("poll is active = %v, want %v", reopened.IsActive, tt.wantActive)
+			}
+			if !reopened.ClosesAt.Equal(tt.wantClosesAt) && tt.wantErr == nil {
+				t.Errorf("closes at %v, want %v", reopened.ClosesAt, tt.wantClosesAt)
+			}
+			if tt.wantErr == nil && reopened.QuorumNotReached {
+				t.Error("reopened poll keeps quorum not reached")
+			}
+			wantEvents := 0
+			if tt.wantErr == nil {
+				wantEvents = 1
+			}
+			if len(events.events) != wantEvents {
+				t.Errorf("%d events recorded, want %d", len(events.events), wantEvents)
+			}
+		})
+	}
+}
+
+func TestExtendPoll(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name    string
+		poll    domain.Poll
+		sender  usecase.Sender
+		d       time.Duration
+		wantErr error
+		// wantAfter, wantBefore - bounds of the new deadline.
+		wantAfter  time.Time
+		wantBefore time.Time
+	}{
+		{
+			name: "before deadline",
+			poll: domain.Poll{
+				ID: "p1", Author: "author", ChannelID: "c1", IsActive: true, ClosesAt: now.Add(time.Hour),
+			},
+			sender:     usecase.Sender{ID: "author"},
+			d:          time.Hour,
+			wantAfter:  now.Add(2*time.Hour - time.Second),
+			wantBefore: now.Add(2*time.Hour + time.Second),
+		},
+		{
+			name: "past deadline is counted from now",
+			poll: domain.Poll{
+				ID: "p1", Author: "author", ChannelID: "c1", IsActive: true, ClosesAt: now.Add(-24 * time.Hour),
+			},
+			sender:     usecase.Sender{ID: "author"},
+			d:          time.Hour,
+			wantAfter:  now.Add(time.Hour - time.Second),
+			wantBefore: now.Add(time.Hour + time.Minute),
+		},
+		{
+			name: "by admin of the poll's channel",
+			poll: domain.Poll{
+				ID: "p1", Author: "author", ChannelID: "c1", IsActive: true, ClosesAt: now.Add(time.Hour),
+			},
+			sender:     usecase.Sender{ID: "admin", Roles: roles{adminOf: []string{"c1"}}},
+			d:          time.Minute,
+			wantAfter:  now.Add(time.Hour),
+			wantBefore: now.Add(time.Hour + 2*time.Minute),
+		},
+		{
+			name: "by admin of another channel",
+			poll: domain.Poll{
+				ID: "p1", Author: "author", ChannelID: "c1", IsActive: true, ClosesAt: now.Add(time.Hour),
+			},
+			sender:  usecase.Sender{ID: "admin", Roles: roles{adminOf: []string{"c2"}}},
+			d:       time.Minute,
+			wantErr: usecase.ErrUserIsNotPollAuthor,
+		},
+		{
+			name:    "no deadline",
+			poll:    domain.Poll{ID: "p1", Author: "author", ChannelID: "c1", IsActive: true},
+			sender:  usecase.Sender{ID: "author"},
+			d:       time.Hour,
+			wantErr: usecase.ErrPollHasNoDeadline,
+		},
+		{
+			name:    "closed poll",
+			poll:    domain.Poll{ID: "p1", Author: "author", ChannelID: "c1", ClosesAt: now.Add(time.Hour)},
+			sender:  usecase.Sender{ID: "author"},
+			d:       time.Hour,
+			wantErr: usecase.ErrPollIsNotActive,
+		},
+		{
+			name: "invalid duration",
+			poll: domain.Poll{
+				ID: "p1", Author: "author", ChannelID: "c1", IsActive: true, ClosesAt: now.Add(time.Hour),
+			},
+			sender:  usecase.Sender{ID: "author"},
+			d:       -time.Hour,
+			wantErr: usecase.ErrInvalidDuration,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			polls := newPollRepository(tt.poll)
+			uc := usecase.NewPoll(polls, nil, nil, &eventRepository{}, nil, nil, nil, nil,
+				usecase.Config{}, discardLogger())
+
+			extended, err := uc.ExtendPoll(context.Background(), "p1", tt.sender, tt.d)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ExtendPoll() error = %v, want %v", err, tt.wantErr)
+			}
+			closesAt := polls.get("p1").ClosesAt
+			if err != nil {
+				if !closesAt.Equal(tt.poll.ClosesAt) {
+					t.Errorf("deadline changed to %v after error", closesAt)
+				}
+				return
+			}
+			if !extended.ClosesAt.Equal(closesAt) {
+				t.Errorf("returned deadline %v, saved %v", extended.ClosesAt, closesAt)
+			}
+			if closesAt.Before(tt.wantAfter) || closesAt.After(tt.wantBefore) {
+				t.Errorf("closes at %v, want between %v and %v", closesAt, tt.wantAfter, tt.wantBefore)
+			}
+		})
+	}
+}
+
+func TestDeletedPoll(t *testing.T) {
+	tests := []struct {
+		name    string
+		deleted bool
+		wantErr error
+	}{
+		{name: "deleted", deleted: true, wantErr: usecase.ErrPollDeleted},
+		{name: "never existed", wantErr: usecase.ErrPollNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			polls := newPollRepository(domain.Poll{ID: "p1", Author: "author", IsActive: true})
+			uc := usecase.NewPoll(polls, &answerRepository{}, nil, &eventRepository{}, nil, nil, nil, nil,
+				usecase.Config{}, discardLogger())
+			ctx := context.Background()
+			id := "p2"
+			if tt.deleted {
+				if err := uc.DeletePollByID(ctx, "p1", "author"); err != nil {
+					t.Fatalf("DeletePollByID() error: %v", err)
+				}
+				id = "p1"
+			}
+			sender := usecase.Sender{ID: "author"}
+
+			if err := uc.ReopenPoll(ctx, id, sender); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ReopenPoll() error = %v, want %v", err, tt.wantErr)
+			}
+			if _, err := uc.ExtendPoll(ctx, id, sender, time.Hour); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ExtendPoll() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -4,6 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
 
 	"github.com/Xausdorf/mattermost-poll/internal/domain"
 )
@@ -16,35 +20,280 @@ var (
 	ErrNoSuchOption        = errors.New("there is no such option in poll")
 	ErrAnswerNotFound      = errors.New("answer not found")
 	ErrAnswerAlreadyExists = errors.New("answer already exists")
+	ErrTooManyActivePolls  = errors.New("too many active polls")
+	ErrQuorumNotReached    = errors.New("quorum is not reached")
+	ErrResultsHidden       = errors.New("results are hidden")
+	ErrPollDeleted         = errors.New("poll is deleted")
+	ErrPollIsActive        = errors.New("poll is active")
+	ErrPollHasNoDeadline   = errors.New("poll has no deadline")
+	ErrInvalidDuration     = errors.New("invalid duration")
+	ErrSuggestionsDisabled = errors.New("suggestions are disabled")
+	ErrTooManySuggestions  = errors.New("too many suggestions")
+	ErrSuggestionExists    = errors.New("suggestion already exists")
+	ErrSuggestionNotFound  = errors.New("suggestion not found")
+	ErrSuggestionReviewed  = errors.New("suggestion is already reviewed")
+	ErrSurveyNotFound      = errors.New("survey not found")
+	ErrSurveyIsNotActive   = errors.New("survey is not active")
+	ErrSurveyCompleted     = errors.New("survey is already completed")
+	// ErrSurveyResponseNotFound - the user has not started the survey, or has no survey in progress.
+	ErrSurveyResponseNotFound = errors.New("survey response not found")
+	ErrSurveyQuestionNotFound = errors.New("survey question not found")
+	ErrNotTextQuestion        = errors.New("question is not a text question")
+	ErrTextAnswerNotFound     = errors.New("text answer not found")
 )
 
+// ResultsHiddenError - the user can't see votes of the poll yet, only count of voters.
+type ResultsHiddenError struct {
+	Visibility domain.ResultsVisibility
+	Voters     int
+}
+
+func (e *ResultsHiddenError) Error() string {
+	return fmt.Sprintf("%v, visibility %s", ErrResultsHidden, e.Visibility)
+}
+
+func (e *ResultsHiddenError) Unwrap() error {
+	return ErrResultsHidden
+}
+
+// QuorumNotReachedError - the poll can't be closed yet, because fewer users voted than its quorum requires.
+type QuorumNotReachedError struct {
+	Voters   int
+	Required int
+}
+
+func (e *QuorumNotReachedError) Error() string {
+	return fmt.Sprintf("%v, voters %d of %d", ErrQuorumNotReached, e.Voters, e.Required)
+}
+
+func (e *QuorumNotReachedError) Unwrap() error {
+	return ErrQuorumNotReached
+}
+
 type PollRepository interface {
 	Save(ctx context.Context, poll *domain.Poll) error
 	UpdateByID(ctx context.Context, id string, updateFn func(poll *domain.Poll) error) error
 	GetByID(ctx context.Context, id string) (*domain.Poll, error)
 	DeleteByID(ctx context.Context, id string) error
+	CountActiveByAuthor(ctx context.Context, author string) (int, error)
+	ListActive(ctx context.Context) ([]*domain.Poll, error)
 }
 
 type AnswerRepository interface {
 	Save(ctx context.Context, answer *domain.Answer) error
 	GetByUserAndPoll(ctx context.Context, userID string, pollID string) (*domain.Answer, error)
+	ListByPoll(ctx context.Context, pollID string) ([]*domain.Answer, error)
 	DeleteByPoll(ctx context.Context, pollID string) error
+	DeleteByOption(ctx context.Context, pollID string, optionID int) (int, error)
+	MoveToOption(ctx context.Context, pollID string, from int, to int) (int, error)
+}
+
+// DoNotDisturbRepository - users who don't want to receive reminders.
+type DoNotDisturbRepository interface {
+	Add(ctx context.Context, userID string) error
+	Remove(ctx context.Context, userID string) error
+	List(ctx context.Context) ([]string, error)
+}
+
+// EventRepository - lifecycle events of polls.
+type EventRepository interface {
+	Save(ctx context.Context, event *domain.PollEvent) error
+	ListByPoll(ctx context.Context, pollID string) ([]*domain.PollEvent, error)
+}
+
+// SuggestionRepository - options of polls suggested by voters.
+type SuggestionRepository interface {
+	Save(ctx context.Context, suggestion *domain.Suggestion) error
+	GetByID(ctx context.Context, id string) (*domain.Suggestion, error)
+	ListByPoll(ctx context.Context, pollID string) ([]*domain.Suggestion, error)
+}
+
+// SurveyRepository - surveys with several questions.
+type SurveyRepository interface {
+	Save(ctx context.Context, survey *domain.Survey) error
+	GetByID(ctx context.Context, id string) (*domain.Survey, error)
+}
+
+// SurveyResponseRepository - answers of users to surveys, including responses in progress.
+type SurveyResponseRepository interface {
+	Save(ctx context.Context, response *domain.SurveyResponse) error
+	GetByUserAndSurvey(ctx context.Context, userID string, surveyID string) (*domain.SurveyResponse, error)
+	ListInProgressByUser(ctx context.Context, userID string) ([]*domain.SurveyResponse, error)
+	ListBySurvey(ctx context.Context, surveyID string) ([]*domain.SurveyResponse, error)
+}
+
+// TextAnswerRepository - answers to text questions of surveys.
+type TextAnswerRepository interface {
+	Save(ctx context.Context, answer *domain.TextAnswer) error
+	GetByID(ctx context.Context, id string) (*domain.TextAnswer, error)
+	ListBySurvey(ctx context.Context, surveyID string) ([]*domain.TextAnswer, error)
+}
+
+// Config - limits of poll service. Zero value means no limit.
+type Config struct {
+	// MaxActivePollsPerAuthor - how many active polls a single user can have at the same time.
+	MaxActivePollsPerAuthor int
+	// PollLimits - limits of question and options of created polls.
+	PollLimits domain.PollLimits
+	// MaxSuggestionsPerUser - how many options a single user can suggest in a poll.
+	MaxSuggestionsPerUser int
+	// MaxSurveyQuestions - how many questions a survey can have, questions are limited by PollLimits.
+	MaxSurveyQuestions int
+	// MaxTextAnswerLength - how many characters an answer to a text question can have.
+	MaxTextAnswerLength int
+}
+
+// CloseOptions - parameters of closing a poll.
+type CloseOptions struct {
+	// Force - close the poll even if its quorum is not reached.
+	Force bool
+	// Members - counts members of the poll's channel for quorum in percents, nil means the channel has no members.
+	// It is called only after the sender is checked and only if the quorum is checked.
+	Members func(ctx context.Context, poll *domain.Poll) (int, error)
+}
+
+// PollResults - poll with votes, returned when results are requested or the poll is closed.
+type PollResults struct {
+	Poll *domain.Poll
+	// PublishResults - votes can be posted to the poll's channel, because its results visibility
+	// lets every member of the channel see them, including members who didn't vote.
+	// Otherwise they are shown only to the user who can see them.
+	PublishResults bool
+}
+
+func newPollResults(poll *domain.Poll) *PollResults {
+	// empty user ID stands for any member of the channel who is not the author and didn't vote
+	return &PollResults{Poll: poll, PublishResults: poll.ResultsVisibleTo("", false)}
+}
+
+// RemoveOptionOptions - what happens with votes for a removed option.
+type RemoveOptionOptions struct {
+	// MoveTo - ID of the option which receives votes for the removed one.
+	// If it is nil, the votes are discarded and their users can vote again.
+	MoveTo *int
+}
+
+// Roles - permissions of the user who sends a command, checked against the channel of the changed poll or survey.
+type Roles interface {
+	// IsAdmin returns true if the user is a system admin, or a member and an admin of the channel.
+	IsAdmin(ctx context.Context, channelID string) (bool, error)
+}
+
+// Sender - user who changes a poll.
+type Sender struct {
+	ID string
+	// Roles - permissions of the user in Mattermost, nil means the user can manage only own polls.
+	Roles Roles
+}
+
+// authorize returns ErrUserIsNotPollAuthor unless the sender is the author, a system admin
+// or an admin of the channel where the poll or survey was started.
+func (s Sender) authorize(ctx context.Context, author string, channelID string) error {
+	if s.ID == author {
+		return nil
+	}
+	if s.Roles == nil {
+		return ErrUserIsNotPollAuthor
+	}
+	admin, err := s.Roles.IsAdmin(ctx, channelID)
+	if err != nil {
+		return fmt.Errorf("could not check permissions: %w", err)
+	}
+	if !admin {
+		return ErrUserIsNotPollAuthor
+	}
+	return nil
 }
 
 type Poll struct {
-	pollRepo   PollRepository
-	answerRepo AnswerRepository
+	pollRepo       PollRepository
+	answerRepo     AnswerRepository
+	dndRepo        DoNotDisturbRepository
+	eventRepo      EventRepository
+	suggestionRepo SuggestionRepository
+	surveyRepo     SurveyRepository
+	responseRepo   SurveyResponseRepository
+	textAnswerRepo TextAnswerRepository
+	logger         *slog.Logger
+
+	cfgMu sync.RWMutex
+	cfg   Config
 }
 
-func NewPoll(pollRepo PollRepository, answerRepo AnswerRepository) *Poll {
+func NewPoll(
+	pollRepo PollRepository,
+	answerRepo AnswerRepository,
+	dndRepo DoNotDisturbRepository,
+	eventRepo EventRepository,
+	suggestionRepo SuggestionRepository,
+	surveyRepo SurveyRepository,
+	responseRepo SurveyResponseRepository,
+	textAnswerRepo TextAnswerRepository,
+	cfg Config,
+	logger *slog.Logger,
+) *Poll {
 	return &Poll{
-		pollRepo:   pollRepo,
-		answerRepo: answerRepo,
+		pollRepo:       pollRepo,
+		answerRepo:     answerRepo,
+		dndRepo:        dndRepo,
+		eventRepo:      eventRepo,
+		suggestionRepo: suggestionRepo,
+		surveyRepo:     surveyRepo,
+		responseRepo:   responseRepo,
+		textAnswerRepo: textAnswerRepo,
+		cfg:            cfg,
+		logger:         logger,
 	}
 }
 
+// SetConfig changes limits of the service, polls created before keep working as they are.
+func (p *Poll) SetConfig(cfg Config) {
+	p.cfgMu.Lock()
+	p.cfg = cfg
+	p.cfgMu.Unlock()
+}
+
+func (p *Poll) config() Config {
+	p.cfgMu.RLock()
+	defer p.cfgMu.RUnlock()
+	return p.cfg
+}
+
 func (p *Poll) CreatePoll(ctx context.Context, poll *domain.Poll) error {
-	return p.pollRepo.Save(ctx, poll)
+	cfg := p.config()
+	if err := poll.Validate(cfg.PollLimits); err != nil {
+		return fmt.Errorf("invalid poll: %w", err)
+	}
+	poll.Sanitize()
+	if poll.RemindEvery > 0 {
+		poll.RemindedAt = time.Now()
+	}
+
+	if err := p.checkActiveLimit(ctx, poll.Author); err != nil {
+		return err
+	}
+	if err := p.pollRepo.Save(ctx, poll); err != nil {
+		return err
+	}
+	p.logger.InfoContext(ctx, "Poll created", "poll_id", poll.ID, "author", poll.Author, "options", len(poll.Options))
+	p.recordEvent(ctx, domain.NewPollEvent(poll.ID, domain.PollCreated, poll.Author))
+	return nil
+}
+
+// checkActiveLimit returns ErrTooManyActivePolls if the author can't have one more active poll.
+func (p *Poll) checkActiveLimit(ctx context.Context, author string) error {
+	limit := p.config().MaxActivePollsPerAuthor
+	if limit <= 0 {
+		return nil
+	}
+	count, err := p.pollRepo.CountActiveByAuthor(ctx, author)
+	if err != nil {
+		return fmt.Errorf("could not count active polls: %w", err)
+	}
+	if count >= limit {
+		return ErrTooManyActivePolls
+	}
+	return nil
 }
 
 func (p *Poll) AddAnswer(ctx context.Context, answer *domain.Answer) error {
@@ -52,25 +301,32 @@ func (p *Poll) AddAnswer(ctx context.Context, answer *domain.Answer) error {
 		return err
 	}
 
-	if ok, err := p.isPollActive(ctx, answer.PollID); err != nil {
-		return err
-	} else if !ok {
+	poll, err := p.pollRepo.GetByID(ctx, answer.PollID)
+	if err != nil {
+		return fmt.Errorf("could not retrieve poll: %w", err)
+	}
+	if !poll.IsActive {
 		return ErrPollIsNotActive
 	}
+	// the weight is stored with the answer, so later changes of weights don't change counted votes
+	answer.Weight = poll.VoteWeight(answer.UserID, answer.Weight)
+	// counted on the retrieved copy only to check the answer before it is saved
+	if err = countVote(poll, answer); err != nil {
+		return err
+	}
 
 	// TODO combine into a single transaction
-	if err := p.answerRepo.Save(ctx, answer); err != nil {
+	if err = p.answerRepo.Save(ctx, answer); err != nil {
 		return fmt.Errorf("could not save answer: %w", err)
 	}
-	if err := p.pollRepo.UpdateByID(ctx, answer.PollID, func(poll *domain.Poll) error {
-		if len(poll.Options) <= answer.Vote {
-			return ErrNoSuchOption
-		}
-		poll.Options[answer.Vote].Votes++
-		return nil
+	if err = p.pollRepo.UpdateByID(ctx, answer.PollID, func(poll *domain.Poll) error {
+		// options could be changed after the check above
+		return countVote(poll, answer)
 	}); err != nil {
 		return fmt.Errorf("could not update poll: %w", err)
 	}
+	p.logger.InfoContext(ctx, "Answer added", "poll_id", answer.PollID, "user_id", answer.UserID,
+		"weight", answer.Weight)
 	return nil
 }
 
@@ -82,14 +338,265 @@ func (p *Poll) GetPollByID(ctx context.Context, id string) (*domain.Poll, error)
 	return poll, nil
 }
 
-func (p *Poll) ClosePollByID(ctx context.Context, id string, senderID string) error {
-	return p.pollRepo.UpdateByID(ctx, id, func(poll *domain.Poll) error {
+// GetResults returns the poll with votes if its results visibility allows the user to see them,
+// otherwise ResultsHiddenError with count of voters.
+func (p *Poll) GetResults(ctx context.Context, id string, userID string) (*PollResults, error) {
+	poll, err := p.GetPollByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	voted := false
+	if poll.ResultsVisibility == domain.ResultsAfterVote && userID != poll.Author {
+		_, err = p.answerRepo.GetByUserAndPoll(ctx, userID, id)
+		if err != nil && !errors.Is(err, ErrAnswerNotFound) {
+			return nil, fmt.Errorf("could not get answer: %w", err)
+		}
+		voted = err == nil
+	}
+	if !poll.ResultsVisibleTo(userID, voted) {
+		return nil, &ResultsHiddenError{Visibility: poll.ResultsVisibility, Voters: poll.Voters()}
+	}
+	return newPollResults(poll), nil
+}
+
+// ClosePollByID closes the poll on request of its author. Poll with a quorum can't be closed
+// before the quorum is reached, unless forced, QuorumNotReachedError is returned then.
+func (p *Poll) ClosePollByID(
+	ctx context.Context, id string, senderID string, opts CloseOptions,
+) (*PollResults, error) {
+	poll, err := p.pollRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if poll.Author != senderID {
+		return nil, ErrUserIsNotPollAuthor
+	}
+	members := 0
+	if !opts.Force && opts.Members != nil {
+		if members, err = opts.Members(ctx, poll); err != nil {
+			return nil, fmt.Errorf("could not count members: %w", err)
+		}
+	}
+
+	var closed *domain.Poll
+	if err = p.pollRepo.UpdateByID(ctx, id, func(poll *domain.Poll) error {
 		if poll.Author != senderID {
 			return ErrUserIsNotPollAuthor
 		}
+		if !opts.Force && !poll.QuorumReached(members) {
+			return &QuorumNotReachedError{Voters: poll.Voters(), Required: poll.Quorum.Required(members)}
+		}
+		poll.IsActive = false
+		closed = poll
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	p.logger.InfoContext(ctx, "Poll closed", "poll_id", id, "force", opts.Force)
+	p.recordEvent(ctx, domain.NewPollEvent(id, domain.PollClosed, senderID))
+	return newPollResults(closed), nil
+}
+
+// ExpiredPolls returns active polls which have to be closed automatically.
+func (p *Poll) ExpiredPolls(ctx context.Context, now time.Time) ([]*domain.Poll, error) {
+	polls, err := p.pollRepo.ListActive(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not list active polls: %w", err)
+	}
+	return slices.DeleteFunc(polls, func(poll *domain.Poll) bool {
+		return !poll.Expired(now)
+	}), nil
+}
+
+// ExpirePoll closes the expired poll. If its quorum is not reached, results are marked as not valid.
+func (p *Poll) ExpirePoll(ctx context.Context, id string, now time.Time, members int) (*PollResults, error) {
+	var closed *domain.Poll
+	if err := p.pollRepo.UpdateByID(ctx, id, func(poll *domain.Poll) error {
+		if !poll.Expired(now) {
+			// closed or extended after the poll was listed
+			return ErrPollIsNotActive
+		}
 		poll.IsActive = false
+		poll.QuorumNotReached = !poll.QuorumReached(members)
+		closed = poll
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	p.logger.InfoContext(ctx, "Poll expired", "poll_id", id, "quorum_not_reached", closed.QuorumNotReached)
+	p.recordEvent(ctx, domain.NewPollEvent(id, domain.PollExpired, ""))
+	return newPollResults(closed), nil
+}
+
+// ReopenPoll makes the closed poll active again, keeping its votes. A deadline which has passed is removed.
+// Deleted polls can't be reopened, and the author must not exceed the limit of active polls.
+func (p *Poll) ReopenPoll(ctx context.Context, id string, sender Sender) error {
+	poll, err := p.managedPoll(ctx, id, sender)
+	if err != nil {
+		if errors.Is(err, ErrPollNotFound) {
+			return p.deletedOr(ctx, id, err)
+		}
+		return err
+	}
+	if !poll.IsActive {
+		// the reopened poll counts against the limit of its author, like a created one
+		if err = p.checkActiveLimit(ctx, poll.Author); err != nil {
+			return err
+		}
+	}
+	if err = p.pollRepo.UpdateByID(ctx, id, func(poll *domain.Poll) error {
+		if poll.IsActive {
+			return ErrPollIsActive
+		}
+		poll.IsActive = true
+		poll.QuorumNotReached = false
+		if !poll.ClosesAt.IsZero() && !poll.ClosesAt.After(time.Now()) {
+			poll.ClosesAt = time.Time{}
+		}
 		return nil
-	})
+	}); err != nil {
+		if errors.Is(err, ErrPollNotFound) {
+			return p.deletedOr(ctx, id, err)
+		}
+		return err
+	}
+	p.logger.InfoContext(ctx, "Poll reopened", "poll_id", id, "user_id", sender.ID)
+	p.recordEvent(ctx, domain.NewPollEvent(id, domain.PollReopened, sender.ID))
+	return nil
+}
+
+// ExtendPoll moves the deadline of the active poll by d. If the deadline has passed already, d is counted from now.
+func (p *Poll) ExtendPoll(ctx context.Context, id string, sender Sender, d time.Duration) (*domain.Poll, error) {
+	if d <= 0 {
+		return nil, ErrInvalidDuration
+	}
+	if _, err := p.managedPoll(ctx, id, sender); err != nil {
+		if errors.Is(err, ErrPollNotFound) {
+			return nil, p.deletedOr(ctx, id, err)
+		}
+		return nil, err
+	}
+	var extended *domain.Poll
+	if err := p.pollRepo.UpdateByID(ctx, id, func(poll *domain.Poll) error {
+		if !poll.IsActive {
+			return ErrPollIsNotActive
+		}
+		if poll.ClosesAt.IsZero() {
+			return ErrPollHasNoDeadline
+		}
+		if now := time.Now(); poll.ClosesAt.Before(now) {
+			poll.ClosesAt = now
+		}
+		poll.ClosesAt = poll.ClosesAt.Add(d)
+		extended = poll
+		return nil
+	}); err != nil {
+		if errors.Is(err, ErrPollNotFound) {
+			return nil, p.deletedOr(ctx, id, err)
+		}
+		return nil, err
+	}
+	p.logger.InfoContext(ctx, "Poll extended", "poll_id", id, "user_id", sender.ID, "closes_at", extended.ClosesAt)
+	event := domain.NewPollEvent(id, domain.PollExtended, sender.ID)
+	event.Details = extended.ClosesAt.UTC().Format(time.RFC3339)
+	p.recordEvent(ctx, event)
+	return extended, nil
+}
+
+// AddOption adds an option to the active poll. IDs of other options and votes for them are not changed.
+func (p *Poll) AddOption(ctx context.Context, id string, sender Sender, text string) (*domain.PollOption, error) {
+	if _, err := p.managedPoll(ctx, id, sender); err != nil {
+		return nil, err
+	}
+	limits := p.config().PollLimits
+	var added domain.PollOption
+	if err := p.pollRepo.UpdateByID(ctx, id, func(poll *domain.Poll) error {
+		if !poll.IsActive {
+			return ErrPollIsNotActive
+		}
+		option, err := poll.AddOption(text, limits)
+		if err != nil {
+			return err
+		}
+		added = *option
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	p.logger.InfoContext(ctx, "Option added", "poll_id", id, "option_id", added.ID, "user_id", sender.ID)
+	return &added, nil
+}
+
+// EditOption changes the text of the option, e.g. to fix a typo. Votes for the option are kept.
+func (p *Poll) EditOption(ctx context.Context, id string, sender Sender, optionID int, text string) error {
+	if _, err := p.managedPoll(ctx, id, sender); err != nil {
+		return err
+	}
+	limits := p.config().PollLimits
+	if err := p.pollRepo.UpdateByID(ctx, id, func(poll *domain.Poll) error {
+		if err := poll.EditOption(optionID, text, limits); err != nil {
+			if errors.Is(err, domain.ErrOptionNotFound) {
+				return ErrNoSuchOption
+			}
+			return err
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	p.logger.InfoContext(ctx, "Option edited", "poll_id", id, "option_id", optionID, "user_id", sender.ID)
+	return nil
+}
+
+// RemoveOption removes the option from the active poll. Votes for it are moved to another option
+// or discarded, according to opts. Returns count of affected votes.
+func (p *Poll) RemoveOption(
+	ctx context.Context,
+	id string,
+	sender Sender,
+	optionID int,
+	opts RemoveOptionOptions,
+) (int, error) {
+	if _, err := p.managedPoll(ctx, id, sender); err != nil {
+		return 0, err
+	}
+	rating := false
+	if err := p.pollRepo.UpdateByID(ctx, id, func(poll *domain.Poll) error {
+		rating = poll.Type == domain.PollRating
+		if !poll.IsActive {
+			return ErrPollIsNotActive
+		}
+		if _, err := poll.RemoveOption(optionID, opts.MoveTo); err != nil {
+			if errors.Is(err, domain.ErrOptionNotFound) {
+				return ErrNoSuchOption
+			}
+			return err
+		}
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+	if rating {
+		// answers keep scores of other options, the removed one is not counted anymore
+		p.logger.InfoContext(ctx, "Option removed", "poll_id", id, "option_id", optionID, "user_id", sender.ID)
+		return 0, nil
+	}
+
+	// no need for a transaction, the option is not in the poll anymore, so nobody can vote for it
+	var votes int
+	var err error
+	if opts.MoveTo != nil {
+		votes, err = p.answerRepo.MoveToOption(ctx, id, optionID, *opts.MoveTo)
+	} else {
+		votes, err = p.answerRepo.DeleteByOption(ctx, id, optionID)
+	}
+	if err != nil {
+		return votes, fmt.Errorf("could not update answers for removed option: %w", err)
+	}
+	p.logger.InfoContext(ctx, "Option removed", "poll_id", id, "option_id", optionID, "user_id", sender.ID,
+		"votes", votes, "moved", opts.MoveTo != nil)
+	return votes, nil
 }
 
 func (p *Poll) DeletePollByID(ctx context.Context, id string, senderID string) error {
@@ -109,15 +616,155 @@ func (p *Poll) DeletePollByID(ctx context.Context, id string, senderID string) e
 	if err = p.answerRepo.DeleteByPoll(ctx, id); err != nil {
 		return fmt.Errorf("could not delete poll answers: %w", err)
 	}
+	p.logger.InfoContext(ctx, "Poll deleted", "poll_id", id)
+	p.recordEvent(ctx, domain.NewPollEvent(id, domain.PollDeleted, senderID))
+	return nil
+}
+
+// RemindPoll returns members of the poll's channel who have not voted and have not opted out of reminders.
+// Only the author can remind, time of the reminder is saved for the schedule. Members are listed
+// by listMembers only after the sender is allowed to remind, since it takes a request per page of members.
+func (p *Poll) RemindPoll(
+	ctx context.Context, id string, senderID string, listMembers func(ctx context.Context) ([]string, error),
+) ([]string, error) {
+	poll, err := p.pollRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if poll.Author != senderID {
+		return nil, ErrUserIsNotPollAuthor
+	}
+	if !poll.IsActive {
+		return nil, ErrPollIsNotActive
+	}
+	members, err := listMembers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not list members: %w", err)
+	}
+
+	// checked again, the poll could be closed while members were listed
+	if err = p.pollRepo.UpdateByID(ctx, id, func(poll *domain.Poll) error {
+		if poll.Author != senderID {
+			return ErrUserIsNotPollAuthor
+		}
+		if !poll.IsActive {
+			return ErrPollIsNotActive
+		}
+		poll.RemindedAt = time.Now()
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	answers, err := p.answerRepo.ListByPoll(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("could not list answers: %w", err)
+	}
+	optedOut, err := p.dndRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not list users who opted out of reminders: %w", err)
+	}
+
+	skip := make(map[string]struct{}, len(answers)+len(optedOut))
+	for _, answer := range answers {
+		skip[answer.UserID] = struct{}{}
+	}
+	for _, userID := range optedOut {
+		skip[userID] = struct{}{}
+	}
+	var users []string
+	for _, userID := range members {
+		if _, ok := skip[userID]; !ok {
+			users = append(users, userID)
+			skip[userID] = struct{}{}
+		}
+	}
+	p.logger.InfoContext(ctx, "Poll reminder", "poll_id", id, "members", len(members), "reminded", len(users))
+	return users, nil
+}
+
+// PollsToRemind returns active polls whose scheduled reminder is due.
+func (p *Poll) PollsToRemind(ctx context.Context, now time.Time) ([]*domain.Poll, error) {
+	polls, err := p.pollRepo.ListActive(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not list active polls: %w", err)
+	}
+	return slices.DeleteFunc(polls, func(poll *domain.Poll) bool {
+		return !poll.ReminderDue(now)
+	}), nil
+}
+
+// SetDoNotDisturb opts the user out of reminders, or back in.
+func (p *Poll) SetDoNotDisturb(ctx context.Context, userID string, enabled bool) error {
+	if userID == "" {
+		return ErrInvalidUserID
+	}
+	var err error
+	if enabled {
+		err = p.dndRepo.Add(ctx, userID)
+	} else {
+		err = p.dndRepo.Remove(ctx, userID)
+	}
+	if err != nil {
+		return fmt.Errorf("could not save do not disturb: %w", err)
+	}
+	p.logger.InfoContext(ctx, "Do not disturb changed", "user_id", userID, "enabled", enabled)
 	return nil
 }
 
-func (p *Poll) isPollActive(ctx context.Context, pollID string) (bool, error) {
-	poll, err := p.pollRepo.GetByID(ctx, pollID)
+// managedPoll returns the poll if the sender can manage it, see Sender.authorize.
+// Author and channel of a poll never change, so the poll can be updated after the check.
+func (p *Poll) managedPoll(ctx context.Context, id string, sender Sender) (*domain.Poll, error) {
+	poll, err := p.pollRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve poll: %w", err)
+	}
+	if err = sender.authorize(ctx, poll.Author, poll.ChannelID); err != nil {
+		return nil, err
+	}
+	return poll, nil
+}
+
+// recordEvent saves the lifecycle event. The change is done already, so a failure is only logged.
+func (p *Poll) recordEvent(ctx context.Context, event *domain.PollEvent) {
+	if err := p.eventRepo.Save(ctx, event); err != nil {
+		p.logger.WarnContext(ctx, "Could not save poll event", "poll_id", event.PollID, "type", event.Type, "error", err)
+	}
+}
+
+// deletedOr returns ErrPollDeleted if the missing poll was deleted, otherwise err.
+func (p *Poll) deletedOr(ctx context.Context, id string, err error) error {
+	events, listErr := p.eventRepo.ListByPoll(ctx, id)
+	if listErr != nil {
+		return fmt.Errorf("could not list poll events: %w", listErr)
+	}
+	for _, event := range events {
+		if event.Type == domain.PollDeleted {
+			return ErrPollDeleted
+		}
+	}
+	return err
+}
+
+// countVote adds the answer with its weight to counters of the poll:
+// votes of the chosen option or histograms of rated options.
+func countVote(poll *domain.Poll, answer *domain.Answer) error {
+	if poll.Type == domain.PollRating {
+		if err := poll.Rate(answer.Scores, answer.Weight); err != nil {
+			if errors.Is(err, domain.ErrOptionNotFound) {
+				return ErrNoSuchOption
+			}
+			return err
+		}
+		return nil
+	}
+	option, err := poll.Option(answer.OptionID)
 	if err != nil {
-		return false, fmt.Errorf("could not retrieve poll: %w", err)
+		return ErrNoSuchOption
 	}
-	return poll.IsActive, nil
+	option.Votes++
+	option.Weight += answer.Weight
+	return nil
 }
 
 func validateAnswer(answer *domain.Answer) error {
@@ -127,7 +774,7 @@ func validateAnswer(answer *domain.Answer) error {
 	if answer.UserID == "" {
 		return ErrInvalidUserID
 	}
-	if answer.Vote < 0 {
+	if answer.OptionID < 0 {
 		return ErrNoSuchOption
 	}
 	return nil
@@ -0,0 +1,95 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Xausdorf/mattermost-poll/internal/domain"
+)
+
+func TestCountVote(t *testing.T) {
+	tests := []struct {
+		name       string
+		poll       domain.Poll
+		answers    []domain.Answer
+		wantErr    error
+		wantVotes  []int
+		wantWeight []int
+	}{
+		{
+			name: "choice",
+			poll: domain.Poll{Options: []domain.PollOption{{ID: 0}, {ID: 1}}},
+			answers: []domain.Answer{
+				{UserID: "u1", OptionID: 1, Weight: 1},
+				{UserID: "u2", OptionID: 1, Weight: 1},
+			},
+			wantVotes:  []int{0, 2},
+			wantWeight: []int{0, 2},
+		},
+		{
+			name: "weighted choice",
+			poll: domain.Poll{Weighted: true, Options: []domain.PollOption{{ID: 0}, {ID: 1}}},
+			answers: []domain.Answer{
+				{UserID: "u1", OptionID: 0, Weight: 3},
+				{UserID: "u2", OptionID: 1, Weight: 1},
+				{UserID: "u3", OptionID: 0, Weight: 2},
+			},
+			wantVotes:  []int{2, 1},
+			wantWeight: []int{5, 1},
+		},
+		{
+			name: "weighted rating",
+			poll: domain.Poll{
+				Type:     domain.PollRating,
+				Scale:    domain.DefaultScale,
+				Weighted: true,
+				Options:  []domain.PollOption{{ID: 0}, {ID: 1}},
+			},
+			answers: []domain.Answer{
+				{UserID: "u1", Scores: map[int]int{0: 5, 1: 1}, Weight: 4},
+			},
+			wantVotes:  []int{1, 1},
+			wantWeight: []int{4, 4},
+		},
+		{
+			name:       "unknown option",
+			poll:       domain.Poll{Options: []domain.PollOption{{ID: 0}, {ID: 1}}},
+			answers:    []domain.Answer{{UserID: "u1", OptionID: 2, Weight: 1}},
+			wantErr:    ErrNoSuchOption,
+			wantVotes:  []int{0, 0},
+			wantWeight: []int{0, 0},
+		},
+		{
+			name: "unknown rated option",
+			poll: domain.Poll{
+				Type:    domain.PollRating,
+				Scale:   domain.DefaultScale,
+				Options: []domain.PollOption{{ID: 0}, {ID: 1}},
+			},
+			answers:    []domain.Answer{{UserID: "u1", Scores: map[int]int{0: 5, 2: 1}, Weight: 1}},
+			wantErr:    ErrNoSuchOption,
+			wantVotes:  []int{0, 0},
+			wantWeight: []int{0, 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			poll := tt.poll
+			var err error
+			for i := range tt.answers {
+				if err = countVote(&poll, &tt.answers[i]); err != nil {
+					break
+				}
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("countVote() error = %v, want %v", err, tt.wantErr)
+			}
+			for i, option := range poll.Options {
+				if option.Votes != tt.wantVotes[i] || option.Weight != tt.wantWeight[i] {
+					t.Errorf("option %d: votes %d, weight %d, want %d, %d",
+						option.ID, option.Votes, option.Weight, tt.wantVotes[i], tt.wantWeight[i])
+				}
+			}
+		})
+	}
+}
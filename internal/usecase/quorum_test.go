@@ -0,0 +1,179 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Xausdorf/mattermost-poll/internal/domain"
+	"github.com/Xausdorf/mattermost-poll/internal/usecase"
+)
+
+func TestClosePollByIDQuorum(t *testing.T) {
+	tests := []struct {
+		name       string
+		quorum     domain.Quorum
+		members    int
+		force      bool
+		wantErr    error
+		wantActive bool
+	}{
+		{name: "no quorum", members: 10},
+		{name: "count reached", quorum: domain.Quorum{Count: 3}},
+		{name: "count not reached", quorum: domain.Quorum{Count: 4}, wantErr: usecase.ErrQuorumNotReached, wantActive: true},
+		{name: "percent reached", quorum: domain.Quorum{Percent: 50}, members: 6},
+		{
+			name:       "percent not reached",
+			quorum:     domain.Quorum{Percent: 50},
+			members:    7,
+			wantErr:    usecase.ErrQuorumNotReached,
+			wantActive: true,
+		},
+		{name: "forced", quorum: domain.Quorum{Count: 4}, force: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			polls := newPollRepository(domain.Poll{
+				ID:       "p1",
+				Author:   "author",
+				IsActive: true,
+				Quorum:   tt.quorum,
+				Options:  []domain.PollOption{{ID: 0, Votes: 2}, {ID: 1, Votes: 1}},
+			})
+			uc := usecase.NewPoll(polls, nil, nil, &eventRepository{}, nil, nil, nil, nil,
+				usecase.Config{}, discardLogger())
+			opts := usecase.CloseOptions{
+				Force: tt.force,
+				Members: func(context.Context, *domain.Poll) (int, error) {
+					return tt.members, nil
+				},
+			}
+
+			_, err := uc.ClosePollByID(context.Background(), "p1", "author", opts)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ClosePollByID() error = %v, want %v", err, tt.wantErr)
+			}
+			if active := polls.get("p1").IsActive; active != tt.wantActive {
+				t.Errorf("poll is active = %v, want %v", active, tt.wantActive)
+			}
+		})
+	}
+}
+
+func TestClosePollByIDQuorumNotReachedError(t *testing.T) {
+	polls := newPollRepository(domain.Poll{
+		ID:       "p1",
+		Author:   "author",
+		IsActive: true,
+		Quorum:   domain.Quorum{Percent: 50},
+		Options:  []domain.PollOption{{ID: 0, Votes: 2}, {ID: 1, Votes: 1}},
+	})
+	uc := usecase.NewPoll(polls, nil, nil, &eventRepository{}, nil, nil, nil, nil,
+		usecase.Config{}, discardLogger())
+	opts := usecase.CloseOptions{
+		Members: func(context.Context, *domain.Poll) (int, error) { return 10, nil },
+	}
+
+	_, err := uc.ClosePollByID(context.Background(), "p1", "author", opts)
+	var quorumErr *usecase.QuorumNotReachedError
+	if !errors.As(err, &quorumErr) {
+		t.Fatalf("ClosePollByID() error = %v, want QuorumNotReachedError", err)
+	}
+	if quorumErr.Voters != 3 || quorumErr.Required != 5 {
+		t.Errorf("voters %d of %d, want 3 of 5", quorumErr.Voters, quorumErr.Required)
+	}
+}
+
+func TestClosePollByIDCountsMembersOnlyForAuthor(t *testing.T) {
+	tests := []struct {
+		name        string
+		senderID    string
+		force       bool
+		wantErr     error
+		wantCounted bool
+	}{
+		{name: "author", senderID: "author", wantCounted: true},
+		{name: "forced by author", senderID: "author", force: true},
+		{name: "not author", senderID: "user", wantErr: usecase.ErrUserIsNotPollAuthor},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			polls := newPollRepository(domain.Poll{
+				ID:       "p1",
+				Author:   "author",
+				IsActive: true,
+				Quorum:   domain.Quorum{Percent: 10},
+				Options:  []domain.PollOption{{ID: 0, Votes: 2}},
+			})
+			uc := usecase.NewPoll(polls, nil, nil, &eventRepository{}, nil, nil, nil, nil,
+				usecase.Config{}, discardLogger())
+			counted := false
+			opts := usecase.CloseOptions{
+				Force: tt.force,
+				Members: func(context.Context, *domain.Poll) (int, error) {
+					counted = true
+					return 10, nil
+				},
+			}
+
+			if _, err := uc.ClosePollByID(context.Background(), "p1", tt.senderID, opts); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ClosePollByID() error = %v, want %v", err, tt.wantErr)
+			}
+			if counted != tt.wantCounted {
+				t.Errorf("members counted = %v, want %v", counted, tt.wantCounted)
+			}
+		})
+	}
+}
+
+func TestExpirePollQuorum(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name           string
+		quorum         domain.Quorum
+		members        int
+		wantNotReached bool
+	}{
+		{name: "no quorum", members: 100},
+		{name: "count reached", quorum: domain.Quorum{Count: 3}},
+		{name: "count not reached", quorum: domain.Quorum{Count: 4}, wantNotReached: true},
+		{name: "percent reached", quorum: domain.Quorum{Percent: 30}, members: 10},
+		{name: "percent not reached", quorum: domain.Quorum{Percent: 31}, members: 10, wantNotReached: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			polls := newPollRepository(domain.Poll{
+				ID:       "p1",
+				Author:   "author",
+				IsActive: true,
+				ClosesAt: now,
+				Quorum:   tt.quorum,
+				Options:  []domain.PollOption{{ID: 0, Votes: 2}, {ID: 1, Votes: 1}},
+			})
+			uc := usecase.NewPoll(polls, nil, nil, &eventRepository{}, nil, nil, nil, nil,
+				usecase.Config{}, discardLogger())
+
+			closed, err := uc.ExpirePoll(context.Background(), "p1", now, tt.members)
+			if err != nil {
+				t.Fatalf("ExpirePoll() error: %v", err)
+			}
+			stored := polls.get("p1")
+			if stored.IsActive || closed.Poll.IsActive {
+				t.Error("expired poll is still active")
+			}
+			if stored.QuorumNotReached != tt.wantNotReached || closed.Poll.QuorumNotReached != tt.wantNotReached {
+				t.Errorf("quorum not reached = %v, want %v", stored.QuorumNotReached, tt.wantNotReached)
+			}
+		})
+	}
+
+	t.Run("not expired yet", func(t *testing.T) {
+		polls := newPollRepository(domain.Poll{ID: "p1", IsActive: true, ClosesAt: now.Add(time.Minute)})
+		uc := usecase.NewPoll(polls, nil, nil, &eventRepository{}, nil, nil, nil, nil,
+			usecase.Config{}, discardLogger())
+		if _, err := uc.ExpirePoll(context.Background(), "p1", now, 0); !errors.Is(err, usecase.ErrPollIsNotActive) {
+			t.Errorf("ExpirePoll() error = %v, want %v", err, usecase.ErrPollIsNotActive)
+		}
+	})
+}
@@ -0,0 +1,64 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/Xausdorf/mattermost-poll/internal/domain"
+	"github.com/Xausdorf/mattermost-poll/internal/usecase"
+)
+
+func TestRemindPoll(t *testing.T) {
+	tests := []struct {
+		name       string
+		sender     string
+		active     bool
+		wantErr    error
+		wantListed bool
+		wantUsers  []string
+	}{
+		{
+			name:       "author",
+			sender:     "author",
+			active:     true,
+			wantListed: true,
+			wantUsers:  []string{"u3"},
+		},
+		{name: "not author", sender: "u2", active: true, wantErr: usecase.ErrUserIsNotPollAuthor},
+		{name: "closed poll", sender: "author", wantErr: usecase.ErrPollIsNotActive},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			polls := newPollRepository(domain.Poll{
+				ID:       "p1",
+				Author:   "author",
+				IsActive: tt.active,
+				Options:  []domain.PollOption{{ID: 0, Votes: 1}, {ID: 1}},
+			})
+			answers := &answerRepository{answers: []domain.Answer{{UserID: "u1", PollID: "p1"}}}
+			dnd := &doNotDisturbRepository{users: []string{"u2"}}
+			uc := usecase.NewPoll(polls, answers, dnd, &eventRepository{}, nil, nil, nil, nil,
+				usecase.Config{}, discardLogger())
+
+			listed := false
+			users, err := uc.RemindPoll(context.Background(), "p1", tt.sender, func(context.Context) ([]string, error) {
+				listed = true
+				return []string{"u1", "u2", "u3", "u3"}, nil
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RemindPoll() error = %v, want %v", err, tt.wantErr)
+			}
+			if listed != tt.wantListed {
+				t.Errorf("members listed = %v, want %v", listed, tt.wantListed)
+			}
+			if !slices.Equal(users, tt.wantUsers) {
+				t.Errorf("RemindPoll() = %v, want %v", users, tt.wantUsers)
+			}
+			if remindedAt := polls.get("p1").RemindedAt; remindedAt.IsZero() != (tt.wantErr != nil) {
+				t.Errorf("reminded at %v after error %v", remindedAt, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,410 @@
+package usecase_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/Xausdorf/mattermost-poll/internal/domain"
+	"github.com/Xausdorf/mattermost-poll/internal/usecase"
+)
+
+// pollRepository - in-memory usecase.PollRepository.
+type pollRepository struct {
+	mu    sync.Mutex
+	polls map[string]domain.Poll
+}
+
+func newPollRepository(polls ...domain.Poll) *pollRepository {
+	r := &pollRepository{polls: make(map[string]domain.Poll)}
+	for _, poll := range polls {
+		r.polls[poll.ID] = poll
+	}
+	return r
+}
+
+func (r *pollRepository) Save(_ context.Context, poll *domain.Poll) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.polls[poll.ID] = *poll
+	return nil
+}
+
+func (r *pollRepository) UpdateByID(_ context.Context, id string, updateFn func(poll *domain.Poll) error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	poll, ok := r.polls[id]
+	if !ok {
+		return usecase.ErrPollNotFound
+	}
+	poll.Options = slices.Clone(poll.Options)
+	if err := updateFn(&poll); err != nil {
+		return err
+	}
+	r.polls[id] = poll
+	return nil
+}
+
+func (r *pollRepository) GetByID(_ context.Context, id string) (*domain.Poll, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	poll, ok := r.polls[id]
+	if !ok {
+		return nil, usecase.ErrPollNotFound
+	}
+	poll.Options = slices.Clone(poll.Options)
+	return &poll, nil
+}
+
+func (r *pollRepository) DeleteByID(_ context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.polls, id)
+	return nil
+}
+
+func (r *pollRepository) CountActiveByAuthor(_ context.Context, author string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	count := 0
+	for _, poll := range r.polls {
+		if poll.IsActive && poll.Author == author {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *pollRepository) ListActive(_ context.Context) ([]*domain.Poll, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var polls []*domain.Poll
+	for _, poll := range r.polls {
+		if poll.IsActive {
+			polls = append(polls, &poll)
+		}
+	}
+	return polls, nil
+}
+
+func (r *pollRepository) get(id string) domain.Poll {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.polls[id]
+}
+
+// eventRepository - in-memory usecase.EventRepository.
+type eventRepository struct {
+	mu     sync.Mutex
+	events []*domain.PollEvent
+}
+
+func (r *eventRepository) Save(_ context.Context, event *domain.PollEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+	return nil
+}
+
+func (r *eventRepository) ListByPoll(_ context.Context, pollID string) ([]*domain.PollEvent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var events []*domain.PollEvent
+	for _, event := range r.events {
+		if event.PollID == pollID {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+// answerRepository - in-memory usecase.AnswerRepository.
+type answerRepository struct {
+	mu      sync.Mutex
+	answers []domain.Answer
+}
+
+func (r *answerRepository) Save(_ context.Context, answer *domain.Answer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.answers = append(r.answers, *answer)
+	return nil
+}
+
+func (r *answerRepository) GetByUserAndPoll(_ context.Context, userID string, pollID string) (*domain.Answer, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, answer := range r.answers {
+		if answer.UserID == userID && answer.PollID == pollID {
+			return &answer, nil
+		}
+	}
+	return nil, usecase.ErrAnswerNotFound
+}
+
+func (r *answerRepository) ListByPoll(_ context.Context, pollID string) ([]*domain.Answer, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var answers []*domain.Answer
+	for _, answer := range r.answers {
+		if answer.PollID == pollID {
+			answers = append(answers, &answer)
+		}
+	}
+	return answers, nil
+}
+
+func (r *answerRepository) DeleteByPoll(_ context.Context, pollID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.answers = slices.DeleteFunc(r.answers, func(answer domain.Answer) bool { return answer.PollID == pollID })
+	return nil
+}
+
+func (r *answerRepository) DeleteByOption(_ context.Context, pollID string, optionID int) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	count := len(r.answers)
+	r.answers = slices.DeleteFunc(r.answers, func(answer domain.Answer) bool {
+		return answer.PollID == pollID && answer.OptionID == optionID
+	})
+	return count - len(r.answers), nil
+}
+
+func (r *answerRepository) MoveToOption(_ context.Context, pollID string, from int, to int) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	moved := 0
+	for i := range r.answers {
+		if r.answers[i].PollID == pollID && r.answers[i].OptionID == from {
+			r.answers[i].OptionID = to
+			moved++
+		}
+	}
+	return moved, nil
+}
+
+// doNotDisturbRepository - in-memory usecase.DoNotDisturbRepository.
+type doNotDisturbRepository struct {
+	mu    sync.Mutex
+	users []string
+}
+
+func (r *doNotDisturbRepository) Add(_ context.Context, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !slices.Contains(r.users, userID) {
+		r.users = append(r.users, userID)
+	}
+	return nil
+}
+
+func (r *doNotDisturbRepository) Remove(_ context.Context, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.users = slices.DeleteFunc(r.users, func(id string) bool { return id == userID })
+	return nil
+}
+
+func (r *doNotDisturbRepository) List(_ context.Context) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.users), nil
+}
+
+// surveyRepository - in-memory usecase.SurveyRepository.
+type surveyRepository struct {
+	mu      sync.Mutex
+	surveys map[string]domain.Survey
+}
+
+func newSurveyRepository(surveys ...domain.Survey) *surveyRepository {
+	r := &surveyRepository{surveys: make(map[string]domain.Survey)}
+	for _, survey := range surveys {
+		r.surveys[survey.ID] = survey
+	}
+	return r
+}
+
+func (r *surveyRepository) Save(_ context.Context, survey *domain.Survey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.surveys[survey.ID] = *survey
+	return nil
+}
+
+func (r *surveyRepository) GetByID(_ context.Context, id string) (*domain.Survey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	survey, ok := r.surveys[id]
+	if !ok {
+		return nil, usecase.ErrSurveyNotFound
+	}
+	return &survey, nil
+}
+
+// responseRepository - in-memory usecase.SurveyResponseRepository, responses are copied on save and on read.
+type responseRepository struct {
+	mu        sync.Mutex
+	responses []domain.SurveyResponse
+}
+
+func cloneResponse(response domain.SurveyResponse) *domain.SurveyResponse {
+	response.Answers = maps.Clone(response.Answers)
+	return &response
+}
+
+func (r *responseRepository) Save(_ context.Context, response *domain.SurveyResponse) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	saved := *cloneResponse(*response)
+	for i := range r.responses {
+		if r.responses[i].ID == response.ID {
+			r.responses[i] = saved
+			return nil
+		}
+	}
+	r.responses = append(r.responses, saved)
+	return nil
+}
+
+func (r *responseRepository) GetByUserAndSurvey(
+	_ context.Context, userID string, surveyID string,
+) (*domain.SurveyResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, response := range r.responses {
+		if response.UserID == userID && response.SurveyID == surveyID {
+			return cloneResponse(response), nil
+		}
+	}
+	return nil, usecase.ErrSurveyResponseNotFound
+}
+
+func (r *responseRepository) ListInProgressByUser(_ context.Context, userID string) ([]*domain.SurveyResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var responses []*domain.SurveyResponse
+	for _, response := range r.responses {
+		if response.UserID == userID && !response.Completed {
+			responses = append(responses, cloneResponse(response))
+		}
+	}
+	return responses, nil
+}
+
+func (r *responseRepository) ListBySurvey(_ context.Context, surveyID string) ([]*domain.SurveyResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var responses []*domain.SurveyResponse
+	for _, response := range r.responses {
+		if response.SurveyID == surveyID {
+			responses = append(responses, cloneResponse(response))
+		}
+	}
+	return responses, nil
+}
+
+// textAnswerRepository - in-memory usecase.TextAnswerRepository, saving fails with err if it is set.
+type textAnswerRepository struct {
+	mu      sync.Mutex
+	answers []domain.TextAnswer
+	err     error
+}
+
+func (r *textAnswerRepository) Save(_ context.Context, answer *domain.TextAnswer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return r.err
+	}
+	for i := range r.answers {
+		if r.answers[i].ID == answer.ID {
+			r.answers[i] = *answer
+			return nil
+		}
+	}
+	r.answers = append(r.answers, *answer)
+	return nil
+}
+
+func (r *textAnswerRepository) GetByID(_ context.Context, id string) (*domain.TextAnswer, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, answer := range r.answers {
+		if answer.ID == id {
+			return &answer, nil
+		}
+	}
+	return nil, usecase.ErrTextAnswerNotFound
+}
+
+func (r *textAnswerRepository) ListBySurvey(_ context.Context, surveyID string) ([]*domain.TextAnswer, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var answers []*domain.TextAnswer
+	for _, answer := range r.answers {
+		if answer.SurveyID == surveyID {
+			answers = append(answers, &answer)
+		}
+	}
+	return answers, nil
+}
+
+// suggestionRepository - in-memory usecase.SuggestionRepository.
+type suggestionRepository struct {
+	mu          sync.Mutex
+	suggestions []domain.Suggestion
+}
+
+func (r *suggestionRepository) Save(_ context.Context, suggestion *domain.Suggestion) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.suggestions {
+		if r.suggestions[i].ID == suggestion.ID {
+			r.suggestions[i] = *suggestion
+			return nil
+		}
+	}
+	r.suggestions = append(r.suggestions, *suggestion)
+	return nil
+}
+
+func (r *suggestionRepository) GetByID(_ context.Context, id string) (*domain.Suggestion, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, suggestion := range r.suggestions {
+		if suggestion.ID == id {
+			return &suggestion, nil
+		}
+	}
+	return nil, usecase.ErrSuggestionNotFound
+}
+
+func (r *suggestionRepository) ListByPoll(_ context.Context, pollID string) ([]*domain.Suggestion, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var suggestions []*domain.Suggestion
+	for _, suggestion := range r.suggestions {
+		if suggestion.PollID == pollID {
+			suggestions = append(suggestions, &suggestion)
+		}
+	}
+	return suggestions, nil
+}
+
+// roles - usecase.Roles of a user who is an admin of the listed channels.
+type roles struct {
+	adminOf []string
+}
+
+func (r roles) IsAdmin(_ context.Context, channelID string) (bool, error) {
+	return slices.Contains(r.adminOf, channelID), nil
+}
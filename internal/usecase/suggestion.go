@@ -0,0 +1,138 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Xausdorf/mattermost-poll/internal/domain"
+)
+
+// SuggestOption suggests a new option of the active poll on behalf of the user. Depending on the poll's
+// suggestion mode the option is added immediately or waits for approval of the author.
+func (p *Poll) SuggestOption(ctx context.Context, pollID string, userID string, text string) (*domain.Suggestion, error) {
+	if userID == "" {
+		return nil, ErrInvalidUserID
+	}
+	cfg := p.config()
+	poll, err := p.pollRepo.GetByID(ctx, pollID)
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve poll: %w", err)
+	}
+	if !poll.IsActive {
+		return nil, ErrPollIsNotActive
+	}
+	if poll.Suggestions == domain.SuggestionsOff {
+		return nil, ErrSuggestionsDisabled
+	}
+	if err = poll.ValidateNewOption(text, cfg.PollLimits); err != nil {
+		return nil, err
+	}
+
+	suggestions, err := p.suggestionRepo.ListByPoll(ctx, pollID)
+	if err != nil {
+		return nil, fmt.Errorf("could not list suggestions: %w", err)
+	}
+	byUser := 0
+	for _, suggestion := range suggestions {
+		if suggestion.UserID == userID {
+			byUser++
+		}
+		// rejected suggestions are kept, so the same option is not suggested to the author again
+		if suggestion.Status != domain.SuggestionApproved && domain.SameOptionText(suggestion.Text, text) {
+			return nil, ErrSuggestionExists
+		}
+	}
+	if cfg.MaxSuggestionsPerUser > 0 && byUser >= cfg.MaxSuggestionsPerUser {
+		return nil, ErrTooManySuggestions
+	}
+
+	suggestion := domain.NewSuggestion(pollID, userID, text)
+	if poll.Suggestions == domain.SuggestionsOpen {
+		if err = p.approve(ctx, suggestion); err != nil {
+			return nil, err
+		}
+	}
+	if err = p.suggestionRepo.Save(ctx, suggestion); err != nil {
+		return nil, fmt.Errorf("could not save suggestion: %w", err)
+	}
+	p.logger.InfoContext(ctx, "Option suggested", "poll_id", pollID, "user_id", userID,
+		"suggestion_id", suggestion.ID, "status", suggestion.Status)
+	return suggestion, nil
+}
+
+// PendingSuggestions returns suggestions of the poll waiting for approval, only for the author or an admin.
+func (p *Poll) PendingSuggestions(ctx context.Context, pollID string, sender Sender) ([]*domain.Suggestion, error) {
+	if _, err := p.managedPoll(ctx, pollID, sender); err != nil {
+		return nil, err
+	}
+	suggestions, err := p.suggestionRepo.ListByPoll(ctx, pollID)
+	if err != nil {
+		return nil, fmt.Errorf("could not list suggestions: %w", err)
+	}
+	pending := suggestions[:0]
+	for _, suggestion := range suggestions {
+		if suggestion.Status == domain.SuggestionPending {
+			pending = append(pending, suggestion)
+		}
+	}
+	return pending, nil
+}
+
+// ReviewSuggestion approves the pending suggestion, adding it as an option of the poll, or rejects it.
+func (p *Poll) ReviewSuggestion(
+	ctx context.Context,
+	id string,
+	sender Sender,
+	approve bool,
+) (*domain.Suggestion, error) {
+	suggestion, err := p.suggestionRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if suggestion.Status != domain.SuggestionPending {
+		return nil, ErrSuggestionReviewed
+	}
+	if _, err = p.managedPoll(ctx, suggestion.PollID, sender); err != nil {
+		return nil, err
+	}
+
+	if approve {
+		err = p.approve(ctx, suggestion)
+	} else {
+		suggestion.Status = domain.SuggestionRejected
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = p.suggestionRepo.Save(ctx, suggestion); err != nil {
+		return nil, fmt.Errorf("could not save suggestion: %w", err)
+	}
+	p.logger.InfoContext(ctx, "Suggestion reviewed", "poll_id", suggestion.PollID, "suggestion_id", id,
+		"user_id", sender.ID, "status", suggestion.Status)
+	return suggestion, nil
+}
+
+// approve adds the suggested option to the active poll. If the poll has the same option already,
+// the suggestion is approved with it.
+func (p *Poll) approve(ctx context.Context, suggestion *domain.Suggestion) error {
+	limits := p.config().PollLimits
+	return p.pollRepo.UpdateByID(ctx, suggestion.PollID, func(poll *domain.Poll) error {
+		if !poll.IsActive {
+			return ErrPollIsNotActive
+		}
+		option, err := poll.AddOption(suggestion.Text, limits)
+		if errors.Is(err, domain.ErrDuplicateOption) {
+			// the author added the same option after the suggestion
+			if existing := poll.OptionWithText(suggestion.Text); existing != nil {
+				option, err = existing, nil
+			}
+		}
+		if err != nil {
+			return err
+		}
+		suggestion.Status = domain.SuggestionApproved
+		suggestion.OptionID = option.ID
+		return nil
+	})
+}
@@ -0,0 +1,207 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Xausdorf/mattermost-poll/internal/domain"
+	"github.com/Xausdorf/mattermost-poll/internal/usecase"
+)
+
+func newSuggestionUsecase(
+	mode domain.SuggestionMode, suggestions ...domain.Suggestion,
+) (*usecase.Poll, *pollRepository, *suggestionRepository) {
+	polls := newPollRepository(domain.Poll{
+		ID:          "p1",
+		Author:      "author",
+		ChannelID:   "c1",
+		IsActive:    true,
+		Suggestions: mode,
+		Options:     []domain.PollOption{{ID: 0, Text: "Pizza"}, {ID: 1, Text: "Sushi"}},
+	})
+	repo := &suggestionRepository{suggestions: suggestions}
+	uc := usecase.NewPoll(polls, nil, nil, &eventRepository{}, repo, nil, nil, nil,
+		usecase.Config{MaxSuggestionsPerUser: 2}, discardLogger())
+	return uc, polls, repo
+}
+
+func TestSuggestOption(t *testing.T) {
+	tests := []struct {
+		name        string
+		mode        domain.SuggestionMode
+		suggestions []domain.Suggestion
+		userID      string
+		text        string
+		wantErr     error
+		wantStatus  domain.SuggestionStatus
+		wantOptions int
+	}{
+		{name: "open", mode: domain.SuggestionsOpen, userID: "u1", text: "Tacos",
+			wantStatus: domain.SuggestionApproved, wantOptions: 3},
+		{name: "approval", mode: domain.SuggestionsApproval, userID: "u1", text: "Tacos",
+			wantStatus: domain.SuggestionPending, wantOptions: 2},
+		{name: "disabled", mode: domain.SuggestionsOff, userID: "u1", text: "Tacos",
+			wantErr: usecase.ErrSuggestionsDisabled, wantOptions: 2},
+		{name: "no user", mode: domain.SuggestionsOpen, text: "Tacos",
+			wantErr: usecase.ErrInvalidUserID, wantOptions: 2},
+		{name: "existing option", mode: domain.SuggestionsOpen, userID: "u1", text: "pizza",
+			wantErr: domain.ErrDuplicateOption, wantOptions: 2},
+		{
+			name: "pending suggestion",
+			mode: domain.SuggestionsApproval,
+			suggestions: []domain.Suggestion{
+				{ID: "s1", PollID: "p1", UserID: "u2", Text: "Tacos", Status: domain.SuggestionPending},
+			},
+			userID:      "u1",
+			text:        " tacos ",
+			wantErr:     usecase.ErrSuggestionExists,
+			wantOptions: 2,
+		},
+		{
+			name: "rejected suggestion",
+			mode: domain.SuggestionsApproval,
+			suggestions: []domain.Suggestion{
+				{ID: "s1", PollID: "p1", UserID: "u2", Text: "Tacos", Status: domain.SuggestionRejected},
+			},
+			userID:      "u1",
+			text:        "Tacos",
+			wantErr:     usecase.ErrSuggestionExists,
+			wantOptions: 2,
+		},
+		{
+			name: "approved suggestion of removed option",
+			mode: domain.SuggestionsApproval,
+			suggestions: []domain.Suggestion{
+				{ID: "s1", PollID: "p1", UserID: "u2", Text: "Tacos", Status: domain.SuggestionApproved, OptionID: 2},
+			},
+			userID:      "u1",
+			text:        "Tacos",
+			wantStatus:  domain.SuggestionPending,
+			wantOptions: 2,
+		},
+		{
+			name: "too many suggestions",
+			mode: domain.SuggestionsApproval,
+			suggestions: []domain.Suggestion{
+				{ID: "s1", PollID: "p1", UserID: "u1", Text: "Tacos", Status: domain.SuggestionPending},
+				{ID: "s2", PollID: "p1", UserID: "u1", Text: "Ramen", Status: domain.SuggestionRejected},
+			},
+			userID:      "u1",
+			text:        "Pasta",
+			wantErr:     usecase.ErrTooManySuggestions,
+			wantOptions: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, polls, _ := newSuggestionUsecase(tt.mode, tt.suggestions...)
+
+			suggestion, err := uc.SuggestOption(context.Background(), "p1", tt.userID, tt.text)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SuggestOption() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && suggestion.Status != tt.wantStatus {
+				t.Errorf("status = %q, want %q", suggestion.Status, tt.wantStatus)
+			}
+			if options := len(polls.get("p1").Options); options != tt.wantOptions {
+				t.Errorf("poll has %d options, want %d", options, tt.wantOptions)
+			}
+		})
+	}
+}
+
+func TestReviewSuggestion(t *testing.T) {
+	pending := domain.Suggestion{ID: "s1", PollID: "p1", UserID: "u1", Text: "Tacos", Status: domain.SuggestionPending}
+	tests := []struct {
+		name         string
+		suggestion   domain.Suggestion
+		sender       usecase.Sender
+		approve      bool
+		wantErr      error
+		wantStatus   domain.SuggestionStatus
+		wantOptionID int
+		wantOptions  int
+	}{
+		{
+			name:         "approved by author",
+			suggestion:   pending,
+			sender:       usecase.Sender{ID: "author"},
+			approve:      true,
+			wantStatus:   domain.SuggestionApproved,
+			wantOptionID: 2,
+			wantOptions:  3,
+		},
+		{
+			name:         "approved by channel admin",
+			suggestion:   pending,
+			sender:       usecase.Sender{ID: "admin", Roles: roles{adminOf: []string{"c1"}}},
+			approve:      true,
+			wantStatus:   domain.SuggestionApproved,
+			wantOptionID: 2,
+			wantOptions:  3,
+		},
+		{
+			name:        "rejected",
+			suggestion:  pending,
+			sender:      usecase.Sender{ID: "author"},
+			wantStatus:  domain.SuggestionRejected,
+			wantOptions: 2,
+		},
+		{
+			name:        "admin of another channel",
+			suggestion:  pending,
+			sender:      usecase.Sender{ID: "admin", Roles: roles{adminOf: []string{"c2"}}},
+			approve:     true,
+			wantErr:     usecase.ErrUserIsNotPollAuthor,
+			wantStatus:  domain.SuggestionPending,
+			wantOptions: 2,
+		},
+		{
+			name: "option added by the author already",
+			suggestion: domain.Suggestion{
+				ID: "s1", PollID: "p1", UserID: "u1", Text: "SUSHI", Status: domain.SuggestionPending,
+			},
+			sender:       usecase.Sender{ID: "author"},
+			approve:      true,
+			wantStatus:   domain.SuggestionApproved,
+			wantOptionID: 1,
+			wantOptions:  2,
+		},
+		{
+			name: "reviewed already",
+			suggestion: domain.Suggestion{
+				ID: "s1", PollID: "p1", UserID: "u1", Text: "Tacos", Status: domain.SuggestionRejected,
+			},
+			sender:      usecase.Sender{ID: "author"},
+			approve:     true,
+			wantErr:     usecase.ErrSuggestionReviewed,
+			wantStatus:  domain.SuggestionRejected,
+			wantOptions: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, polls, suggestions := newSuggestionUsecase(domain.SuggestionsApproval, tt.suggestion)
+
+			reviewed, err := uc.ReviewSuggestion(context.Background(), "s1", tt.sender, tt.approve)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ReviewSuggestion() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (reviewed.Status != tt.wantStatus || reviewed.OptionID != tt.wantOptionID) {
+				t.Errorf("reviewed status %q, option %d, want %q, %d",
+					reviewed.Status, reviewed.OptionID, tt.wantStatus, tt.wantOptionID)
+			}
+			saved, err := suggestions.GetByID(context.Background(), "s1")
+			if err != nil {
+				t.Fatalf("GetByID() error: %v", err)
+			}
+			if saved.Status != tt.wantStatus {
+				t.Errorf("saved status %q, want %q", saved.Status, tt.wantStatus)
+			}
+			if options := len(polls.get("p1").Options); options != tt.wantOptions {
+				t.Errorf("poll has %d options, want %d", options, tt.wantOptions)
+			}
+		})
+	}
+}
@@ -0,0 +1,266 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/Xausdorf/mattermost-poll/internal/domain"
+)
+
+// CreateSurvey validates, sanitizes and saves the survey. Questions are limited like polls.
+func (p *Poll) CreateSurvey(ctx context.Context, survey *domain.Survey) error {
+	cfg := p.config()
+	if err := survey.Validate(cfg.PollLimits, cfg.MaxSurveyQuestions); err != nil {
+		return fmt.Errorf("invalid survey: %w", err)
+	}
+	survey.Sanitize()
+	if err := p.surveyRepo.Save(ctx, survey); err != nil {
+		return err
+	}
+	p.logger.InfoContext(ctx, "Survey created", "survey_id", survey.ID, "author", survey.Author,
+		"questions", len(survey.Questions))
+	return nil
+}
+
+// TakeSurvey starts the survey for the user or resumes the response in progress,
+// so the next answers of the user go to this survey.
+func (p *Poll) TakeSurvey(
+	ctx context.Context,
+	id string,
+	userID string,
+) (*domain.Survey, *domain.SurveyResponse, error) {
+	if userID == "" {
+		return nil, nil, ErrInvalidUserID
+	}
+	survey, err := p.surveyRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not retrieve survey: %w", err)
+	}
+	if !survey.IsActive {
+		return nil, nil, ErrSurveyIsNotActive
+	}
+
+	response, err := p.responseRepo.GetByUserAndSurvey(ctx, userID, id)
+	switch {
+	case errors.Is(err, ErrSurveyResponseNotFound):
+		response = domain.NewSurveyResponse(id, userID)
+	case err != nil:
+		return nil, nil, fmt.Errorf("could not get survey response: %w", err)
+	case response.Completed:
+		return nil, nil, ErrSurveyCompleted
+	default:
+		response.UpdatedAt = time.Now()
+	}
+	if err = p.responseRepo.Save(ctx, response); err != nil {
+		return nil, nil, fmt.Errorf("could not save survey response: %w", err)
+	}
+	p.logger.InfoContext(ctx, "Survey taken", "survey_id", id, "user_id", userID, "answered", len(response.Answers))
+	return survey, response, nil
+}
+
+// AnswerSurvey answers the next question of the survey the user has taken last, among active ones.
+// Answers to text questions are saved as TextAnswer, the response only marks the question as answered.
+// Returns ErrSurveyResponseNotFound if the user has no survey in progress.
+func (p *Poll) AnswerSurvey(
+	ctx context.Context,
+	userID string,
+	input string,
+) (*domain.Survey, *domain.SurveyResponse, error) {
+	survey, response, err := p.surveyInProgress(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	question, err := response.Answer(survey, input, p.config().MaxTextAnswerLength)
+	if err != nil {
+		return survey, response, err
+	}
+	var text string
+	if question.Type == domain.QuestionText {
+		text = response.Answers[question.ID].Text
+		response.Answers[question.ID] = domain.SurveyAnswer{}
+	}
+	// the response is saved first, so the answered question is not asked again and a text answer
+	// can't be saved twice if the user repeats it after a failure
+	if err = p.responseRepo.Save(ctx, response); err != nil {
+		return nil, nil, fmt.Errorf("could not save survey response: %w", err)
+	}
+	if question.Type == domain.QuestionText {
+		var answers []*domain.TextAnswer
+		if question.Anonymous {
+			if answers, err = p.textAnswerRepo.ListBySurvey(ctx, survey.ID); err != nil {
+				p.unanswer(ctx, response, question.ID)
+				return nil, nil, fmt.Errorf("could not list text answers: %w", err)
+			}
+		}
+		answer := domain.NewTextAnswer(survey.ID, question, userID, text, answers)
+		if err = p.textAnswerRepo.Save(ctx, answer); err != nil {
+			p.unanswer(ctx, response, question.ID)
+			return nil, nil, fmt.Errorf("could not save text answer: %w", err)
+		}
+	}
+	p.logger.InfoContext(ctx, "Survey answered", "survey_id", survey.ID, "user_id", userID,
+		"answered", len(response.Answers), "completed", response.Completed)
+	return survey, response, nil
+}
+
+// unanswer marks the question of the saved response as not answered again, so the user is asked it once more.
+func (p *Poll) unanswer(ctx context.Context, response *domain.SurveyResponse, questionID int) {
+	delete(response.Answers, questionID)
+	response.Completed = false
+	if err := p.responseRepo.Save(ctx, response); err != nil {
+		p.logger.WarnContext(ctx, "Could not revert survey answer", "survey_id", response.SurveyID,
+			"question_id", questionID, "error", err)
+	}
+}
+
+// SurveyInProgress returns the survey the user has taken last, among active ones, and the user's response.
+// Returns ErrSurveyResponseNotFound if the user has no survey in progress.
+func (p *Poll) SurveyInProgress(ctx context.Context, userID string) (*domain.Survey, *domain.SurveyResponse, error) {
+	return p.surveyInProgress(ctx, userID)
+}
+
+// surveyInProgress returns the response of the user which was updated last and its survey.
+// Responses to closed surveys are skipped, so they don't catch answers to other surveys.
+func (p *Poll) surveyInProgress(
+	ctx context.Context,
+	userID string,
+) (*domain.Survey, *domain.SurveyResponse, error) {
+	responses, err := p.responseRepo.ListInProgressByUser(ctx, userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not list survey responses: %w", err)
+	}
+	slices.SortFunc(responses, func(a, b *domain.SurveyResponse) int {
+		return b.UpdatedAt.Compare(a.UpdatedAt)
+	})
+	for _, response := range responses {
+		survey, err := p.surveyRepo.GetByID(ctx, response.SurveyID)
+		if errors.Is(err, ErrSurveyNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not retrieve survey: %w", err)
+		}
+		if survey.IsActive {
+			return survey, response, nil
+		}
+	}
+	return nil, nil, ErrSurveyResponseNotFound
+}
+
+// SurveyResults returns answers to every question of the survey, of text answers only published ones.
+// Only the author or an admin can get them, like text answers.
+func (p *Poll) SurveyResults(ctx context.Context, id string, sender Sender) (*domain.SurveyResults, error) {
+	survey, err := p.surveyRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve survey: %w", err)
+	}
+	if err = sender.authorize(ctx, survey.Author, survey.ChannelID); err != nil {
+		return nil, err
+	}
+	responses, err := p.responseRepo.ListBySurvey(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("could not list survey responses: %w", err)
+	}
+	textAnswers, err := p.textAnswerRepo.ListBySurvey(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("could not list text answers: %w", err)
+	}
+	return survey.Results(responses, textAnswers), nil
+}
+
+// TextAnswers returns all answers to the text question in the order they were given,
+// only for the author of the survey or an admin.
+func (p *Poll) TextAnswers(
+	ctx context.Context,
+	surveyID string,
+	questionID int,
+	sender Sender,
+) (*domain.Survey, []*domain.TextAnswer, error) {
+	survey, err := p.surveyRepo.GetByID(ctx, surveyID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not retrieve survey: %w", err)
+	}
+	if err = sender.authorize(ctx, survey.Author, survey.ChannelID); err != nil {
+		return nil, nil, err
+	}
+	if questionID < 0 || questionID >= len(survey.Questions) {
+		return nil, nil, ErrSurveyQuestionNotFound
+	}
+	if survey.Questions[questionID].Type != domain.QuestionText {
+		return nil, nil, ErrNotTextQuestion
+	}
+
+	answers, err := p.textAnswerRepo.ListBySurvey(ctx, surveyID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not list text answers: %w", err)
+	}
+	answers = slices.DeleteFunc(answers, func(answer *domain.TextAnswer) bool {
+		return answer.QuestionID != questionID
+	})
+	domain.SortTextAnswers(answers)
+	return survey, answers, nil
+}
+
+// PublishTextAnswers marks the answers as published, so they are shown in results of the survey.
+// Only for the author of the survey or an admin, every answer must belong to the survey.
+func (p *Poll) PublishTextAnswers(
+	ctx context.Context,
+	surveyID string,
+	sender Sender,
+	ids []string,
+) (*domain.Survey, []*domain.TextAnswer, error) {
+	survey, err := p.surveyRepo.GetByID(ctx, surveyID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not retrieve survey: %w", err)
+	}
+	if err = sender.authorize(ctx, survey.Author, survey.ChannelID); err != nil {
+		return nil, nil, err
+	}
+
+	answers := make([]*domain.TextAnswer, 0, len(ids))
+	for _, id := range ids {
+		answer, err := p.textAnswerRepo.GetByID(ctx, id)
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not get text answer: %w", err)
+		}
+		if answer.SurveyID != surveyID {
+			return nil, nil, ErrTextAnswerNotFound
+		}
+		answers = append(answers, answer)
+	}
+	for _, answer := range answers {
+		if answer.Published {
+			continue
+		}
+		answer.Published = true
+		if err = p.textAnswerRepo.Save(ctx, answer); err != nil {
+			return nil, nil, fmt.Errorf("could not save text answer: %w", err)
+		}
+	}
+	p.logger.InfoContext(ctx, "Text answers published", "survey_id", surveyID, "user_id", sender.ID,
+		"answers", len(answers))
+	return survey, answers, nil
+}
+
+// CloseSurvey stops accepting answers to the survey, only for the author or an admin.
+func (p *Poll) CloseSurvey(ctx context.Context, id string, sender Sender) error {
+	survey, err := p.surveyRepo.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("could not retrieve survey: %w", err)
+	}
+	if err = sender.authorize(ctx, survey.Author, survey.ChannelID); err != nil {
+		return err
+	}
+	if !survey.IsActive {
+		return ErrSurveyIsNotActive
+	}
+	survey.IsActive = false
+	if err = p.surveyRepo.Save(ctx, survey); err != nil {
+		return fmt.Errorf("could not save survey: %w", err)
+	}
+	p.logger.InfoContext(ctx, "Survey closed", "survey_id", id, "user_id", sender.ID)
+	return nil
+}
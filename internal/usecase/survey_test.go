@@ -0,0 +1,101 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Xausdorf/mattermost-poll/internal/domain"
+	"github.com/Xausdorf/mattermost-poll/internal/usecase"
+)
+
+func TestAnswerSurveyTextAnswer(t *testing.T) {
+	errSave := errors.New("save failed")
+	tests := []struct {
+		name       string
+		saveErr    error
+		wantTexts  int
+		wantAnswer bool
+	}{
+		{name: "saved", wantTexts: 1, wantAnswer: true},
+		{name: "text answer is not saved", saveErr: errSave, wantTexts: 0, wantAnswer: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			survey := domain.Survey{
+				ID:        "s1",
+				Author:    "author",
+				IsActive:  true,
+				Questions: []domain.SurveyQuestion{{ID: 0, Text: "Why?", Type: domain.QuestionText}},
+			}
+			responses := &responseRepository{}
+			textAnswers := &textAnswerRepository{err: tt.saveErr}
+			p := usecase.NewPoll(newPollRepository(), nil, nil, &eventRepository{}, nil,
+				newSurveyRepository(survey), responses, textAnswers, usecase.Config{}, discardLogger())
+			ctx := context.Background()
+
+			if _, _, err := p.TakeSurvey(ctx, "s1", "user"); err != nil {
+				t.Fatalf("TakeSurvey() error: %v", err)
+			}
+			_, response, err := p.AnswerSurvey(ctx, "user", "Because")
+			if !errors.Is(err, tt.saveErr) {
+				t.Fatalf("AnswerSurvey() error = %v, want %v", err, tt.saveErr)
+			}
+			if err == nil && response.Answers[0].Text != "" {
+				t.Errorf("response keeps text %q, want it in text answers only", response.Answers[0].Text)
+			}
+
+			if len(textAnswers.answers) != tt.wantTexts {
+				t.Errorf("%d text answers saved, want %d", len(textAnswers.answers), tt.wantTexts)
+			}
+			saved, err := responses.GetByUserAndSurvey(ctx, "user", "s1")
+			if err != nil {
+				t.Fatalf("GetByUserAndSurvey() error: %v", err)
+			}
+			if _, answered := saved.Answers[0]; answered != tt.wantAnswer || saved.Completed != tt.wantAnswer {
+				t.Errorf("question answered %v, completed %v, want %v", answered, saved.Completed, tt.wantAnswer)
+			}
+		})
+	}
+}
+
+func TestSurveyResultsAuthorization(t *testing.T) {
+	tests := []struct {
+		name    string
+		sender  usecase.Sender
+		wantErr error
+	}{
+		{name: "by author", sender: usecase.Sender{ID: "author"}},
+		{
+			name:   "by admin of the survey's channel",
+			sender: usecase.Sender{ID: "admin", Roles: roles{adminOf: []string{"c1"}}},
+		},
+		{
+			name:    "by admin of another channel",
+			sender:  usecase.Sender{ID: "admin", Roles: roles{adminOf: []string{"c2"}}},
+			wantErr: usecase.ErrUserIsNotPollAuthor,
+		},
+		{name: "by another user", sender: usecase.Sender{ID: "user"}, wantErr: usecase.ErrUserIsNotPollAuthor},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			survey := domain.Survey{
+				ID:        "s1",
+				Author:    "author",
+				ChannelID: "c1",
+				Questions: []domain.SurveyQuestion{{ID: 0, Text: "Why?", Type: domain.QuestionText}},
+			}
+			p := usecase.NewPoll(newPollRepository(), nil, nil, &eventRepository{}, nil,
+				newSurveyRepository(survey), &responseRepository{}, &textAnswerRepository{}, usecase.Config{},
+				discardLogger())
+
+			results, err := p.SurveyResults(context.Background(), "s1", tt.sender)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SurveyResults() error = %v, want %v", err, tt.wantErr)
+			}
+			if (results != nil) != (tt.wantErr == nil) {
+				t.Errorf("SurveyResults() = %v, want results only without error", results)
+			}
+		})
+	}
+}